@@ -18,8 +18,84 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/auth/login": {
+            "post": {
+                "description": "PIN salah berulang kali untuk staff yang sama atau dari IP yang sama mengunci login sementara (429).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login staff menggunakan ID dan PIN",
+                "parameters": [
+                    {
+                        "description": "Kredensial staff",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/bills": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
@@ -49,6 +125,12 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order settled, void atau merged, atau yang sudah punya bill aktif, ditolak (409).",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,6 +169,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -99,8 +199,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/bills/pay": {
+        "/bills/merge": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order bill pertama menjadi order tujuan, order lain di-merge ke sana lalu bill asal di-void dan diganti satu bill baru dengan diskon gabungan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,21 +216,27 @@ const docTemplate = `{
                 "tags": [
                     "Bills"
                 ],
-                "summary": "Proses pembayaran tagihan",
+                "summary": "Gabungkan bill open dari beberapa order/meja menjadi satu bill",
                 "parameters": [
                     {
-                        "description": "Data pembayaran",
+                        "description": "Bill yang digabung",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.BillPaymentRequest"
+                            "$ref": "#/definitions/handlers.MergeBillsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -132,8 +244,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -153,8 +274,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/bills/split": {
+        "/bills/pay": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hanya bill open/partial. Cash boleh melebihi sisa tagihan (selisih jadi kembalian), metode lain maksimal sebesar sisa tagihan. Tip dicatat terpisah.\nroom_charge: reference_number berisi nomor kamar (default hotel_room order), divalidasi dan diposting ke PMS jika integrasi aktif.\nPembayaran tercatat ke sesi laci kas kasir yang terbuka di outlet bill, cash ditolak (409) jika kasir belum membuka laci.",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,24 +291,23 @@ const docTemplate = `{
                 "tags": [
                     "Bills"
                 ],
-                "summary": "Buat tagihan split dari satu order",
+                "summary": "Proses pembayaran tagihan",
                 "parameters": [
                     {
-                        "description": "Data split bill",
+                        "description": "Data pembayaran",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SplitBillRequest"
+                            "$ref": "#/definitions/handlers.BillPaymentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.PaymentReceipt"
                         }
                     },
                     "400": {
@@ -193,6 +319,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -201,33 +345,54 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/bills/{id}": {
+        "/bills/preview": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Memakai aturan pajak, service charge dan pembulatan yang sama dengan pembuatan bill",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Bills"
                 ],
-                "summary": "Ambil tagihan berdasarkan ID",
+                "summary": "Hitung rincian tagihan order tanpa membuat bill",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID tagihan",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID order",
+                        "name": "order_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Diskon",
+                        "name": "discount_amount",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Bill"
+                            "$ref": "#/definitions/pricing.Breakdown"
                         }
                     },
                     "400": {
@@ -258,27 +423,82 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bills/refund": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Wajib disetujui manager dengan PIN. Dikembalikan lewat metode pembayaran asal (room_charge = kredit ke folio kamar), tagihan bersih bill berkurang dan bill lunas yang seluruhnya dikembalikan menjadi refunded.\nRefund cash dibayarkan dari laci kas yang sedang dibuka staf di outlet bill.\nRefund atas bill partial hanya membatalkan pembayaran (is_reversal): sisa tagihan terbuka lagi dan tidak dihitung refund penjualan.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Bills"
                 ],
-                "summary": "Soft delete tagihan",
+                "summary": "Refund sebagian atau seluruh satu pembayaran",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID tagihan",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Data refund",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefundRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.BillRefund"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -298,27 +518,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/customers": {
+        "/bills/room-guest/{room}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Bills"
+                ],
+                "summary": "Cek tamu kamar di PMS sebelum room charge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nomor kamar",
+                        "name": "room",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Ambil semua customer",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Customer"
+                            "$ref": "#/definitions/pms.Guest"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -327,8 +567,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bills/split": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mode: items (item utuh), quantity (qty item dibagi), seat (per kursi, hidangan bersama dibagi rata), even (dibagi rata ke N tamu). Seluruh item aktif order harus terbagi. Bill asal (original_bill_id) harus open dan belum dibayar, diskonnya dibagi ke bill split jika split tidak menyebut diskon. Tanpa bill asal, order tidak boleh sudah punya bill aktif.",
                 "consumes": [
                     "application/json"
                 ],
@@ -336,17 +584,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Bills"
                 ],
-                "summary": "Tambah customer baru",
+                "summary": "Buat tagihan split dari satu order",
                 "parameters": [
                     {
-                        "description": "Data customer baru",
+                        "description": "Data split bill",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CustomerRequest"
+                            "$ref": "#/definitions/models.SplitBillRequest"
                         }
                     }
                 ],
@@ -367,6 +615,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -379,19 +645,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/customers/{id}": {
+        "/bills/{id}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Bills"
                 ],
-                "summary": "Ambil customer berdasarkan ID",
+                "summary": "Ambil tagihan berdasarkan ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Customer",
+                        "description": "ID tagihan",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -401,7 +672,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Customer"
+                            "$ref": "#/definitions/models.Bill"
                         }
                     },
                     "400": {
@@ -413,6 +684,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -424,33 +704,27 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Hanya bill open yang belum ada pembayaran maupun refund. Bill split (induk atau pecahannya) tidak bisa di-void.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer"
+                    "Bills"
                 ],
-                "summary": "Update data customer",
+                "summary": "Soft delete tagihan",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Customer",
+                        "description": "ID tagihan",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CustomerRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -472,6 +746,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -482,32 +774,57 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bills/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Format escpos (byte stream printer thermal), html atau pdf. Setiap cetak dicatat, cetakan berikutnya ditandai CETAK ULANG. preview=true tidak dicatat dan ditandai PREVIEW / BUKAN STRUK.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream",
+                    "text/html",
+                    "application/pdf"
                 ],
                 "tags": [
-                    "Customer"
+                    "Bills"
                 ],
-                "summary": "Hapus customer (soft delete)",
+                "summary": "Cetak struk bill",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Customer",
+                        "description": "ID bill",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "escpos, html atau pdf (default html)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lebar kertas dalam karakter, 32 (58mm) sampai 64, default 42 (80mm)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan tanpa mencatat cetak",
+                        "name": "preview",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -519,36 +836,14 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/ingredients": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Ingredient"
-                ],
-                "summary": "Tampilkan semua ingredient",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Ingredient"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -560,35 +855,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/bills/{id}/refunds": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ingredient"
+                    "Bills"
                 ],
-                "summary": "Tambah ingredient baru",
+                "summary": "Daftar refund satu bill",
                 "parameters": [
                     {
-                        "description": "Data ingredient baru",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateIngredientRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID bill",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BillRefund"
+                            }
                         }
                     },
                     "400": {
@@ -612,29 +911,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/ingredients/{id}": {
+        "/cash-drawers": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ingredient"
+                    "Cash Drawer"
                 ],
-                "summary": "Ambil ingredient berdasarkan ID",
+                "summary": "Daftar sesi laci kas",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Ingredient",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter outlet",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open atau closed",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Ingredient"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CashDrawerSession"
+                            }
                         }
                     },
                     "400": {
@@ -656,36 +968,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/cash-drawers/current": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tanpa angka penjualan agar hitung saat tutup sesi tetap buta",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ingredient"
-                ],
-                "summary": "Hapus (soft delete) ingredient berdasarkan ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID Ingredient",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Cash Drawer"
                 ],
+                "summary": "Sesi laci kas kasir yang sedang terbuka",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.CashDrawerSession"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -705,8 +1013,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu-ingredients": {
+        "/cash-drawers/open": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kasir membuka laci dengan modal awal. Selama sesi terbuka semua pembayaran dan refund yang diterima kasir di outlet tersebut tercatat ke sesi, pembayaran cash wajib lewat sesi.",
                 "consumes": [
                     "application/json"
                 ],
@@ -714,32 +1028,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MenuIngredient"
+                    "Cash Drawer"
                 ],
-                "summary": "Tambah menu ingredient baru",
+                "summary": "Buka sesi laci kas (mulai shift kasir)",
                 "parameters": [
                     {
-                        "description": "Data menu ingredient",
+                        "description": "Outlet dan modal awal",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateMenuIngredientRequest"
+                            "$ref": "#/definitions/handlers.OpenDrawerRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CashDrawerSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -759,8 +1079,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu-ingredients/{id}": {
-            "put": {
+        "/cash-drawers/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kasir mengisi jumlah fisik per metode (cash, slip kartu, voucher) tanpa melihat angka sistem. Metode yang tidak diisi dihitung 0. Hasilnya laporan Z dengan selisih lebih/kurang per metode.",
                 "consumes": [
                     "application/json"
                 ],
@@ -768,24 +1094,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "MenuIngredient"
+                    "Cash Drawer"
                 ],
-                "summary": "Perbarui data menu ingredient",
+                "summary": "Tutup sesi laci kas dengan hitung buta",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID menu ingredient",
+                        "description": "ID sesi",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data yang diperbarui",
+                        "description": "Hasil hitung",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateMenuIngredientRequest"
+                            "$ref": "#/definitions/handlers.CloseDrawerRequest"
                         }
                     }
                 ],
@@ -793,10 +1119,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.DrawerReport"
                         }
                     },
                     "400": {
@@ -808,37 +1131,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "delete": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "MenuIngredient"
-                ],
-                "summary": "Hapus menu ingredient berdasarkan ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID menu ingredient",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -846,8 +1149,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -867,20 +1170,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu-ingredients/{menu_item_id}": {
+        "/cash-drawers/{id}/report": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sesi terbuka menghasilkan laporan X (posisi sementara), sesi yang sudah ditutup laporan Z beserta hasil hitung dan selisihnya",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "MenuIngredient"
+                    "Cash Drawer"
                 ],
-                "summary": "Tampilkan semua ingredient berdasarkan menu item",
+                "summary": "Laporan X/Z satu sesi laci kas",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Menu Item",
-                        "name": "menu_item_id",
+                        "description": "ID sesi",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -889,10 +1198,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MenuIngredient"
-                            }
+                            "$ref": "#/definitions/models.DrawerReport"
                         }
                     },
                     "400": {
@@ -904,6 +1210,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -916,22 +1231,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/category": {
+        "/combos": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Combos"
+                ],
+                "summary": "Tampilkan semua paket beserta slot dan pilihannya",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Hanya paket aktif",
+                        "name": "active",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Tampilkan semua kategori menu",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.MenuCategory"
+                                "$ref": "#/definitions/models.Combo"
                             }
                         }
                     },
@@ -947,6 +1275,12 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Slot dengan satu pilihan menjadi komponen tetap, slot dengan beberapa pilihan dipilih saat order",
                 "consumes": [
                     "application/json"
                 ],
@@ -954,17 +1288,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Combos"
                 ],
-                "summary": "Tambah kategori menu baru",
+                "summary": "Tambah paket / set menu",
                 "parameters": [
                     {
-                        "description": "Data kategori baru",
+                        "description": "Data paket",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateCategoryRequest"
+                            "$ref": "#/definitions/handlers.ComboRequest"
                         }
                     }
                 ],
@@ -973,7 +1307,9 @@ const docTemplate = `{
                         "description": "Created",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
@@ -997,19 +1333,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/category/{id}": {
-            "delete": {
+        "/combos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Category"
+                    "Combos"
                 ],
-                "summary": "Hapus (soft delete) kategori menu",
+                "summary": "Detail paket",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Kategori",
+                        "description": "ID paket",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1018,6 +1359,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Combo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1025,8 +1372,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1044,24 +1391,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/menu/menu-items": {
-            "get": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Combos"
+                ],
+                "summary": "Ubah paket (slot dan pilihan diganti seluruhnya)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID paket",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data paket",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ComboRequest"
+                        }
+                    }
                 ],
-                "summary": "List semua menu",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MenuItem"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1076,34 +1465,36 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu-Items"
+                    "Combos"
                 ],
-                "summary": "Tambah menu baru",
+                "summary": "Hapus paket (soft delete)",
                 "parameters": [
                     {
-                        "description": "Data menu baru",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateMenuItemRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID paket",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1115,6 +1506,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1127,22 +1527,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/menu-items-active": {
+        "/customers": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Customer"
                 ],
-                "summary": "List menu aktif",
+                "summary": "Ambil semua customer",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.MenuItem"
+                                "$ref": "#/definitions/models.Customer"
                             }
                         }
                     },
@@ -1156,34 +1561,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/menu/menu-items/category": {
-            "get": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Customer"
                 ],
-                "summary": "Dapatkan menu berdasarkan kategori",
+                "summary": "Tambah customer baru",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Kategori",
-                        "name": "category_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Data customer baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CustomerRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MenuItem"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1207,19 +1618,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/menu/menu-items/detail/{id}": {
+        "/customers/{id}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Customer"
                 ],
-                "summary": "Dapatkan detail menu dengan bahan",
+                "summary": "Ambil customer berdasarkan ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Menu",
+                        "description": "ID Customer",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1229,7 +1645,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MenuItemWithIngredients"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1241,15 +1657,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1260,33 +1667,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/menu/menu-items/search": {
-            "get": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Customer"
                 ],
-                "summary": "Cari menu berdasarkan keyword",
+                "summary": "Update data customer",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Kata kunci pencarian",
-                        "name": "search",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "ID Customer",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Data yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CustomerRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MenuItem"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1309,36 +1731,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/menu/menu-items/{id}": {
-            "put": {
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Customer"
                 ],
-                "summary": "Perbarui menu",
+                "summary": "Hapus customer (soft delete)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Menu",
+                        "description": "ID Customer",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data menu yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateMenuItemRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -1370,32 +1783,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Event: order.created, order.item_added, order.item_voided, order.status_changed, table.status_changed, table.transferred, bill.paid, kitchen.item_bumped",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Menu"
+                    "Events"
                 ],
-                "summary": "Hapus (soft delete) menu",
+                "summary": "Stream event real-time (Server-Sent Events)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Menu",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Hanya event dari outlet ini",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token sesi untuk EventSource yang tidak bisa mengirim header Authorization",
+                        "name": "access_token",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/events.Event"
                         }
                     },
                     "400": {
@@ -1406,35 +1829,31 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/orders": {
+        "/ingredients": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Ingredient"
                 ],
-                "summary": "Ambil semua order",
+                "summary": "Tampilkan semua ingredient",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Order"
+                                "$ref": "#/definitions/models.Ingredient"
                             }
                         }
                     },
@@ -1450,6 +1869,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1457,17 +1881,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Ingredient"
                 ],
-                "summary": "Buat order baru",
+                "summary": "Tambah ingredient baru",
                 "parameters": [
                     {
-                        "description": "Data order baru",
+                        "description": "Data ingredient baru",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.NewOrderRequest"
+                            "$ref": "#/definitions/handlers.CreateIngredientRequest"
                         }
                     }
                 ],
@@ -1500,19 +1924,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}": {
+        "/ingredients/{id}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Ingredient"
                 ],
-                "summary": "Ambil order berdasarkan ID",
+                "summary": "Ambil ingredient berdasarkan ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID order",
+                        "description": "ID Ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1522,7 +1951,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Order"
+                            "$ref": "#/definitions/models.Ingredient"
                         }
                     },
                     "400": {
@@ -1534,8 +1963,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1545,33 +1974,26 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Ingredient"
                 ],
-                "summary": "Perbarui data order",
+                "summary": "Hapus (soft delete) ingredient berdasarkan ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID order",
+                        "description": "ID Ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data order yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.NewOrderRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -1603,31 +2025,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/ingredients/{id}/movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Stock Movements"
                 ],
-                "summary": "Soft delete order (status menjadi void)",
+                "summary": "Riwayat pergerakan stok sebuah ingredient",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID order",
+                        "description": "ID Ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal (YYYY-MM-DD), default 30 hari terakhir",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD), inklusif, default hari ini",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockMovement"
                             }
                         }
                     },
@@ -1650,10 +2091,13 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}/add": {
+            },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1661,35 +2105,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Stock Movements"
                 ],
-                "summary": "Tambahkan item ke order",
+                "summary": "Catat pergerakan stok manual (receiving, waste, adjustment)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID order",
+                        "description": "ID Ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data item baru",
+                        "description": "Data pergerakan stok",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AddOrderItemRequest"
+                            "$ref": "#/definitions/handlers.CreateStockMovementRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1713,22 +2155,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/outlets": {
+        "/ingredients/{id}/reconcile": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Outlet"
+                    "Stock Movements"
+                ],
+                "summary": "Bandingkan qty ingredient saat ini dengan total ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Ingredient",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Tampilkan semua outlet",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Outlet"
+                            "$ref": "#/definitions/models.StockReconciliation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1742,35 +2213,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/kitchen/items/{id}/bump": {
             "post": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Item di tiket yang masih ditahan, item yang di-void atau item order void ditolak (409).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Outlet"
+                    "Kitchen"
                 ],
-                "summary": "Tambah outlet baru",
+                "summary": "Majukan status item dapur (queued -\u003e cooking -\u003e ready -\u003e served)",
                 "parameters": [
                     {
-                        "description": "Data outlet",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateOutletRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID ticket item",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1782,6 +2258,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1794,29 +2288,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/outlets/{id}": {
+        "/kitchen/print-jobs": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Outlet"
+                    "Kitchen"
                 ],
-                "summary": "Ambil data outlet berdasarkan ID",
+                "summary": "Antrian cetak tiket dapur",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, printing, printed atau failed",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID outlet",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter outlet",
+                        "name": "outlet_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Outlet"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KitchenPrintJob"
+                            }
                         }
                     },
                     "400": {
@@ -1828,15 +2335,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1847,34 +2345,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/kitchen/print-jobs/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Outlet"
+                    "Kitchen"
                 ],
-                "summary": "Perbarui outlet",
+                "summary": "Antrikan ulang job cetak yang gagal",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID outlet",
+                        "description": "ID job cetak",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data outlet yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateOutletRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -1896,6 +2389,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1906,31 +2417,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/kitchen/queue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Outlet"
+                    "Kitchen"
                 ],
-                "summary": "Hapus outlet (soft delete)",
+                "summary": "Antrian item dapur (KDS) yang belum served",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID outlet",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter station",
+                        "name": "station_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter outlet",
+                        "name": "outlet_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KitchenQueueItem"
                             }
                         }
                     },
@@ -1955,30 +2478,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservations": {
+        "/kitchen/stations": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reservations"
-                ],
-                "summary": "Ambil semua reservasi",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Kolom untuk sorting (default: reservation_time)",
-                        "name": "sort",
-                        "in": "query"
-                    }
+                    "Kitchen"
                 ],
+                "summary": "Tampilkan semua station dapur",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Reservation"
+                                "$ref": "#/definitions/models.KitchenStation"
                             }
                         }
                     },
@@ -1994,6 +2514,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -2001,17 +2526,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reservations"
+                    "Kitchen"
                 ],
-                "summary": "Tambah reservasi baru",
+                "summary": "Tambah station dapur",
                 "parameters": [
                     {
-                        "description": "Data reservasi",
+                        "description": "Data station",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateReservationRequest"
+                            "$ref": "#/definitions/handlers.CreateStationRequest"
                         }
                     }
                 ],
@@ -2032,15 +2557,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Meja sudah dipesan",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2053,29 +2569,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservations/{id}": {
-            "get": {
+        "/kitchen/stations/{id}/categories": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reservations"
+                    "Kitchen"
                 ],
-                "summary": "Ambil detail reservasi berdasarkan ID",
+                "summary": "Arahkan kategori menu ke station dapur",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID reservasi",
+                        "description": "ID Station",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Daftar kategori",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AssignCategoriesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -2097,8 +2633,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/kitchen/stations/{id}/printer": {
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Nama printer harus terdaftar di POS_PRINTERS, kosongkan printer untuk berhenti mencetak",
                 "consumes": [
                     "application/json"
                 ],
@@ -2106,24 +2650,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reservations"
+                    "Kitchen"
                 ],
-                "summary": "Update data reservasi",
+                "summary": "Atur printer tiket kertas untuk station dapur",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID reservasi",
+                        "description": "ID Station",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data reservasi baru",
+                        "description": "Printer station",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateReservationRequest"
+                            "$ref": "#/definitions/handlers.StationPrinterRequest"
                         }
                     }
                 ],
@@ -2146,6 +2690,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2156,27 +2709,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/kitchen/tickets/{id}/reprint": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reservations"
+                    "Kitchen"
                 ],
-                "summary": "Hapus reservasi berdasarkan ID",
+                "summary": "Cetak ulang tiket dapur ke printer station",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID reservasi",
+                        "description": "ID tiket dapur",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2193,34 +2753,21 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/staff": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Staff"
-                ],
-                "summary": "Lihat semua staff",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Staff"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -2234,8 +2781,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/menu-ingredients": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -2243,17 +2797,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "MenuIngredient"
                 ],
-                "summary": "Tambah staff baru",
+                "summary": "Tambah menu ingredient baru",
                 "parameters": [
                     {
-                        "description": "Data staff",
+                        "description": "Data menu ingredient",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.StaffRequest"
+                            "$ref": "#/definitions/handlers.CreateMenuIngredientRequest"
                         }
                     }
                 ],
@@ -2262,7 +2816,9 @@ const docTemplate = `{
                         "description": "Created",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
@@ -2286,29 +2842,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff/{id}": {
-            "get": {
-                "produces": [
-                    "application/json"
+        "/menu-ingredients/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "MenuIngredient"
                 ],
-                "summary": "Lihat staff berdasarkan ID",
+                "summary": "Perbarui data menu ingredient",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID staff",
+                        "description": "ID menu ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Data yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateMenuIngredientRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -2320,8 +2896,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2331,33 +2907,26 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "MenuIngredient"
                 ],
-                "summary": "Perbarui data staff",
+                "summary": "Hapus menu ingredient berdasarkan ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID staff",
+                        "description": "ID menu ingredient",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data staff yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.StaffRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -2389,20 +2958,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/menu-ingredients/{menu_item_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "MenuIngredient"
                 ],
-                "summary": "Hapus (soft delete) staff",
+                "summary": "Tampilkan semua ingredient berdasarkan menu item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID staff",
-                        "name": "id",
+                        "description": "ID Menu Item",
+                        "name": "menu_item_id",
                         "in": "path",
                         "required": true
                     }
@@ -2411,9 +2987,9 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MenuIngredient"
                             }
                         }
                     },
@@ -2438,22 +3014,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/table-transfers": {
+        "/menu/category": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table Transfers"
+                    "Category"
                 ],
-                "summary": "Ambil semua data pemindahan meja",
+                "summary": "Tampilkan semua kategori menu",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.TableTransfer"
+                                "$ref": "#/definitions/models.MenuCategory"
                             }
                         }
                     },
@@ -2469,6 +3050,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -2476,17 +3062,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table Transfers"
+                    "Category"
                 ],
-                "summary": "Buat data pemindahan meja",
+                "summary": "Tambah kategori menu baru",
                 "parameters": [
                     {
-                        "description": "Data pemindahan meja",
+                        "description": "Data kategori baru",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CreateTableTransferRequest"
+                            "$ref": "#/definitions/handlers.CreateCategoryRequest"
                         }
                     }
                 ],
@@ -2519,69 +3105,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/table-transfers/{id}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Table Transfers"
-                ],
-                "summary": "Ambil data pemindahan meja berdasarkan ID",
-                "parameters": [
+        "/menu/category/{id}": {
+            "delete": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "ID pemindahan meja",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.TableTransfer"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                        "BearerAuth": []
                     }
-                }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table Transfers"
+                    "Category"
                 ],
-                "summary": "Perbarui data pemindahan meja",
+                "summary": "Hapus (soft delete) kategori menu",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID pemindahan meja",
+                        "description": "ID Kategori",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data pemindahan meja yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateTableTransferRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -2613,62 +3157,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Table Transfers"
-                ],
-                "summary": "Hapus data pemindahan meja berdasarkan ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID pemindahan meja",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
             }
         },
-        "/tables": {
+        "/menu/menu-items": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Lihat semua data meja",
+                "summary": "List semua menu",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Table"
+                                "$ref": "#/definitions/models.MenuItem"
                             }
                         }
                     },
@@ -2684,7 +3195,11 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Menambahkan data meja baru ke dalam sistem",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -2692,17 +3207,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu-Items"
                 ],
-                "summary": "Tambah meja baru",
+                "summary": "Tambah menu baru",
                 "parameters": [
                     {
-                        "description": "Data meja",
+                        "description": "Data menu baru",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.newTableRequest"
+                            "$ref": "#/definitions/handlers.CreateMenuItemRequest"
                         }
                     }
                 ],
@@ -2735,37 +3250,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/tables/{id}": {
+        "/menu/menu-items-active": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
-                ],
-                "summary": "Lihat detail meja berdasarkan ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID meja",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Menu"
                 ],
+                "summary": "List menu aktif",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Table"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MenuItem"
                             }
                         }
                     },
@@ -2779,43 +3284,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/menu/menu-items/category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Update data meja",
+                "summary": "Dapatkan menu berdasarkan kategori",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID meja",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID Kategori",
+                        "name": "category_id",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Data meja yang diperbarui",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.newTableRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MenuItem"
                             }
                         }
                     },
@@ -2838,19 +3338,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/menu/menu-items/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Hapus (soft delete) data meja",
+                "summary": "Dapatkan detail menu dengan bahan dan modifier",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID meja",
+                        "description": "ID Menu",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2859,6 +3366,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuItemWithIngredients"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2866,8 +3379,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2887,22 +3400,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits": {
+        "/menu/menu-items/search": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer Visit"
+                    "Menu"
+                ],
+                "summary": "Cari menu berdasarkan keyword",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kata kunci pencarian",
+                        "name": "search",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Ambil semua data kunjungan customer",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.CustomerVisit"
+                                "$ref": "#/definitions/models.MenuItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -2916,8 +3452,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/menu/menu-items/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -2925,26 +3468,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer Visit"
+                    "Menu"
                 ],
-                "summary": "Tambah data kunjungan customer",
+                "summary": "Perbarui menu",
                 "parameters": [
                     {
-                        "description": "Data kunjungan customer",
+                        "type": "integer",
+                        "description": "ID Menu",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data menu yang diperbarui",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CustomerVisitRequest"
+                            "$ref": "#/definitions/handlers.CreateMenuItemRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -2966,21 +3518,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/visits/{id}": {
-            "get": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer Visit"
+                    "Menu"
                 ],
-                "summary": "Ambil detail kunjungan berdasarkan ID",
+                "summary": "Hapus (soft delete) menu",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Kunjungan",
+                        "description": "ID Menu",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2990,11 +3545,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CustomerVisit"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -3003,8 +3570,74 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/modifiers/groups": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifiers"
+                ],
+                "summary": "Tambah modifier group ke menu (ukuran, kematangan, extra topping)",
+                "parameters": [
+                    {
+                        "description": "Data modifier group",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ModifierGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/modifiers/groups/{id}": {
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -3012,24 +3645,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Customer Visit"
+                    "Modifiers"
                 ],
-                "summary": "Update data kunjungan customer",
+                "summary": "Ubah nama dan aturan min/max modifier group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Kunjungan",
+                        "description": "ID modifier group",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data yang diperbarui",
+                        "description": "Data modifier group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CustomerVisitRequest"
+                            "$ref": "#/definitions/handlers.ModifierGroupRequest"
                         }
                     }
                 ],
@@ -3052,6 +3685,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3064,17 +3706,22 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Customer Visit"
+                    "Modifiers"
                 ],
-                "summary": "Hapus data kunjungan customer",
+                "summary": "Hapus modifier group (soft delete)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Kunjungan",
+                        "description": "ID modifier group",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3090,6 +3737,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3101,93 +3766,5861 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.BillPaymentRequest": {
-            "type": "object",
-            "required": [
-                "amount",
-                "bill_id",
-                "payment_method"
-            ],
-            "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "bill_id": {
-                    "type": "integer"
-                },
-                "payment_method": {
-                    "type": "string"
-                },
-                "reference_number": {
-                    "type": "string"
-                },
-                "room_charge_approved_by": {
+        },
+        "/modifiers/groups/{id}/options": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifiers"
+                ],
+                "summary": "Tambah pilihan ke modifier group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID modifier group",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data pilihan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ModifierOptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/modifiers/menu-items/{menu_item_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifiers"
+                ],
+                "summary": "Tampilkan modifier group beserta pilihannya untuk satu menu",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Menu Item",
+                        "name": "menu_item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ModifierGroup"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/modifiers/options/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifiers"
+                ],
+                "summary": "Ubah pilihan modifier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID modifier option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data pilihan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ModifierOptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifiers"
+                ],
+                "summary": "Hapus pilihan modifier (soft delete)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID modifier option",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Ambil semua order",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Order"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Harga item diambil dari menu, override harga wajib disetujui manager/supervisor (approved_by + approver_pin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Buat order baru",
+                "parameters": [
+                    {
+                        "description": "Data order baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NewOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Item, paket dan tiket dapur order sumber dipindah ke order tujuan, bill sumber maupun tujuan yang belum dibayar di-void (buat bill baru setelah merge), order sumber menjadi merged dan meja sumber yang kosong kembali available.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Gabungkan beberapa order open ke satu order (rombongan tamu bergabung)",
+                "parameters": [
+                    {
+                        "description": "Order tujuan dan order sumber",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeOrdersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Ambil order berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hanya order open/transferred. Perubahan status (kecuali void) disimpan bersama data order dalam satu transaksi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Perbarui data order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data order yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NewOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bill order yang belum dibayar ikut di-void. Ditolak (409) jika masih ada bill yang memegang pembayaran, refund lebih dulu.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Void order (status menjadi void) dan kembalikan stok bahan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Makanan sudah dibuat, stok dicatat sebagai waste",
+                        "name": "prepared",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alasan void",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/add": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order harus open/transferred dan belum punya bill aktif (void bill lebih dulu)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Tambahkan item ke order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data item baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddOrderItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/courses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Status course order (held, fired, served)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrderCourse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/fire": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tiket dapur yang ditahan masuk antrian KDS dan dicetak di printer station-nya.\nIsi course untuk melepas satu course (appetizer/main/dessert) order dine-in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Kirim item yang ditahan (hold) ke dapur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tiket yang di-fire",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FireRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Riwayat perubahan status order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrderStatusChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/items/{item_id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order harus open/transferred dan belum punya bill aktif (void bill lebih dulu)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Void satu item order dan kembalikan stok bahan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID order item",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Opsi void",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VoidItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/merges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Jejak merge order (sebagai tujuan maupun sumber)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrderMerge"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "open/transferred -\u003e settled, hanya jika semua bill lunas. Void lewat DELETE /orders/{id}, transferred lewat transfer meja.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Ubah status order sesuai state machine",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status tujuan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ChangeOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/outlets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Tampilkan semua outlet",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Outlet"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Tambah outlet baru",
+                "parameters": [
+                    {
+                        "description": "Data outlet",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateOutletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/outlets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Ambil data outlet berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outlet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Perbarui outlet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data outlet yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateOutletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Hapus outlet (soft delete)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/outlets/{id}/category-taxes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kategori yang tidak tercantum memakai tax_percentage outlet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Tampilkan tarif pajak khusus per kategori di outlet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryTaxRate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Atur tarif pajak khusus per kategori di outlet (mengganti seluruh daftar)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Daftar tarif pajak kategori",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CategoryTaxRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/outlets/{id}/dining-periods": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Tampilkan periode makan outlet (jam reservasi dan lama makan)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DiningPeriod"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Setiap periode (breakfast, lunch, dinner, event) punya slot reservasi pertama \u0026 terakhir (HH:MM) dan lama makan dalam menit yang dipakai untuk cek bentrok reservasi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Outlet"
+                ],
+                "summary": "Atur periode makan outlet (mengganti seluruh daftar)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Daftar periode makan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DiningPeriodRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/course-times": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rata-rata dan maksimum menit dari course di-fire sampai semua itemnya served, serta rata-rata menit dari order dibuka sampai course di-fire",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Waktu layanan per course (fire sampai served)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter outlet",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal (YYYY-MM-DD), default 30 hari lalu",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD), default hari ini",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CourseServiceTime"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sales-daily": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Ringkasan penjualan harian per outlet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter outlet",
+                        "name": "outlet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal (YYYY-MM-DD), default 30 hari lalu",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD), default hari ini",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SalesAnalysisDaily"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sales-daily/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Default hanya hari ini. Aman dijalankan berulang (upsert per outlet \u0026 tanggal)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Hitung ulang ringkasan penjualan harian (manual)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/x-report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Posisi semua laci kas yang masih terbuka di outlet tanpa menutup sesi",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Laporan X outlet (tengah shift)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OutletDrawerReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/z-report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gabungan laporan Z semua sesi laci kas outlet yang ditutup pada tanggal tersebut, termasuk selisih lebih/kurang per metode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Laporan Z outlet (akhir shift)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal tutup sesi (YYYY-MM-DD), default hari ini",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OutletDrawerReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Ambil semua reservasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kolom untuk sorting (default: reservation_time)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lama makan diambil dari periode makan outlet (visit_type atau jam reservasi). Tanpa table_id, meja terkecil yang cukup dipilih otomatis, atau gabungan meja bersebelahan jika tidak ada. Reservasi confirmed/seated ditolak jika bentrok dengan reservasi lain di meja yang sama.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Tambah reservasi baru",
+                "parameters": [
+                    {
+                        "description": "Data reservasi",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Meja sudah dipesan / tidak ada meja kosong",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Slot per 15 menit dari first_seating sampai last_seating setiap periode makan outlet yang masih punya meja (atau gabungan meja bersebelahan) untuk jumlah tamu, beserta meja yang disarankan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Cari slot reservasi yang masih tersedia",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah tamu",
+                        "name": "pax",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "breakfast, lunch, dinner atau event",
+                        "name": "visit_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AvailabilitySlot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Periode makan outlet belum diatur",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Ambil detail reservasi berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID reservasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Update data reservasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID reservasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data reservasi baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Hapus reservasi berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID reservasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/seat": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reservasi confirmed, waiting atau no_show (tamu terlambat) menjadi seated. Tanpa table_id dipakai meja reservasi confirmed, atau meja kosong yang dipilih otomatis. Kunjungan pelanggan (terhubung ke reservasi) dan order dine-in langsung dibuka.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Dudukkan tamu reservasi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID reservasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meja dan waiter",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SeatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/staff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Lihat semua staff",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Staff"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Tambah staff baru",
+                "parameters": [
+                    {
+                        "description": "Data staff",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StaffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Lihat staff berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID staff",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Perbarui data staff",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID staff",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data staff yang diperbarui, pin_code kosong = PIN tidak diubah",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateStaffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Hapus (soft delete) staff",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID staff",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/table-transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table Transfers"
+                ],
+                "summary": "Ambil semua data pemindahan meja",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TableTransfer"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hanya order open/transferred, order open berubah status menjadi transferred",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table Transfers"
+                ],
+                "summary": "Buat data pemindahan meja",
+                "parameters": [
+                    {
+                        "description": "Data pemindahan meja",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateTableTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/table-transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table Transfers"
+                ],
+                "summary": "Ambil data pemindahan meja berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID pemindahan meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TableTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table Transfers"
+                ],
+                "summary": "Perbarui data pemindahan meja",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID pemindahan meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data pemindahan meja yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateTableTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table Transfers"
+                ],
+                "summary": "Hapus data pemindahan meja berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID pemindahan meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tables": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Lihat semua data meja",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Table"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menambahkan data meja baru ke dalam sistem. Status hanya bisa diisi out_of_order, selain itu dihitung otomatis dari order dan reservasi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Tambah meja baru",
+                "parameters": [
+                    {
+                        "description": "Data meja",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.newTableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/floor": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Setiap meja dengan status terkini, order yang sedang berjalan (waiter, jumlah item, total, sudah dibayar), lama duduk sejak order pertama, total berjalan dan reservasi berikutnya hari ini",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Tampilan denah meja outlet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter area",
+                        "name": "section",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FloorTable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/layout": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menyimpan area, bentuk, posisi dan meja sebelah (yang bisa digabung untuk reservasi rombongan besar) beberapa meja sekaligus. Semua meja harus milik outlet tersebut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Simpan denah meja outlet",
+                "parameters": [
+                    {
+                        "description": "Posisi meja",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.saveLayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Lihat detail meja berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Table"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Isi status out_of_order untuk menandai meja tidak bisa dipakai, status lain melepas out_of_order, kosong = tidak berubah. Selain out_of_order, status selalu dihitung ulang dari order dan reservasi. Posisi di denah diubah lewat PUT /tables/layout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Update data meja",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data meja yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.newTableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Hapus (soft delete) data meja",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID meja",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/visits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customer Visit"
+                ],
+                "summary": "Ambil semua data kunjungan customer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CustomerVisit"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customer Visit"
+                ],
+                "summary": "Tambah data kunjungan customer",
+                "parameters": [
+                    {
+                        "description": "Data kunjungan customer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CustomerVisitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customer Visit"
+                ],
+                "summary": "Ambil detail kunjungan berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kunjungan",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomerVisit"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customer Visit"
+                ],
+                "summary": "Update data kunjungan customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kunjungan",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data yang diperbarui",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CustomerVisitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customer Visit"
+                ],
+                "summary": "Hapus data kunjungan customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Kunjungan",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tanpa status, yang diambil adalah antrian hari ini. Antrian waiting dilengkapi posisi dan perkiraan sisa tunggu.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Ambil antrian walk-in outlet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "waiting",
+                            "seated",
+                            "left",
+                            "canceled"
+                        ],
+                        "type": "string",
+                        "description": "Status antrian",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WaitlistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Perkiraan lama tunggu dihitung dari kondisi meja, rata-rata lama meja dipakai, dan antrian di depan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Daftarkan tamu walk-in ke antrian",
+                "parameters": [
+                    {
+                        "description": "Data antrian",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWaitlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist/quote": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Perkiraan lama tunggu untuk rombongan baru",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID outlet",
+                        "name": "outlet_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah tamu",
+                        "name": "pax",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Ambil detail antrian",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID antrian",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist/{id}/remove": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Keluarkan tamu dari antrian",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID antrian",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "left = tamu pergi, canceled = dibatalkan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RemoveWaitlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist/{id}/seat": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tanpa table_id, meja kosong terkecil yang cukup dipilih otomatis, atau gabungan meja bersebelahan. Kunjungan pelanggan dan order dine-in langsung dibuka.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Panggil antrian ke meja",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID antrian",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meja dan waiter",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SeatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "occurred_at": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AssignCategoriesRequest": {
+            "type": "object",
+            "required": [
+                "category_ids"
+            ],
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.BillPaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "bill_id",
+                "payment_method"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "cash: uang diterima (termasuk tip), lainnya: nominal untuk bill tanpa tip",
+                    "type": "number"
+                },
+                "bill_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "room_charge",
+                        "voucher",
+                        "split"
+                    ]
+                },
+                "reference_number": {
+                    "type": "string"
+                },
+                "room_charge_approved_by": {
+                    "type": "integer"
+                },
+                "tip_amount": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "handlers.CategoryTaxRateInput": {
+            "type": "object",
+            "required": [
+                "category_id"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "tax_percentage": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "handlers.CategoryTaxRateRequest": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CategoryTaxRateInput"
+                    }
+                }
+            }
+        },
+        "handlers.ChangeOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "void lewat DELETE /orders/{id}, transferred lewat transfer meja",
+                    "type": "string",
+                    "enum": [
+                        "settled"
+                    ]
+                }
+            }
+        },
+        "handlers.CloseDrawerRequest": {
+            "type": "object",
+            "required": [
+                "counts"
+            ],
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrawerCount"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ComboRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "slots"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handlers.ComboSlotRequest"
+                    }
+                }
+            }
+        },
+        "handlers.ComboSlotOptionRequest": {
+            "type": "object",
+            "required": [
+                "menu_item_id"
+            ],
+            "properties": {
+                "is_default": {
+                    "type": "boolean"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "price_delta": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.ComboSlotRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "options"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handlers.ComboSlotOptionRequest"
+                    }
+                }
+            }
+        },
+        "handlers.CreateBillRequest": {
+            "type": "object",
+            "required": [
+                "order_id"
+            ],
+            "properties": {
+                "discount_amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "order_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateIngredientRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_allergen": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateMenuIngredientRequest": {
+            "type": "object",
+            "required": [
+                "ingredient_id",
+                "menu_item_id",
+                "qty"
+            ],
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "is_removable": {
+                    "type": "boolean"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.CreateMenuItemRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "name",
+                "price",
+                "sku"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "preparation_time": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.CreateOutletRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "default IDR",
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prices_include_tax": {
+                    "type": "boolean"
+                },
+                "rounding_mode": {
+                    "description": "default nearest",
+                    "type": "string",
+                    "enum": [
+                        "nearest",
+                        "up",
+                        "down"
+                    ]
+                },
+                "rounding_unit": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "service_charge_exempt_types": {
+                    "description": "default takeaway \u0026 delivery, kirim [] agar semua order_type kena service charge",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "service_charge_percentage": {
+                    "type": "number"
+                },
+                "tax_on_service_charge": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "tax_percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.CreateReservationRequest": {
+            "type": "object",
+            "required": [
+                "customer_id",
+                "pax",
+                "reservation_time",
+                "status"
+            ],
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "join_table_ids": {
+                    "description": "meja tambahan yang digabung dengan table_id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "outlet_id": {
+                    "description": "wajib jika table_id kosong",
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "reservation_time": {
+                    "type": "string"
+                },
+                "special_request": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "waiting",
+                        "canceled",
+                        "no_show",
+                        "seated"
+                    ]
+                },
+                "table_id": {
+                    "description": "kosong = meja dipilih otomatis",
+                    "type": "integer"
+                },
+                "visit_type": {
+                    "type": "string",
+                    "enum": [
+                        "breakfast",
+                        "lunch",
+                        "dinner",
+                        "event"
+                    ]
+                }
+            }
+        },
+        "handlers.CreateStationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "printer": {
+                    "description": "nama printer di POS_PRINTERS, kosong = hanya KDS",
+                    "type": "string"
+                },
+                "printer_width": {
+                    "type": "integer",
+                    "maximum": 64,
+                    "minimum": 32
+                }
+            }
+        },
+        "handlers.CreateStockMovementRequest": {
+            "type": "object",
+            "required": [
+                "movement_type",
+                "qty"
+            ],
+            "properties": {
+                "movement_type": {
+                    "type": "string",
+                    "enum": [
+                        "receiving",
+                        "waste",
+                        "adjustment"
+                    ]
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.CreateTableTransferRequest": {
+            "type": "object",
+            "required": [
+                "from_table_id",
+                "order_id",
+                "to_table_id",
+                "transferred_by"
+            ],
+            "properties": {
+                "from_table_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_table_id": {
+                    "type": "integer"
+                },
+                "transferred_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CreateWaitlistRequest": {
+            "type": "object",
+            "required": [
+                "guest_name",
+                "outlet_id",
+                "pax"
+            ],
+            "properties": {
+                "customer_id": {
+                    "description": "kosong = tamu walk-in baru",
+                    "type": "integer"
+                },
+                "guest_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CustomerRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "hotel_guest_id": {
+                    "type": "string"
+                },
+                "last_visit": {
+                    "description": "ISO string expected",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "visit_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CustomerVisitRequest": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "room_number": {
+                    "type": "string"
+                },
+                "total_spent": {
+                    "type": "number"
+                },
+                "visit_date": {
+                    "type": "string"
+                },
+                "visit_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DiningPeriodRequest": {
+            "type": "object",
+            "properties": {
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiningPeriod"
+                    }
+                }
+            }
+        },
+        "handlers.FireRequest": {
+            "type": "object",
+            "properties": {
+                "course": {
+                    "description": "fire satu course",
+                    "type": "string",
+                    "enum": [
+                        "appetizer",
+                        "main",
+                        "dessert"
+                    ]
+                },
+                "ticket_ids": {
+                    "description": "kosong = semua tiket yang ditahan",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "pin_code",
+                "staff_id"
+            ],
+            "properties": {
+                "pin_code": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MergeBillsRequest": {
+            "type": "object",
+            "required": [
+                "bill_ids"
+            ],
+            "properties": {
+                "bill_ids": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MergeOrdersRequest": {
+            "type": "object",
+            "required": [
+                "source_order_ids",
+                "target_order_id"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "source_order_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_order_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ModifierGroupRequest": {
+            "type": "object",
+            "required": [
+                "max_select",
+                "menu_item_id",
+                "name"
+            ],
+            "properties": {
+                "max_select": {
+                    "type": "integer"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "min_select": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ModifierOptionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "ingredient_id": {
+                    "description": "0 = tidak memakai bahan tambahan",
+                    "type": "integer"
+                },
+                "ingredient_qty": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "is_active": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.NewOrderRequest": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "hotel_room": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItemInput"
+                    }
+                },
+                "order_type": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                },
+                "waiter_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.OpenDrawerRequest": {
+            "type": "object",
+            "required": [
+                "outlet_id"
+            ],
+            "properties": {
+                "opening_float": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "outlet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RefundRequest": {
+            "type": "object",
+            "required": [
+                "approved_by",
+                "approver_pin",
+                "payment_id",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "0 = seluruh sisa pembayaran",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "approved_by": {
+                    "type": "integer"
+                },
+                "approver_pin": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RemoveWaitlistRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "left",
+                        "canceled"
+                    ]
+                }
+            }
+        },
+        "handlers.SeatRequest": {
+            "type": "object",
+            "properties": {
+                "join_table_ids": {
+                    "description": "meja tambahan yang digabung dengan table_id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "table_id": {
+                    "description": "kosong = meja dipilih otomatis",
+                    "type": "integer"
+                },
+                "waiter_id": {
+                    "description": "kosong = staf yang memanggil",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StaffRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "pin_code",
+                "role"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pin_code": {
+                    "type": "string",
+                    "maxLength": 10,
+                    "minLength": 4
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.StationPrinterRequest": {
+            "type": "object",
+            "properties": {
+                "printer": {
+                    "type": "string"
+                },
+                "printer_width": {
+                    "type": "integer",
+                    "maximum": 64,
+                    "minimum": 32
+                }
+            }
+        },
+        "handlers.UpdateStaffRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "role"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pin_code": {
+                    "type": "string",
+                    "maxLength": 10,
+                    "minLength": 4
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.VoidItemRequest": {
+            "type": "object",
+            "properties": {
+                "prepared": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.newTableRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "location_type": {
+                    "type": "string"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "pos_x": {
+                    "type": "integer"
+                },
+                "pos_y": {
+                    "type": "integer"
+                },
+                "section": {
+                    "type": "string"
+                },
+                "shape": {
+                    "type": "string",
+                    "enum": [
+                        "square",
+                        "round",
+                        "rectangle"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "available",
+                        "occupied",
+                        "reserved",
+                        "out_of_order"
+                    ]
+                },
+                "table_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.saveLayoutRequest": {
+            "type": "object",
+            "required": [
+                "outlet_id",
+                "tables"
+            ],
+            "properties": {
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "tables": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.TableLayout"
+                    }
+                }
+            }
+        },
+        "models.AddOrderItemRequest": {
+            "type": "object",
+            "required": [
+                "qty"
+            ],
+            "properties": {
+                "combo_id": {
+                    "type": "integer"
+                },
+                "combo_selections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComboSelection"
+                    }
+                },
+                "course": {
+                    "type": "string",
+                    "enum": [
+                        "appetizer",
+                        "main",
+                        "dessert"
+                    ]
+                },
+                "excluded_ingredient_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "hold": {
+                    "type": "boolean"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "modifier_option_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "price_override": {
+                    "$ref": "#/definitions/models.PriceOverride"
+                },
+                "qty": {
+                    "type": "number"
+                },
+                "seat_no": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.AvailabilitySlot": {
+            "type": "object",
+            "properties": {
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "tables": {
+                    "$ref": "#/definitions/models.TableSuggestion"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "visit_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Bill": {
+            "type": "object",
+            "properties": {
+                "balance_due": {
+                    "description": "total_amount - refunded_amount - paid_amount",
+                    "type": "number"
+                },
+                "bill_number": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "original_bill_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "paid_amount": {
+                    "type": "number"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "rounding_amount": {
+                    "type": "number"
+                },
+                "service_charge": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_included": {
+                    "description": "Bagian tax_amount yang sudah termasuk harga menu",
+                    "type": "number"
+                },
+                "tip_amount": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BillRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "approved_by": {
+                    "type": "integer"
+                },
+                "bill_id": {
+                    "type": "integer"
+                },
+                "bill_status": {
+                    "description": "status bill setelah refund",
+                    "type": "string"
+                },
+                "drawer_session_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_reversal": {
+                    "description": "bill belum lunas: hanya membuka lagi sisa tagihan, tidak dihitung refund penjualan",
+                    "type": "boolean"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "pms_posting_id": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference_number": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "refund_method": {
+                    "type": "string"
+                },
+                "refunded_at": {
+                    "type": "string"
+                },
+                "refunded_by": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                }
+            }
+        },
+        "models.CashDrawerSession": {
+            "type": "object",
+            "properties": {
+                "cashier_id": {
+                    "type": "integer"
+                },
+                "cashier_name": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "closed_by": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "opening_float": {
+                    "type": "number"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryTaxRate": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "tax_percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Combo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sku": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComboSlot"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ComboSelection": {
+            "type": "object",
+            "properties": {
+                "excluded_ingredient_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "modifier_option_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "slot_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ComboSlot": {
+            "type": "object",
+            "properties": {
+                "combo_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComboSlotOption"
+                    }
+                }
+            }
+        },
+        "models.ComboSlotOption": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "menu_name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "description": "Upcharge",
+                    "type": "number"
+                },
+                "slot_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CourseServiceTime": {
+            "type": "object",
+            "properties": {
+                "avg_fire_to_serve_minutes": {
+                    "description": "fire sampai semua item served",
+                    "type": "number"
+                },
+                "avg_order_to_fire_minutes": {
+                    "description": "order dibuka sampai course di-fire",
+                    "type": "number"
+                },
+                "course": {
+                    "type": "string"
+                },
+                "max_fire_to_serve_minutes": {
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cust_id": {
+                    "type": "integer"
+                },
+                "hotel_guest_id": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "last_visit": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visit_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CustomerVisit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "room_number": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "total_spent": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visit_date": {
+                    "type": "string"
+                },
+                "visit_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DiningPeriod": {
+            "type": "object",
+            "required": [
+                "duration_minutes",
+                "first_seating",
+                "last_seating",
+                "visit_type"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "first_seating": {
+                    "description": "HH:MM, slot pertama",
+                    "type": "string"
+                },
+                "last_seating": {
+                    "description": "HH:MM, slot terakhir",
+                    "type": "string"
+                },
+                "visit_type": {
+                    "type": "string",
+                    "enum": [
+                        "breakfast",
+                        "lunch",
+                        "dinner",
+                        "event"
+                    ]
+                }
+            }
+        },
+        "models.DrawerCount": {
+            "type": "object",
+            "required": [
+                "payment_method"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "credit_card",
+                        "debit_card",
+                        "voucher"
+                    ]
+                }
+            }
+        },
+        "models.DrawerMethodTotal": {
+            "type": "object",
+            "properties": {
+                "counted": {
+                    "description": "hanya di laporan Z untuk metode yang dihitung",
+                    "type": "number"
+                },
+                "expected": {
+                    "description": "seharusnya ada di laci, cash termasuk modal awal",
+                    "type": "number"
+                },
+                "over_short": {
+                    "description": "counted - expected, negatif = kurang",
+                    "type": "number"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "integer"
+                },
+                "refunds": {
+                    "type": "number"
+                },
+                "sales": {
+                    "description": "nominal yang mengurangi tagihan",
+                    "type": "number"
+                },
+                "tips": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DrawerReport": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrawerMethodTotal"
+                    }
+                },
+                "over_short": {
+                    "description": "jumlah selisih semua metode yang dihitung",
+                    "type": "number"
+                },
+                "session": {
+                    "$ref": "#/definitions/models.CashDrawerSession"
+                },
+                "total_refunds": {
+                    "type": "number"
+                },
+                "total_sales": {
+                    "type": "number"
+                },
+                "total_tips": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FloorOrder": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "item aktif",
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "paid": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "waiter": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FloorReservation": {
+            "type": "object",
+            "properties": {
+                "customer_name": {
+                    "type": "string"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "reservation_id": {
+                    "type": "integer"
+                },
+                "reservation_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FloorTable": {
+            "type": "object",
+            "properties": {
+                "adjacent": {
+                    "description": "meja yang bisa digabung",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "elapsed_minutes": {
+                    "description": "sejak order pertama di meja dibuka",
+                    "type": "integer"
+                },
+                "next_reservation": {
+                    "$ref": "#/definitions/models.FloorReservation"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FloorOrder"
+                    }
+                },
+                "pos_x": {
+                    "type": "integer"
+                },
+                "pos_y": {
+                    "type": "integer"
+                },
+                "running_total": {
+                    "description": "total semua order aktif termasuk service \u0026 pajak",
+                    "type": "number"
+                },
+                "section": {
+                    "type": "string"
+                },
+                "shape": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                },
+                "table_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FreedTable": {
+            "type": "object",
+            "properties": {
+                "table_id": {
+                    "type": "integer"
+                },
+                "table_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Ingredient": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "description": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_allergen": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateBillRequest": {
+        "models.KitchenPrintJob": {
             "type": "object",
-            "required": [
-                "order_id"
-            ],
             "properties": {
-                "discount_amount": {
-                    "type": "number"
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "next_attempt_at": {
+                    "type": "string"
                 },
                 "order_id": {
                     "type": "integer"
+                },
+                "printed_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "printer": {
+                    "type": "string"
+                },
+                "reprint": {
+                    "type": "boolean"
+                },
+                "station_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "ticket_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.CreateCategoryRequest": {
+        "models.KitchenQueueItem": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "course": {
+                    "type": "string"
+                },
+                "excluded_ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fired_at": {
+                    "description": "sama dengan ordered_at kecuali item sempat ditahan",
+                    "type": "string"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "menu_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_item_id": {
+                    "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "ordered_at": {
+                    "type": "string"
+                },
+                "preparation_time": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "qty": {
+                    "type": "number"
+                },
+                "ready_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "started_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "station_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "station_name": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
+                },
+                "table_number": {
+                    "type": "string"
+                },
+                "ticket_id": {
+                    "type": "integer"
+                },
+                "ticket_item_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.CreateIngredientRequest": {
+        "models.KitchenStation": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "description": {
+                "created_at": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "deleted_at": {
+                    "$ref": "#/definitions/sql.NullTime"
                 },
-                "is_allergen": {
-                    "type": "boolean"
+                "id": {
+                    "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "qty": {
-                    "type": "number"
+                "printer": {
+                    "description": "nama printer di POS_PRINTERS, kosong = hanya KDS",
+                    "type": "string"
                 },
-                "unit": {
+                "printer_width": {
+                    "description": "karakter per baris tiket",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MenuCategory": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
+                },
+                "station_id": {
+                    "description": "Station dapur tujuan tiket",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sql.NullInt64"
+                        }
+                    ]
                 }
             }
         },
-        "handlers.CreateMenuIngredientRequest": {
+        "models.MenuIngredient": {
             "type": "object",
-            "required": [
-                "ingredient_id",
-                "menu_item_id",
-                "qty"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "ingredient_id": {
                     "type": "integer"
                 },
@@ -3202,206 +9635,231 @@ const docTemplate = `{
                 },
                 "qty": {
                     "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateMenuItemRequest": {
+        "models.MenuItem": {
             "type": "object",
-            "required": [
-                "category_id",
-                "name",
-                "price",
-                "sku"
-            ],
             "properties": {
                 "category_id": {
                     "type": "integer"
                 },
                 "cost": {
-                    "type": "number",
-                    "minimum": 0
+                    "type": "number"
                 },
-                "description": {
+                "created_at": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "description": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "is_active": {
                     "type": "boolean"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 255
+                    "type": "string"
                 },
                 "preparation_time": {
-                    "type": "integer"
+                    "$ref": "#/definitions/sql.NullInt64"
                 },
                 "price": {
                     "type": "number"
                 },
                 "sku": {
-                    "type": "string",
-                    "maxLength": 50
+                    "type": "string"
                 },
                 "tags": {
+                    "description": "parsed manually from JSONB",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateOutletRequest": {
+        "models.MenuItemWithIngredients": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "is_active": {
-                    "type": "boolean"
+                "category_id": {
+                    "type": "integer"
                 },
-                "location": {
-                    "type": "string"
+                "cost": {
+                    "type": "number"
                 },
-                "name": {
+                "created_at": {
                     "type": "string"
                 },
-                "service_charge_percentage": {
-                    "type": "number"
+                "deleted_at": {
+                    "$ref": "#/definitions/sql.NullTime"
                 },
-                "tax_percentage": {
-                    "type": "number"
-                }
-            }
-        },
-        "handlers.CreateReservationRequest": {
-            "type": "object",
-            "required": [
-                "customer_id",
-                "pax",
-                "reservation_time",
-                "status",
-                "table_id"
-            ],
-            "properties": {
-                "customer_id": {
-                    "type": "integer"
+                "description": {
+                    "$ref": "#/definitions/sql.NullString"
                 },
-                "pax": {
+                "id": {
                     "type": "integer"
                 },
-                "reservation_time": {
-                    "type": "string"
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Ingredient"
+                    }
                 },
-                "special_request": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "modifier_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierGroup"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "status": {
+                "preparation_time": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sku": {
                     "type": "string"
                 },
-                "table_id": {
-                    "type": "integer"
+                "tags": {
+                    "description": "parsed manually from JSONB",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CreateTableTransferRequest": {
+        "models.MergeResult": {
             "type": "object",
-            "required": [
-                "from_table_id",
-                "order_id",
-                "to_table_id",
-                "transferred_by"
-            ],
             "properties": {
-                "from_table_id": {
+                "bill_id": {
+                    "description": "bill gabungan, hanya untuk merge bill",
                     "type": "integer"
                 },
-                "order_id": {
+                "freed_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FreedTable"
+                    }
+                },
+                "items_moved": {
                     "type": "integer"
                 },
-                "reason": {
-                    "type": "string"
+                "merged_order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "to_table_id": {
+                "outlet_id": {
                     "type": "integer"
                 },
-                "transferred_by": {
+                "target_order_id": {
                     "type": "integer"
+                },
+                "voided_bill_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "handlers.CustomerRequest": {
+        "models.ModifierGroup": {
             "type": "object",
-            "required": [
-                "name",
-                "type"
-            ],
             "properties": {
-                "hotel_guest_id": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "last_visit": {
-                    "description": "ISO string expected",
-                    "type": "string"
+                "max_select": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "menu_item_id": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "min_select": {
+                    "description": "1 = wajib pilih",
+                    "type": "integer"
                 },
-                "type": {
+                "name": {
                     "type": "string"
                 },
-                "visit_count": {
-                    "type": "integer"
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierOption"
+                    }
                 }
             }
         },
-        "handlers.CustomerVisitRequest": {
+        "models.ModifierOption": {
             "type": "object",
             "properties": {
-                "customer_id": {
-                    "type": "integer"
-                },
-                "outlet_id": {
-                    "type": "integer"
-                },
-                "pax": {
+                "group_id": {
                     "type": "integer"
                 },
-                "reservation_id": {
+                "id": {
                     "type": "integer"
                 },
-                "room_number": {
-                    "type": "string"
+                "ingredient_id": {
+                    "description": "Bahan extra yang ikut dipakai",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sql.NullInt64"
+                        }
+                    ]
                 },
-                "total_spent": {
+                "ingredient_qty": {
                     "type": "number"
                 },
-                "visit_date": {
-                    "type": "string"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "visit_type": {
+                "name": {
                     "type": "string"
+                },
+                "price_delta": {
+                    "type": "number"
                 }
             }
         },
-        "handlers.NewOrderRequest": {
+        "models.Order": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
                 "customer_id": {
                     "type": "integer"
                 },
                 "hotel_room": {
-                    "type": "string"
+                    "$ref": "#/definitions/sql.NullString"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.OrderItemInput"
-                    }
+                "order_number": {
+                    "type": "string"
                 },
                 "order_type": {
                     "type": "string"
@@ -3415,272 +9873,226 @@ const docTemplate = `{
                 "table_id": {
                     "type": "integer"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
                 "waiter_id": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.StaffRequest": {
+        "models.OrderCourse": {
             "type": "object",
-            "required": [
-                "name",
-                "pin_code",
-                "role"
-            ],
             "properties": {
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
+                "course": {
                     "type": "string"
                 },
-                "pin_code": {
+                "created_at": {
                     "type": "string"
                 },
-                "role": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.newTableRequest": {
-            "type": "object",
-            "properties": {
-                "capacity": {
-                    "type": "integer"
+                "fired_at": {
+                    "$ref": "#/definitions/sql.NullTime"
                 },
-                "location_type": {
-                    "type": "string"
+                "fired_by": {
+                    "$ref": "#/definitions/sql.NullInt64"
                 },
-                "outlet_id": {
+                "items": {
+                    "description": "item aktif (belum void)",
                     "type": "integer"
                 },
-                "status": {
-                    "type": "string"
+                "order_id": {
+                    "type": "integer"
                 },
-                "table_number": {
+                "served_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "models.AddOrderItemRequest": {
+        "models.OrderItemInput": {
             "type": "object",
             "required": [
-                "menu_item_id",
                 "qty"
             ],
             "properties": {
-                "excluded_ingredient_ids": {
+                "combo_id": {
+                    "description": "Paket: isi combo_id (menu_item_id diabaikan), komponen dikembalikan sebagai item dengan order_combo_id",
+                    "type": "integer"
+                },
+                "combo_selections": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.ComboSelection"
                     }
                 },
-                "menu_item_id": {
-                    "type": "integer"
-                },
-                "notes": {
-                    "type": "string"
-                },
-                "qty": {
-                    "type": "number"
-                },
-                "unit_price": {
-                    "description": "captured per item",
-                    "type": "number"
-                }
-            }
-        },
-        "models.Bill": {
-            "type": "object",
-            "properties": {
-                "balance_due": {
-                    "type": "number"
-                },
-                "bill_number": {
+                "course": {
+                    "description": "appetizer, main, dessert; dine-in ditahan sampai course di-fire",
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "excluded_ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "discount_amount": {
-                    "type": "number"
+                "hold": {
+                    "description": "tahan di dapur sampai waiter fire",
+                    "type": "boolean"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "order_id": {
+                "menu_item_id": {
                     "type": "integer"
                 },
-                "original_bill_id": {
-                    "$ref": "#/definitions/sql.NullInt64"
-                },
-                "paid_amount": {
-                    "type": "number"
-                },
-                "service_charge": {
-                    "type": "number"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "subtotal": {
-                    "type": "number"
-                },
-                "tax_amount": {
-                    "type": "number"
+                "modifier_option_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "total_amount": {
+                "modifiers_price": {
+                    "description": "diisi server, total price_delta per unit",
                     "type": "number"
                 },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Customer": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                "notes": {
                     "type": "string"
                 },
-                "cust_id": {
+                "order_combo_id": {
                     "type": "integer"
                 },
-                "hotel_guest_id": {
-                    "$ref": "#/definitions/sql.NullString"
-                },
-                "last_visit": {
-                    "$ref": "#/definitions/sql.NullTime"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "phone": {
-                    "$ref": "#/definitions/sql.NullString"
+                "price_override": {
+                    "$ref": "#/definitions/models.PriceOverride"
                 },
-                "type": {
-                    "type": "string"
+                "qty": {
+                    "type": "number"
                 },
-                "updated_at": {
-                    "type": "string"
+                "seat_no": {
+                    "description": "0 = hidangan bersama",
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "visit_count": {
-                    "type": "integer"
+                "unit_price": {
+                    "description": "diisi server dari menu_items.price, nilai dari client diabaikan",
+                    "type": "number"
                 }
             }
         },
-        "models.CustomerVisit": {
+        "models.OrderMerge": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "customer_id": {
-                    "type": "integer"
+                "bill_id": {
+                    "description": "bill gabungan jika merge dilakukan dari bill",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sql.NullInt64"
+                        }
+                    ]
                 },
                 "id": {
                     "type": "integer"
                 },
-                "outlet_id": {
+                "items_moved": {
                     "type": "integer"
                 },
-                "pax": {
-                    "type": "integer"
+                "merged_at": {
+                    "type": "string"
                 },
-                "reservation_id": {
+                "merged_by": {
                     "$ref": "#/definitions/sql.NullInt64"
                 },
-                "room_number": {
+                "reason": {
                     "$ref": "#/definitions/sql.NullString"
                 },
-                "total_spent": {
-                    "$ref": "#/definitions/sql.NullFloat64"
-                },
-                "updated_at": {
-                    "type": "string"
+                "source_order_id": {
+                    "type": "integer"
                 },
-                "visit_date": {
-                    "type": "string"
+                "source_table_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
                 },
-                "visit_type": {
-                    "type": "string"
+                "target_order_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Ingredient": {
+        "models.OrderStatusChange": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "changed_at": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/sql.NullTime"
+                "changed_by": {
+                    "$ref": "#/definitions/sql.NullInt64"
                 },
-                "description": {
+                "from_status": {
                     "$ref": "#/definitions/sql.NullString"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "is_allergen": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "qty": {
-                    "type": "number"
+                "order_id": {
+                    "type": "integer"
                 },
-                "unit": {
-                    "type": "string"
+                "reason": {
+                    "$ref": "#/definitions/sql.NullString"
                 },
-                "updated_at": {
+                "to_status": {
                     "type": "string"
                 }
             }
         },
-        "models.MenuCategory": {
+        "models.Outlet": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "description": "ISO 4217, menentukan presisi pembulatan",
+                    "type": "string"
+                },
                 "deleted_at": {
                     "$ref": "#/definitions/sql.NullTime"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
                 "name": {
                     "type": "string"
-                }
-            }
-        },
-        "models.MenuIngredient": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                },
+                "prices_include_tax": {
+                    "type": "boolean"
+                },
+                "rounding_mode": {
+                    "description": "nearest, up, down",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "rounding_unit": {
+                    "description": "0 = tanpa pembulatan",
+                    "type": "number"
                 },
-                "ingredient_id": {
-                    "type": "integer"
+                "service_charge_exempt_types": {
+                    "description": "order_type tanpa service charge",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "is_default": {
-                    "type": "boolean"
+                "service_charge_percentage": {
+                    "type": "number"
                 },
-                "is_removable": {
+                "tax_on_service_charge": {
                     "type": "boolean"
                 },
-                "menu_item_id": {
-                    "type": "integer"
-                },
-                "qty": {
+                "tax_percentage": {
                     "type": "number"
                 },
                 "updated_at": {
@@ -3688,109 +10100,128 @@ const docTemplate = `{
                 }
             }
         },
-        "models.MenuItem": {
+        "models.OutletDrawerReport": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "integer"
-                },
-                "cost": {
-                    "type": "number"
-                },
-                "created_at": {
+                "date": {
+                    "description": "laporan Z: tanggal tutup sesi",
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/sql.NullTime"
+                "generated_at": {
+                    "type": "string"
                 },
-                "description": {
-                    "$ref": "#/definitions/sql.NullString"
+                "methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrawerMethodTotal"
+                    }
                 },
-                "id": {
+                "outlet_id": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "preparation_time": {
-                    "$ref": "#/definitions/sql.NullInt64"
-                },
-                "price": {
+                "over_short": {
                     "type": "number"
                 },
-                "sku": {
-                    "type": "string"
-                },
-                "tags": {
-                    "description": "parsed manually from JSONB",
+                "sessions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.DrawerReport"
                     }
                 },
-                "updated_at": {
+                "total_refunds": {
+                    "type": "number"
+                },
+                "total_sales": {
+                    "type": "number"
+                },
+                "total_tips": {
+                    "type": "number"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.MenuItemWithIngredients": {
+        "models.PaymentReceipt": {
             "type": "object",
             "properties": {
-                "category_id": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance_due": {
+                    "type": "number"
+                },
+                "bill_id": {
                     "type": "integer"
                 },
-                "cost": {
+                "bill_number": {
+                    "type": "string"
+                },
+                "bill_status": {
+                    "type": "string"
+                },
+                "bill_total": {
                     "type": "number"
                 },
-                "created_at": {
+                "change_amount": {
+                    "type": "number"
+                },
+                "guest_name": {
+                    "description": "room_charge",
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/sql.NullTime"
+                "order_id": {
+                    "type": "integer"
                 },
-                "description": {
-                    "$ref": "#/definitions/sql.NullString"
+                "order_settled": {
+                    "type": "boolean"
                 },
-                "id": {
+                "paid_amount": {
+                    "type": "number"
+                },
+                "payment_id": {
                     "type": "integer"
                 },
-                "ingredients": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Ingredient"
-                    }
+                "payment_method": {
+                    "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "payment_time": {
+                    "type": "string"
                 },
-                "name": {
+                "pms_posting_id": {
+                    "description": "room_charge",
                     "type": "string"
                 },
-                "preparation_time": {
-                    "$ref": "#/definitions/sql.NullInt64"
+                "reference_number": {
+                    "type": "string"
                 },
-                "price": {
+                "tendered_amount": {
                     "type": "number"
                 },
-                "sku": {
+                "tip_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceOverride": {
+            "type": "object",
+            "properties": {
+                "approved_by": {
+                    "type": "integer"
+                },
+                "approver_pin": {
+                    "description": "hanya input, tidak pernah dikembalikan",
                     "type": "string"
                 },
-                "tags": {
-                    "description": "parsed manually from JSONB",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "price": {
+                    "type": "number"
                 },
-                "updated_at": {
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "models.Order": {
+        "models.Reservation": {
             "type": "object",
             "properties": {
                 "created_at": {
@@ -3799,123 +10230,119 @@ const docTemplate = `{
                 "customer_id": {
                     "type": "integer"
                 },
-                "hotel_room": {
-                    "$ref": "#/definitions/sql.NullString"
+                "duration_minutes": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "order_number": {
-                    "type": "string"
-                },
-                "order_type": {
-                    "type": "string"
+                "order_id": {
+                    "description": "order yang dibuka saat tamu didudukkan",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sql.NullInt64"
+                        }
+                    ]
                 },
                 "outlet_id": {
+                    "description": "outlet meja reservasi",
                     "type": "integer"
                 },
-                "status": {
+                "pax": {
+                    "type": "integer"
+                },
+                "reservation_time": {
                     "type": "string"
                 },
-                "table_id": {
-                    "type": "integer"
+                "seated_at": {
+                    "$ref": "#/definitions/sql.NullTime"
                 },
-                "updated_at": {
+                "special_request": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "status": {
                     "type": "string"
                 },
-                "waiter_id": {
+                "table_id": {
+                    "description": "meja utama",
                     "type": "integer"
-                }
-            }
-        },
-        "models.OrderItemInput": {
-            "type": "object",
-            "properties": {
-                "excluded_ingredients": {
+                },
+                "table_ids": {
+                    "description": "semua meja termasuk meja utama, lebih dari satu jika digabung",
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "menu_item_id": {
-                    "type": "integer"
-                },
-                "notes": {
+                "updated_at": {
                     "type": "string"
                 },
-                "qty": {
-                    "type": "number"
-                },
-                "unit_price": {
-                    "description": "captured per item",
-                    "type": "number"
+                "visit_type": {
+                    "$ref": "#/definitions/sql.NullString"
                 }
             }
         },
-        "models.Outlet": {
+        "models.SalesAnalysisDaily": {
             "type": "object",
             "properties": {
+                "analysis_date": {
+                    "type": "string"
+                },
+                "avg_spend_per_cover": {
+                    "type": "number"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/sql.NullTime"
+                "discount_amount": {
+                    "type": "number"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "location": {
-                    "$ref": "#/definitions/sql.NullString"
-                },
-                "name": {
-                    "type": "string"
+                "outlet_id": {
+                    "type": "integer"
                 },
-                "service_charge_percentage": {
+                "refund_amount": {
                     "type": "number"
                 },
-                "tax_percentage": {
+                "total_covers": {
+                    "type": "integer"
+                },
+                "total_sales": {
+                    "description": "Subtotal bill terbayar, sebelum diskon, pajak dan service charge",
                     "type": "number"
                 },
                 "updated_at": {
+                    "description": "Terakhir dihitung ulang",
                     "type": "string"
+                },
+                "void_amount": {
+                    "description": "Nilai item yang di-void, dasar yang sama dengan total_sales",
+                    "type": "number"
                 }
             }
         },
-        "models.Reservation": {
+        "models.SeatResult": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
                 "customer_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "pax": {
+                "order_id": {
                     "type": "integer"
                 },
-                "reservation_time": {
+                "order_number": {
                     "type": "string"
                 },
-                "special_request": {
-                    "$ref": "#/definitions/sql.NullString"
-                },
-                "status": {
-                    "type": "string"
+                "table_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "table_id": {
+                "visit_id": {
                     "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
@@ -3926,6 +10353,21 @@ const docTemplate = `{
                     "type": "number"
                 },
                 "item_ids": {
+                    "description": "mode items",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "items": {
+                    "description": "mode quantity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SplitItemQty"
+                    }
+                },
+                "seats": {
+                    "description": "mode seat",
                     "type": "array",
                     "items": {
                         "type": "integer"
@@ -3936,6 +10378,17 @@ const docTemplate = `{
         "models.SplitBillRequest": {
             "type": "object",
             "properties": {
+                "discount_amount": {
+                    "description": "mode even, dibagi rata ke semua tamu",
+                    "type": "number"
+                },
+                "guests": {
+                    "description": "mode even",
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
                 "original_bill_id": {
                     "description": "\u003c- Tambahan",
                     "type": "integer"
@@ -3951,6 +10404,17 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SplitItemQty": {
+            "type": "object",
+            "properties": {
+                "order_item_id": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "number"
+                }
+            }
+        },
         "models.Staff": {
             "type": "object",
             "properties": {
@@ -3969,9 +10433,6 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "pin_code": {
-                    "type": "string"
-                },
                 "role": {
                     "type": "string"
                 },
@@ -3980,6 +10441,56 @@ const docTemplate = `{
                 }
             }
         },
+        "models.StockMovement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "movement_type": {
+                    "type": "string"
+                },
+                "notes": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "order_item_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "qty_change": {
+                    "description": "Negatif = stok keluar",
+                    "type": "number"
+                },
+                "staff_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                }
+            }
+        },
+        "models.StockReconciliation": {
+            "type": "object",
+            "properties": {
+                "current_qty": {
+                    "type": "number"
+                },
+                "difference": {
+                    "type": "number"
+                },
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "is_balanced": {
+                    "type": "boolean"
+                },
+                "ledger_qty": {
+                    "type": "number"
+                }
+            }
+        },
         "models.Table": {
             "type": "object",
             "properties": {
@@ -4006,6 +10517,19 @@ const docTemplate = `{
                 "outlet_id": {
                     "type": "integer"
                 },
+                "pos_x": {
+                    "type": "integer"
+                },
+                "pos_y": {
+                    "type": "integer"
+                },
+                "section": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "shape": {
+                    "description": "square, round, rectangle",
+                    "type": "string"
+                },
                 "status": {
                     "description": "available, occupied, etc.",
                     "type": "string"
@@ -4018,6 +10542,62 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TableLayout": {
+            "type": "object",
+            "required": [
+                "table_id"
+            ],
+            "properties": {
+                "adjacent": {
+                    "description": "Meja sebelah yang bisa digabung untuk rombongan besar, null = tidak diubah, [] = hapus semua",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "pos_x": {
+                    "type": "integer"
+                },
+                "pos_y": {
+                    "type": "integer"
+                },
+                "section": {
+                    "type": "string"
+                },
+                "shape": {
+                    "type": "string",
+                    "enum": [
+                        "square",
+                        "round",
+                        "rectangle"
+                    ]
+                },
+                "table_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TableSuggestion": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "total kursi",
+                    "type": "integer"
+                },
+                "table_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "table_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.TableTransfer": {
             "type": "object",
             "properties": {
@@ -4044,6 +10624,147 @@ const docTemplate = `{
                 }
             }
         },
+        "models.WaitQuote": {
+            "type": "object",
+            "properties": {
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "parties_ahead": {
+                    "description": "antrian di depan yang butuh meja sejenis",
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "quoted_minutes": {
+                    "description": "dibulatkan ke atas per 5 menit",
+                    "type": "integer"
+                },
+                "turn_minutes": {
+                    "description": "rata-rata lama meja dipakai yang dipakai untuk perkiraan",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WaitlistEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "customer_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "estimated_minutes": {
+                    "description": "perkiraan sisa tunggu saat ini",
+                    "type": "integer"
+                },
+                "guest_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "order_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "outlet_id": {
+                    "type": "integer"
+                },
+                "pax": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "position": {
+                    "description": "Hanya untuk antrian yang masih waiting",
+                    "type": "integer"
+                },
+                "quoted_minutes": {
+                    "description": "perkiraan tunggu saat mendaftar",
+                    "type": "integer"
+                },
+                "seated_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "visit_id": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "waited_minutes": {
+                    "description": "sudah menunggu sejak mendaftar",
+                    "type": "integer"
+                }
+            }
+        },
+        "pms.Guest": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "saldo folio saat ini",
+                    "type": "number"
+                },
+                "credit_limit": {
+                    "description": "0 = tanpa batas",
+                    "type": "number"
+                },
+                "guest_id": {
+                    "type": "string"
+                },
+                "in_house": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "room": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.Breakdown": {
+            "type": "object",
+            "properties": {
+                "discount_amount": {
+                    "type": "number"
+                },
+                "rounding_amount": {
+                    "type": "number"
+                },
+                "service_charge": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_amount": {
+                    "type": "number"
+                },
+                "tax_included": {
+                    "description": "bagian TaxAmount yang sudah termasuk di harga menu",
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
         "sql.NullFloat64": {
             "type": "object",
             "properties": {
@@ -4092,6 +10813,14 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Isi dengan \"Bearer \u003ctoken\u003e\" dari /auth/login",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
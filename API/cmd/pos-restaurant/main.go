@@ -6,16 +6,27 @@
 // @host localhost:8080
 // @BasePath /api
 // @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Isi dengan "Bearer <token>" dari /auth/login
 
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 	"pos-restaurant/database"
+	"pos-restaurant/events"
 	"pos-restaurant/handlers"
+	"pos-restaurant/pms"
+	"pos-restaurant/printing"
 	"pos-restaurant/repositories"
 	"pos-restaurant/server"
 	"pos-restaurant/services"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -26,11 +37,38 @@ func main() {
 	}
 	defer database.DB.Close()
 
+	// Integrasi PMS hotel untuk room charge, kosongkan POS_PMS_DRIVER jika tidak dipakai
+	pmsClient, err := pms.New(pms.Config{
+		Driver:       os.Getenv("POS_PMS_DRIVER"),
+		GuestsFile:   os.Getenv("POS_PMS_GUESTS_FILE"),
+		PostingsFile: os.Getenv("POS_PMS_POSTINGS_FILE"),
+		BaseURL:      os.Getenv("POS_PMS_URL"),
+		APIKey:       os.Getenv("POS_PMS_API_KEY"),
+	})
+	if err != nil {
+		log.Fatalf("Gagal inisialisasi PMS: %v", err)
+	}
+	if pmsClient == nil {
+		log.Println("POS_PMS_DRIVER tidak diset, room charge hanya dicatat tanpa posting ke PMS")
+	}
+
+	// Printer tiket dapur per station, contoh POS_PRINTERS="dapur=tcp://192.168.1.50:9100,bar=file:/dev/usb/lp0"
+	printers, err := printing.ParsePrinters(os.Getenv("POS_PRINTERS"))
+	if err != nil {
+		log.Fatalf("Gagal membaca konfigurasi printer: %v", err)
+	}
+	if len(printers) == 0 {
+		log.Println("POS_PRINTERS tidak diset, tiket dapur hanya tampil di KDS")
+	}
+
 	// Repo Init
 	menuRepo := repositories.NewMenuItemRepository(database.DB)
 	categoryRepo := repositories.NewMenuCategoryRepository(database.DB)
 	ingredientRepo := repositories.NewIngredientRepository(database.DB)
+	stockMovementRepo := repositories.NewStockMovementRepository(database.DB)
 	menuIngredientRepo := repositories.NewMenuIngredientRepository(database.DB)
+	modifierRepo := repositories.NewModifierRepository(database.DB)
+	comboRepo := repositories.NewComboRepository(database.DB)
 
 	outletRepo := repositories.NewOutletRepository(database.DB)
 	tableRepo := repositories.NewTableRepository(database.DB)
@@ -39,34 +77,77 @@ func main() {
 	customerRepo := repositories.NewCustomerRepository(database.DB)
 	customerVisitRepo := repositories.NewCustomerVisitRepository(database.DB)
 	reservationRepo := repositories.NewReservationRepository(database.DB)
+	waitlistRepo := repositories.NewWaitlistRepository(database.DB)
 
 	orderRepo := repositories.NewOrderRepository(database.DB)
-	billRepo := repositories.NewBillRepository(database.DB)
+	kitchenRepo := repositories.NewKitchenRepository(database.DB)
+	billRepo := repositories.NewBillRepository(database.DB, pmsClient)
 	tableTfRepo := repositories.NewTableTransferRepository(database.DB)
+	salesAnalysisRepo := repositories.NewSalesAnalysisRepository(database.DB)
+	cashDrawerRepo := repositories.NewCashDrawerRepository(database.DB)
+
+	// Event broker (real-time update ke POS, KDS dan floor plan)
+	broker := events.NewBroker()
 
 	// Service Init
+	authSecret := os.Getenv("POS_AUTH_SECRET")
+	if authSecret == "" {
+		log.Println("POS_AUTH_SECRET tidak diset, token login hanya berlaku sampai server restart")
+	}
+	authService := services.NewAuthService(staffRepo, authSecret, 12*time.Hour)
 	menuService := services.NewMenuService(menuRepo)
 	categoryService := services.NewMenuCategoryService(categoryRepo)
 	ingredientService := services.NewIngredientService(ingredientRepo)
+	stockMovementService := services.NewStockMovementService(stockMovementRepo)
 	menuIngredientService := services.NewMenuIngredientService(menuIngredientRepo)
+	modifierService := services.NewModifierService(modifierRepo)
+	comboService := services.NewComboService(comboRepo)
 
 	outletService := services.NewOutletService(outletRepo)
-	tableService := services.NewTableService(tableRepo)
+	tableService := services.NewTableService(tableRepo, broker)
 	staffService := services.NewStaffService(staffRepo)
 
+	// Manager pertama untuk instalasi baru, diabaikan jika sudah ada manager aktif
+	if pin := os.Getenv("POS_BOOTSTRAP_MANAGER_PIN"); pin != "" {
+		managerID, err := staffService.BootstrapManager(context.Background(), os.Getenv("POS_BOOTSTRAP_MANAGER_NAME"), pin)
+		if err != nil {
+			log.Fatalf("Gagal membuat manager awal: %v", err)
+		}
+		if managerID != 0 {
+			log.Printf("Manager awal dibuat dengan staff id %d, hapus POS_BOOTSTRAP_MANAGER_PIN setelah login", managerID)
+		}
+	}
+
 	customerService := services.NewCustomerService(customerRepo)
 	customerVisitService := services.NewCustomerVisitService(customerVisitRepo)
-	reservationService := services.NewReservationService(reservationRepo)
+	reservationService := services.NewReservationService(reservationRepo, tableService, broker)
+	waitlistService := services.NewWaitlistService(waitlistRepo, reservationService, tableService, broker)
+
+	OrderService := services.NewOrderService(orderRepo, authService, tableService, broker)
+	kitchenService := services.NewKitchenService(kitchenRepo, broker, printers)
+	billService := services.NewBillService(billRepo, authService, tableService, broker)
+	tableTfService := services.NewTableTransferService(tableTfRepo, tableService, broker)
+	salesAnalysisService := services.NewSalesAnalysisService(salesAnalysisRepo)
+	cashDrawerService := services.NewCashDrawerService(cashDrawerRepo, broker)
+
+	// Job agregasi penjualan harian (in-process)
+	salesAnalysisService.StartScheduler(context.Background(), time.Hour)
+
+	// Worker no-show reservasi & penandaan meja reserved (in-process)
+	reservationService.StartLifecycle(context.Background(), time.Minute)
 
-	OrderService := services.NewOrderService(orderRepo)
-	billService := services.NewBillService(billRepo)
-	tableTfService := services.NewTableTransferService(tableTfRepo)
+	// Spooler cetak tiket dapur dengan retry (in-process)
+	printing.NewSpooler(services.NewKitchenPrintQueue(kitchenRepo, broker), printers).Start(context.Background(), 2*time.Second)
 
 	// Handler init
+	authHandler := handlers.NewAuthHandler(authService)
 	menuHandler := handlers.NewMenuItemHandler(menuService)
 	categoryHandler := handlers.NewMenuCategoryHandler(categoryService)
 	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
+	stockMovementHandler := handlers.NewStockMovementHandler(stockMovementService)
 	menuIngredientHandler := handlers.NewMenuIngredientHandler(menuIngredientService)
+	modifierHandler := handlers.NewModifierHandler(modifierService)
+	comboHandler := handlers.NewComboHandler(comboService)
 
 	outletHandler := handlers.NewOutletHandler(outletService)
 	tableHandler := handlers.NewTableHandler(tableService)
@@ -75,17 +156,28 @@ func main() {
 	customerHandler := handlers.NewCustomerHandler(customerService)
 	customerVisitHandler := handlers.NewCustomerVisitHandler(customerVisitService)
 	reservationHandler := handlers.NewReservationHandler(reservationService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
 
 	orderHandler := handlers.NewOrderHandler(OrderService)
+	kitchenHandler := handlers.NewKitchenHandler(kitchenService)
 	billHandler := handlers.NewBillHandler(billService)
 	tableTfHandler := handlers.NewTableTransferHandler(tableTfService)
+	salesAnalysisHandler := handlers.NewSalesAnalysisHandler(salesAnalysisService)
+	cashDrawerHandler := handlers.NewCashDrawerHandler(cashDrawerService)
+	eventHandler := handlers.NewEventHandler(broker)
 
 	// Create and Start server
 	srv := server.NewServer(
+		authService,
+		authHandler,
+
 		menuHandler,
 		categoryHandler,
 		ingredientHandler,
+		stockMovementHandler,
 		menuIngredientHandler,
+		modifierHandler,
+		comboHandler,
 
 		outletHandler,
 		tableHandler,
@@ -94,12 +186,27 @@ func main() {
 		customerHandler,
 		customerVisitHandler,
 		reservationHandler,
+		waitlistHandler,
 
 		orderHandler,
+		kitchenHandler,
 		billHandler,
 		tableTfHandler,
+		salesAnalysisHandler,
+		cashDrawerHandler,
+		eventHandler,
 	)
 
+	// IP client (untuk batas percobaan PIN per IP) hanya diambil dari X-Forwarded-For jika request datang dari
+	// reverse proxy di POS_TRUSTED_PROXIES, contoh "127.0.0.1,10.0.0.0/8"; kosong = pakai alamat koneksi langsung
+	var trustedProxies []string
+	if v := os.Getenv("POS_TRUSTED_PROXIES"); v != "" {
+		trustedProxies = strings.Split(v, ",")
+	}
+	if err := srv.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("POS_TRUSTED_PROXIES tidak valid: %v", err)
+	}
+
 	log.Printf("Server starting on port 8080")
 	if err := srv.Run(":8080"); err != nil {
 		log.Fatalf("Server failed: %v", err)
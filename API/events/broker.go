@@ -0,0 +1,86 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Jenis event domain yang dikirim ke client
+const (
+	OrderCreated       = "order.created"
+	OrderItemAdded     = "order.item_added"
+	OrderItemVoided    = "order.item_voided"
+	OrderStatusChanged = "order.status_changed"
+	OrdersMerged       = "order.merged"
+	TableStatusChanged = "table.status_changed"
+	TableTransferred   = "table.transferred"
+	BillPaid           = "bill.paid"
+	BillRefunded       = "bill.refunded"
+	BillVoided         = "bill.voided"
+	KitchenItemBumped  = "kitchen.item_bumped"
+	KitchenFired       = "kitchen.tickets_fired"
+	KitchenPrintFailed = "kitchen.print_failed"
+	DrawerOpened       = "cash_drawer.opened"
+	DrawerClosed       = "cash_drawer.closed"
+	WaitlistUpdated    = "waitlist.updated"
+	ReservationChanged = "reservation.status_changed"
+)
+
+type Event struct {
+	Type       string    `json:"type"`
+	OutletID   int       `json:"outlet_id"`
+	Data       any       `json:"data"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Broker adalah pub/sub in-process, setiap subscriber bisa memfilter event per outlet
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]int // channel -> outlet_id filter (0 = semua outlet)
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]int)}
+}
+
+// Subscribe mendaftarkan subscriber baru, panggil fungsi yang dikembalikan untuk berhenti
+func (b *Broker) Subscribe(outletID int) (<-chan Event, func()) {
+	ch := make(chan Event, 32)
+
+	b.mu.Lock()
+	b.subscribers[ch] = outletID
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish mengirim event ke semua subscriber yang cocok. Subscriber yang lambat
+// (buffer penuh) dilewati agar request yang mem-publish tidak ikut tertahan.
+func (b *Broker) Publish(eventType string, outletID int, data any) {
+	if b == nil {
+		return
+	}
+
+	evt := Event{Type: eventType, OutletID: outletID, Data: data, OccurredAt: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch, filter := range b.subscribers {
+		if filter != 0 && filter != outletID {
+			continue
+		}
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	service *services.AuthService
+}
+
+func NewAuthHandler(service *services.AuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+type LoginRequest struct {
+	StaffID int    `json:"staff_id" binding:"required"`
+	PinCode string `json:"pin_code" binding:"required"`
+}
+
+// Login godoc
+// @Summary Login staff menggunakan ID dan PIN
+// @Description PIN salah berulang kali untuk staff yang sama atau dari IP yang sama mengunci login sementara (429).
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Kredensial staff"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, claims, err := h.service.Login(c.Request.Context(), req.StaffID, req.PinCode, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrTooManyAttempts) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal login staff %d: %v", req.StaffID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal login"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"expires_at": claims.ExpiresAt,
+		"staff_id":   claims.StaffID,
+		"name":       claims.Name,
+		"role":       claims.Role,
+	})
+}
@@ -2,9 +2,16 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"pos-restaurant/middleware"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/pms"
+	"pos-restaurant/receipt"
+	"pos-restaurant/repositories"
 	"pos-restaurant/services"
 	"strconv"
 
@@ -20,19 +27,23 @@ func NewBillHandler(service *services.BillService) *BillHandler {
 }
 
 type CreateBillRequest struct {
-	OrderID        int     `json:"order_id" binding:"required"`
-	DiscountAmount float64 `json:"discount_amount"`
+	OrderID        int          `json:"order_id" binding:"required"`
+	DiscountAmount money.Amount `json:"discount_amount" binding:"gte=0"`
 }
 
 // Create godoc
 // @Summary Buat tagihan untuk sebuah order
+// @Description Order settled, void atau merged, atau yang sudah punya bill aktif, ditolak (409).
 // @Tags Bills
 // @Accept json
 // @Produce json
 // @Param request body CreateBillRequest true "Data tagihan"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills [post]
 func (h *BillHandler) Create(c *gin.Context) {
 	var req CreateBillRequest
@@ -44,6 +55,14 @@ func (h *BillHandler) Create(c *gin.Context) {
 
 	billID, err := h.service.Create(c.Request.Context(), req.OrderID, req.DiscountAmount)
 	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrOrderNotBillable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+			return
+		}
 		log.Printf("Gagal membuat tagihan untuk order %d: %v", req.OrderID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat tagihan"})
 		return
@@ -52,15 +71,61 @@ func (h *BillHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "Tagihan berhasil dibuat", "bill_id": billID})
 }
 
+// Preview godoc
+// @Summary Hitung rincian tagihan order tanpa membuat bill
+// @Description Memakai aturan pajak, service charge dan pembulatan yang sama dengan pembuatan bill
+// @Tags Bills
+// @Produce json
+// @Param order_id query int true "ID order"
+// @Param discount_amount query number false "Diskon"
+// @Success 200 {object} pricing.Breakdown
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/preview [get]
+func (h *BillHandler) Preview(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Query("order_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id tidak valid"})
+		return
+	}
+	var discount money.Amount
+	if d := c.Query("discount_amount"); d != "" {
+		discount, err = money.Parse(d)
+		if err != nil || discount < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "discount_amount tidak valid"})
+			return
+		}
+	}
+
+	breakdown, err := h.service.Preview(c.Request.Context(), orderID, discount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal menghitung preview tagihan order %d: %v", orderID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung tagihan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
 // CreateSplit godoc
 // @Summary Buat tagihan split dari satu order
+// @Description Mode: items (item utuh), quantity (qty item dibagi), seat (per kursi, hidangan bersama dibagi rata), even (dibagi rata ke N tamu). Seluruh item aktif order harus terbagi. Bill asal (original_bill_id) harus open dan belum dibayar, diskonnya dibagi ke bill split jika split tidak menyebut diskon. Tanpa bill asal, order tidak boleh sudah punya bill aktif.
 // @Tags Bills
 // @Accept json
 // @Produce json
 // @Param request body models.SplitBillRequest true "Data split bill"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills/split [post]
 func (h *BillHandler) CreateSplit(c *gin.Context) {
 	var req models.SplitBillRequest
@@ -72,6 +137,17 @@ func (h *BillHandler) CreateSplit(c *gin.Context) {
 
 	billIDs, err := h.service.CreateSplit(c.Request.Context(), req)
 	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidSplit):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, repositories.ErrOrderNotBillable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+			return
+		}
 		log.Printf("Gagal membuat split bill: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat split bill"})
 		return
@@ -80,12 +156,53 @@ func (h *BillHandler) CreateSplit(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "Split bill berhasil dibuat", "bill_ids": billIDs})
 }
 
+type MergeBillsRequest struct {
+	BillIDs []int  `json:"bill_ids" binding:"required,min=2"`
+	Reason  string `json:"reason"`
+}
+
+// Merge godoc
+// @Summary Gabungkan bill open dari beberapa order/meja menjadi satu bill
+// @Description Order bill pertama menjadi order tujuan, order lain di-merge ke sana lalu bill asal di-void dan diganti satu bill baru dengan diskon gabungan.
+// @Tags Bills
+// @Accept json
+// @Produce json
+// @Param request body MergeBillsRequest true "Bill yang digabung"
+// @Success 201 {object} models.MergeResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/merge [post]
+func (h *BillHandler) Merge(c *gin.Context) {
+	var req MergeBillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Merge(c.Request.Context(), req.BillIDs, middleware.StaffID(c), req.Reason)
+	if err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal menggabungkan bill %v: %v", req.BillIDs, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menggabungkan bill"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // List godoc
 // @Summary Ambil semua tagihan
 // @Tags Bills
 // @Produce json
 // @Success 200 {array} models.Bill
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills [get]
 func (h *BillHandler) List(c *gin.Context) {
 	bills, err := h.service.List(c.Request.Context())
@@ -107,6 +224,7 @@ func (h *BillHandler) List(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills/{id} [get]
 func (h *BillHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -133,38 +251,63 @@ func (h *BillHandler) GetByID(c *gin.Context) {
 
 // Delete godoc
 // @Summary Soft delete tagihan
+// @Description Hanya bill open yang belum ada pembayaran maupun refund. Bill split (induk atau pecahannya) tidak bisa di-void.
 // @Tags Bills
 // @Produce json
 // @Param id path int true "ID tagihan"
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills/{id} [delete]
 func (h *BillHandler) Delete(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
 	if err := h.service.SoftDelete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bill"})
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bill tidak ditemukan"})
+		case errors.Is(err, repositories.ErrBillNotVoidable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal void bill %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bill"})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Bill deleted"})
 }
 
 type BillPaymentRequest struct {
-	BillID               int     `json:"bill_id" binding:"required"`
-	PaymentMethod        string  `json:"payment_method" binding:"required"`
-	Amount               float64 `json:"amount" binding:"required"`
-	ReferenceNumber      string  `json:"reference_number"`
-	RoomChargeApprovedBy int     `json:"room_charge_approved_by"`
+	BillID               int          `json:"bill_id" binding:"required"`
+	PaymentMethod        string       `json:"payment_method" binding:"required,oneof=cash credit_card debit_card room_charge voucher split"`
+	Amount               money.Amount `json:"amount" binding:"required,gt=0"` // cash: uang diterima (termasuk tip), lainnya: nominal untuk bill tanpa tip
+	TipAmount            money.Amount `json:"tip_amount" binding:"gte=0"`
+	ReferenceNumber      string       `json:"reference_number"`
+	RoomChargeApprovedBy int          `json:"room_charge_approved_by"`
 }
 
 // Pay godoc
 // @Summary Proses pembayaran tagihan
+// @Description Hanya bill open/partial. Cash boleh melebihi sisa tagihan (selisih jadi kembalian), metode lain maksimal sebesar sisa tagihan. Tip dicatat terpisah.
+// @Description room_charge: reference_number berisi nomor kamar (default hotel_room order), divalidasi dan diposting ke PMS jika integrasi aktif.
+// @Description Pembayaran tercatat ke sesi laci kas kasir yang terbuka di outlet bill, cash ditolak (409) jika kasir belum membuka laci.
 // @Tags Bills
 // @Accept json
 // @Produce json
 // @Param request body BillPaymentRequest true "Data pembayaran"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} models.PaymentReceipt
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Security BearerAuth
 // @Router /bills/pay [post]
 func (h *BillHandler) Pay(c *gin.Context) {
 	var req BillPaymentRequest
@@ -177,16 +320,216 @@ func (h *BillHandler) Pay(c *gin.Context) {
 		BillID:               req.BillID,
 		PaymentMethod:        req.PaymentMethod,
 		Amount:               req.Amount,
+		TipAmount:            req.TipAmount,
 		ReferenceNumber:      sql.NullString{String: req.ReferenceNumber, Valid: req.ReferenceNumber != ""},
 		RoomChargeApprovedBy: sql.NullInt64{Int64: int64(req.RoomChargeApprovedBy), Valid: req.RoomChargeApprovedBy != 0},
 	}
 
-	err := h.service.Pay(c.Request.Context(), payment)
+	receipt, err := h.service.Pay(c.Request.Context(), payment, middleware.StaffID(c))
+	if err != nil {
+		if status, ok := pmsErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		switch {
+		case errors.Is(err, repositories.ErrOverpayment):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bill tidak ditemukan"})
+		case errors.Is(err, repositories.ErrBillNotPayable), errors.Is(err, repositories.ErrNoDrawerSession):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal memproses pembayaran: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses pembayaran"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+type RefundRequest struct {
+	PaymentID   int          `json:"payment_id" binding:"required"`
+	Amount      money.Amount `json:"amount" binding:"gte=0"` // 0 = seluruh sisa pembayaran
+	Reason      string       `json:"reason" binding:"required"`
+	ApprovedBy  int          `json:"approved_by" binding:"required"`
+	ApproverPin string       `json:"approver_pin" binding:"required"`
+}
+
+// Refund godoc
+// @Summary Refund sebagian atau seluruh satu pembayaran
+// @Description Wajib disetujui manager dengan PIN. Dikembalikan lewat metode pembayaran asal (room_charge = kredit ke folio kamar), tagihan bersih bill berkurang dan bill lunas yang seluruhnya dikembalikan menjadi refunded.
+// @Description Refund cash dibayarkan dari laci kas yang sedang dibuka staf di outlet bill.
+// @Description Refund atas bill partial hanya membatalkan pembayaran (is_reversal): sisa tagihan terbuka lagi dan tidak dihitung refund penjualan.
+// @Tags Bills
+// @Accept json
+// @Produce json
+// @Param request body RefundRequest true "Data refund"
+// @Success 201 {object} models.BillRefund
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/refund [post]
+func (h *BillHandler) Refund(c *gin.Context) {
+	var req RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	refund := &models.BillRefund{
+		PaymentID:  req.PaymentID,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		ApprovedBy: req.ApprovedBy,
+	}
+	if err := h.service.Refund(c.Request.Context(), refund, req.ApproverPin, middleware.StaffID(c)); err != nil {
+		if status, ok := pmsErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		switch {
+		case errors.Is(err, repositories.ErrInvalidRefund):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrApprovalDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrTooManyAttempts):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrNoDrawerSession):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pembayaran tidak ditemukan"})
+		default:
+			log.Printf("Gagal refund pembayaran %d: %v", req.PaymentID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses refund"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, refund)
+}
+
+// ListRefunds godoc
+// @Summary Daftar refund satu bill
+// @Tags Bills
+// @Produce json
+// @Param id path int true "ID bill"
+// @Success 200 {array} models.BillRefund
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/{id}/refunds [get]
+func (h *BillHandler) ListRefunds(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	refunds, err := h.service.ListRefunds(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Gagal mengambil refund bill %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil refund"})
+		return
+	}
+
+	c.JSON(http.StatusOK, refunds)
+}
+
+// Receipt godoc
+// @Summary Cetak struk bill
+// @Description Format escpos (byte stream printer thermal), html atau pdf. Setiap cetak dicatat, cetakan berikutnya ditandai CETAK ULANG. preview=true tidak dicatat dan ditandai PREVIEW / BUKAN STRUK.
+// @Tags Bills
+// @Produce octet-stream
+// @Produce html
+// @Produce application/pdf
+// @Param id path int true "ID bill"
+// @Param format query string false "escpos, html atau pdf (default html)"
+// @Param width query int false "Lebar kertas dalam karakter, 32 (58mm) sampai 64, default 42 (80mm)"
+// @Param preview query bool false "Tampilkan tanpa mencatat cetak"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/{id}/receipt [get]
+func (h *BillHandler) Receipt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+	format := c.DefaultQuery("format", receipt.FormatHTML)
+	width := receipt.DefaultWidth
+	if w := c.Query("width"); w != "" {
+		width, err = strconv.Atoi(w)
+		if err != nil || width < receipt.MinWidth || width > receipt.MaxWidth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "width harus antara 32 dan 64"})
+			return
+		}
+	}
+	preview := c.Query("preview") == "true"
+
+	body, contentType, err := h.service.Receipt(c.Request.Context(), id, format, width, middleware.StaffID(c), preview)
 	if err != nil {
-		log.Printf("Gagal memproses pembayaran: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses pembayaran"})
+		switch {
+		case errors.Is(err, receipt.ErrUnknownFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bill tidak ditemukan"})
+		default:
+			log.Printf("Gagal mencetak struk bill %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencetak struk"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Pembayaran berhasil diproses"})
+	if format != receipt.FormatHTML {
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="bill-%d.%s"`, id, format))
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// LookupRoomGuest godoc
+// @Summary Cek tamu kamar di PMS sebelum room charge
+// @Tags Bills
+// @Produce json
+// @Param room path string true "Nomor kamar"
+// @Success 200 {object} pms.Guest
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Security BearerAuth
+// @Router /bills/room-guest/{room} [get]
+func (h *BillHandler) LookupRoomGuest(c *gin.Context) {
+	guest, err := h.service.LookupRoomGuest(c.Request.Context(), c.Param("room"))
+	if err != nil {
+		if errors.Is(err, pms.ErrGuestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := pmsErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal cek tamu kamar %s: %v", c.Param("room"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal cek tamu kamar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, guest)
+}
+
+// pmsErrorStatus memetakan error PMS: penolakan (tamu tidak ada, sudah check-out, limit) = 400, gangguan = 502
+func pmsErrorStatus(err error) (int, bool) {
+	switch {
+	case pms.IsRejection(err):
+		return http.StatusBadRequest, true
+	case errors.Is(err, pms.ErrUnavailable):
+		return http.StatusBadGateway, true
+	}
+	return 0, false
 }
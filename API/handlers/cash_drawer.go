@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/middleware"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/repositories"
+	"pos-restaurant/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CashDrawerHandler struct {
+	service *services.CashDrawerService
+}
+
+func NewCashDrawerHandler(service *services.CashDrawerService) *CashDrawerHandler {
+	return &CashDrawerHandler{service: service}
+}
+
+type OpenDrawerRequest struct {
+	OutletID     int          `json:"outlet_id" binding:"required"`
+	OpeningFloat money.Amount `json:"opening_float" binding:"gte=0"`
+}
+
+// Open godoc
+// @Summary Buka sesi laci kas (mulai shift kasir)
+// @Description Kasir membuka laci dengan modal awal. Selama sesi terbuka semua pembayaran dan refund yang diterima kasir di outlet tersebut tercatat ke sesi, pembayaran cash wajib lewat sesi.
+// @Tags Cash Drawer
+// @Accept json
+// @Produce json
+// @Param request body OpenDrawerRequest true "Outlet dan modal awal"
+// @Success 201 {object} models.CashDrawerSession
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /cash-drawers/open [post]
+func (h *CashDrawerHandler) Open(c *gin.Context) {
+	var req OpenDrawerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.Open(c.Request.Context(), req.OutletID, middleware.StaffID(c), req.OpeningFloat)
+	if err != nil {
+		if status, ok := drawerErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal membuka laci kas: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuka laci kas"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// Current godoc
+// @Summary Sesi laci kas kasir yang sedang terbuka
+// @Description Tanpa angka penjualan agar hitung saat tutup sesi tetap buta
+// @Tags Cash Drawer
+// @Produce json
+// @Success 200 {object} models.CashDrawerSession
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /cash-drawers/current [get]
+func (h *CashDrawerHandler) Current(c *gin.Context) {
+	session, err := h.service.Current(c.Request.Context(), middleware.StaffID(c))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Belum ada laci kas yang dibuka"})
+			return
+		}
+		log.Printf("Gagal mengambil sesi laci kas: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil sesi laci kas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+type CloseDrawerRequest struct {
+	Counts []models.DrawerCount `json:"counts" binding:"required,dive"`
+	Notes  string               `json:"notes"`
+}
+
+// Close godoc
+// @Summary Tutup sesi laci kas dengan hitung buta
+// @Description Kasir mengisi jumlah fisik per metode (cash, slip kartu, voucher) tanpa melihat angka sistem. Metode yang tidak diisi dihitung 0. Hasilnya laporan Z dengan selisih lebih/kurang per metode.
+// @Tags Cash Drawer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID sesi"
+// @Param request body CloseDrawerRequest true "Hasil hitung"
+// @Success 200 {object} models.DrawerReport
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /cash-drawers/{id}/close [post]
+func (h *CashDrawerHandler) Close(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req CloseDrawerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Close(c.Request.Context(), id, req.Counts, middleware.StaffID(c), middleware.StaffRole(c), req.Notes)
+	if err != nil {
+		if status, ok := drawerErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal menutup laci kas %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menutup laci kas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// List godoc
+// @Summary Daftar sesi laci kas
+// @Tags Cash Drawer
+// @Produce json
+// @Param outlet_id query int false "Filter outlet"
+// @Param status query string false "open atau closed"
+// @Success 200 {array} models.CashDrawerSession
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /cash-drawers [get]
+func (h *CashDrawerHandler) List(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && status != models.DrawerOpen && status != models.DrawerClosed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter status tidak valid"})
+		return
+	}
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+
+	sessions, err := h.service.List(c.Request.Context(), outletID, status)
+	if err != nil {
+		log.Printf("Gagal mengambil sesi laci kas: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil sesi laci kas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Report godoc
+// @Summary Laporan X/Z satu sesi laci kas
+// @Description Sesi terbuka menghasilkan laporan X (posisi sementara), sesi yang sudah ditutup laporan Z beserta hasil hitung dan selisihnya
+// @Tags Cash Drawer
+// @Produce json
+// @Param id path int true "ID sesi"
+// @Success 200 {object} models.DrawerReport
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /cash-drawers/{id}/report [get]
+func (h *CashDrawerHandler) Report(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	report, err := h.service.Report(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sesi laci kas tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal menyusun laporan laci kas %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun laporan laci kas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// XReport godoc
+// @Summary Laporan X outlet (tengah shift)
+// @Description Posisi semua laci kas yang masih terbuka di outlet tanpa menutup sesi
+// @Tags Reports
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Success 200 {object} models.OutletDrawerReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/x-report [get]
+func (h *CashDrawerHandler) XReport(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+
+	report, err := h.service.OutletX(c.Request.Context(), outletID)
+	if err != nil {
+		log.Printf("Gagal menyusun laporan X outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun laporan X"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ZReport godoc
+// @Summary Laporan Z outlet (akhir shift)
+// @Description Gabungan laporan Z semua sesi laci kas outlet yang ditutup pada tanggal tersebut, termasuk selisih lebih/kurang per metode
+// @Tags Reports
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Param date query string false "Tanggal tutup sesi (YYYY-MM-DD), default hari ini"
+// @Success 200 {object} models.OutletDrawerReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/z-report [get]
+func (h *CashDrawerHandler) ZReport(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if v := c.Query("date"); v != "" {
+		if date, err = time.ParseInLocation("2006-01-02", v, time.Local); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format 'date' tidak valid (YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	report, err := h.service.OutletZ(c.Request.Context(), outletID, date)
+	if err != nil {
+		log.Printf("Gagal menyusun laporan Z outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun laporan Z"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// drawerErrorStatus memetakan error laci kas ke HTTP status
+func drawerErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, true
+	case errors.Is(err, services.ErrDrawerNotOwner):
+		return http.StatusForbidden, true
+	case errors.Is(err, repositories.ErrDrawerAlreadyOpen), errors.Is(err, repositories.ErrDrawerClosed),
+		errors.Is(err, repositories.ErrNoDrawerSession):
+		return http.StatusConflict, true
+	}
+	return 0, false
+}
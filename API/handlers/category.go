@@ -31,6 +31,7 @@ type CreateCategoryRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/category [post]
 func (h *MenuCategoryHandler) CreateCategory(c *gin.Context) {
 	var req CreateCategoryRequest
@@ -55,6 +56,7 @@ func (h *MenuCategoryHandler) CreateCategory(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.MenuCategory
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/category [get]
 func (h *MenuCategoryHandler) ListCategories(c *gin.Context) {
 	categories, err := h.service.ListCategories(c.Request.Context())
@@ -73,6 +75,7 @@ func (h *MenuCategoryHandler) ListCategories(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/category/{id} [delete]
 func (h *MenuCategoryHandler) DeleteCategory(c *gin.Context) {
 	idStr := c.Param("id")
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ComboHandler struct {
+	service *services.ComboService
+}
+
+func NewComboHandler(service *services.ComboService) *ComboHandler {
+	return &ComboHandler{service: service}
+}
+
+type ComboRequest struct {
+	SKU         string             `json:"sku"`
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	Price       money.Amount       `json:"price" binding:"gte=0"`
+	IsActive    *bool              `json:"is_active"` // default true
+	Slots       []ComboSlotRequest `json:"slots" binding:"required,min=1,dive"`
+}
+
+type ComboSlotRequest struct {
+	Name    string                   `json:"name" binding:"required"`
+	Options []ComboSlotOptionRequest `json:"options" binding:"required,min=1,dive"`
+}
+
+type ComboSlotOptionRequest struct {
+	MenuItemID int          `json:"menu_item_id" binding:"required"`
+	PriceDelta money.Amount `json:"price_delta"`
+	IsDefault  bool         `json:"is_default"`
+}
+
+func (req *ComboRequest) toModel() *models.Combo {
+	combo := &models.Combo{
+		SKU:         sql.NullString{String: req.SKU, Valid: req.SKU != ""},
+		Name:        req.Name,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Price:       req.Price,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	for _, s := range req.Slots {
+		slot := models.ComboSlot{Name: s.Name}
+		for _, o := range s.Options {
+			slot.Options = append(slot.Options, models.ComboSlotOption{
+				MenuItemID: o.MenuItemID,
+				PriceDelta: o.PriceDelta,
+				IsDefault:  o.IsDefault,
+			})
+		}
+		combo.Slots = append(combo.Slots, slot)
+	}
+	return combo
+}
+
+// Create godoc
+// @Summary Tambah paket / set menu
+// @Description Slot dengan satu pilihan menjadi komponen tetap, slot dengan beberapa pilihan dipilih saat order
+// @Tags Combos
+// @Accept json
+// @Produce json
+// @Param request body ComboRequest true "Data paket"
+// @Success 201 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /combos [post]
+func (h *ComboHandler) Create(c *gin.Context) {
+	var req ComboRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := h.service.Create(c.Request.Context(), req.toModel())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCombo) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal membuat paket: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat paket"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// List godoc
+// @Summary Tampilkan semua paket beserta slot dan pilihannya
+// @Tags Combos
+// @Produce json
+// @Param active query bool false "Hanya paket aktif"
+// @Success 200 {array} models.Combo
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /combos [get]
+func (h *ComboHandler) List(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+
+	combos, err := h.service.List(c.Request.Context(), activeOnly)
+	if err != nil {
+		log.Printf("Gagal mengambil paket: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil paket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, combos)
+}
+
+// GetByID godoc
+// @Summary Detail paket
+// @Tags Combos
+// @Produce json
+// @Param id path int true "ID paket"
+// @Success 200 {object} models.Combo
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /combos/{id} [get]
+func (h *ComboHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	combo, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Paket tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal mengambil paket %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil paket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, combo)
+}
+
+// Update godoc
+// @Summary Ubah paket (slot dan pilihan diganti seluruhnya)
+// @Tags Combos
+// @Accept json
+// @Produce json
+// @Param id path int true "ID paket"
+// @Param request body ComboRequest true "Data paket"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /combos/{id} [put]
+func (h *ComboHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req ComboRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	combo := req.toModel()
+	combo.ID = id
+	if err := h.service.Update(c.Request.Context(), combo); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCombo):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Paket tidak ditemukan"})
+		default:
+			log.Printf("Gagal update paket %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update paket"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Paket berhasil diupdate"})
+}
+
+// Delete godoc
+// @Summary Hapus paket (soft delete)
+// @Tags Combos
+// @Produce json
+// @Param id path int true "ID paket"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /combos/{id} [delete]
+func (h *ComboHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Paket tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal hapus paket %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus paket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Paket berhasil dihapus"})
+}
@@ -37,6 +37,7 @@ type CustomerRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /customers [post]
 func (h *CustomerHandler) Create(c *gin.Context) {
 	var req CustomerRequest
@@ -70,6 +71,7 @@ func (h *CustomerHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Customer
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /customers [get]
 func (h *CustomerHandler) List(c *gin.Context) {
 	data, err := h.service.GetAllCustomers(c.Request.Context())
@@ -89,6 +91,7 @@ func (h *CustomerHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Customer
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /customers/{id} [get]
 func (h *CustomerHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -118,6 +121,7 @@ func (h *CustomerHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /customers/{id} [put]
 func (h *CustomerHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -160,6 +164,7 @@ func (h *CustomerHandler) Update(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /customers/{id} [delete]
 func (h *CustomerHandler) SoftDelete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -40,6 +40,7 @@ type CustomerVisitRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /visits [post]
 func (h *CustomerVisitHandler) Create(c *gin.Context) {
 	var req CustomerVisitRequest
@@ -76,6 +77,7 @@ func (h *CustomerVisitHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.CustomerVisit
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /visits [get]
 func (h *CustomerVisitHandler) List(c *gin.Context) {
 	data, err := h.service.List(c.Request.Context())
@@ -94,6 +96,7 @@ func (h *CustomerVisitHandler) List(c *gin.Context) {
 // @Param id path int true "ID Kunjungan"
 // @Success 200 {object} models.CustomerVisit
 // @Failure 404 {object} map[string]string
+// @Security BearerAuth
 // @Router /visits/{id} [get]
 func (h *CustomerVisitHandler) GetByID(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
@@ -116,6 +119,7 @@ func (h *CustomerVisitHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /visits/{id} [put]
 func (h *CustomerVisitHandler) Update(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
@@ -155,6 +159,7 @@ func (h *CustomerVisitHandler) Update(c *gin.Context) {
 // @Param id path int true "ID Kunjungan"
 // @Success 200 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /visits/{id} [delete]
 func (h *CustomerVisitHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"pos-restaurant/events"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	broker *events.Broker
+}
+
+func NewEventHandler(broker *events.Broker) *EventHandler {
+	return &EventHandler{broker: broker}
+}
+
+// Stream godoc
+// @Summary Stream event real-time (Server-Sent Events)
+// @Description Event: order.created, order.item_added, order.item_voided, order.status_changed, table.status_changed, table.transferred, bill.paid, kitchen.item_bumped
+// @Tags Events
+// @Produce text/event-stream
+// @Param outlet_id query int false "Hanya event dari outlet ini"
+// @Param access_token query string false "Token sesi untuk EventSource yang tidak bisa mengirim header Authorization"
+// @Success 200 {object} events.Event
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /events [get]
+func (h *EventHandler) Stream(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+
+	ch, unsubscribe := h.broker.Subscribe(outletID)
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case evt, ok := <-ch:
+			if !ok {
+				return false
+			}
+			c.SSEvent(evt.Type, evt)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
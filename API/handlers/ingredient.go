@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
+	"pos-restaurant/middleware"
 	"pos-restaurant/models"
 	"pos-restaurant/services"
 	"strconv"
@@ -37,6 +38,7 @@ type CreateIngredientRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /ingredients [post]
 func (h *IngredientHandler) CreateIngredient(c *gin.Context) {
 	var req CreateIngredientRequest
@@ -55,7 +57,7 @@ func (h *IngredientHandler) CreateIngredient(c *gin.Context) {
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 	}
 
-	id, err := h.service.CreateIngredient(c.Request.Context(), ingredient)
+	id, err := h.service.CreateIngredient(c.Request.Context(), ingredient, middleware.StaffID(c))
 	if err != nil {
 		log.Printf("Error creating ingredient: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat ingredient"})
@@ -71,6 +73,7 @@ func (h *IngredientHandler) CreateIngredient(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Ingredient
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /ingredients [get]
 func (h *IngredientHandler) ListIngredients(c *gin.Context) {
 	ingredients, err := h.service.ListIngredients(c.Request.Context())
@@ -90,6 +93,7 @@ func (h *IngredientHandler) ListIngredients(c *gin.Context) {
 // @Success 200 {object} models.Ingredient
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /ingredients/{id} [get]
 func (h *IngredientHandler) GetIngredientByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -118,6 +122,7 @@ func (h *IngredientHandler) GetIngredientByID(c *gin.Context) {
 // @Success 200 {object} models.Ingredient
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /ingredients/{id} [get]
 func (h *IngredientHandler) UpdateIngredient(c *gin.Context) {
 	idStr := c.Param("id")
@@ -145,7 +150,7 @@ func (h *IngredientHandler) UpdateIngredient(c *gin.Context) {
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 	}
 
-	if err := h.service.UpdateIngredient(c.Request.Context(), ingredient); err != nil {
+	if err := h.service.UpdateIngredient(c.Request.Context(), ingredient, middleware.StaffID(c)); err != nil {
 		log.Printf("Gagal update ingredient ID %d: %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate ingredient"})
 		return
@@ -162,6 +167,7 @@ func (h *IngredientHandler) UpdateIngredient(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /ingredients/{id} [delete]
 func (h *IngredientHandler) DeleteIngredient(c *gin.Context) {
 	idStr := c.Param("id")
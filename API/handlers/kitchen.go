@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"pos-restaurant/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KitchenHandler struct {
+	service *services.KitchenService
+}
+
+func NewKitchenHandler(service *services.KitchenService) *KitchenHandler {
+	return &KitchenHandler{service: service}
+}
+
+type CreateStationRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Printer      string `json:"printer"` // nama printer di POS_PRINTERS, kosong = hanya KDS
+	PrinterWidth int    `json:"printer_width" binding:"omitempty,min=32,max=64"`
+}
+
+type StationPrinterRequest struct {
+	Printer      string `json:"printer"`
+	PrinterWidth int    `json:"printer_width" binding:"omitempty,min=32,max=64"`
+}
+
+type AssignCategoriesRequest struct {
+	CategoryIDs []int `json:"category_ids" binding:"required,min=1"`
+}
+
+// CreateStation godoc
+// @Summary Tambah station dapur
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param request body CreateStationRequest true "Data station"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/stations [post]
+func (h *KitchenHandler) CreateStation(c *gin.Context) {
+	var req CreateStationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	station := &models.KitchenStation{Name: req.Name, Printer: req.Printer, PrinterWidth: req.PrinterWidth}
+	id, err := h.service.CreateStation(c.Request.Context(), station)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPrinterInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal membuat station: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat station"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id, "name": station.Name})
+}
+
+// ListStations godoc
+// @Summary Tampilkan semua station dapur
+// @Tags Kitchen
+// @Produce json
+// @Success 200 {array} models.KitchenStation
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/stations [get]
+func (h *KitchenHandler) ListStations(c *gin.Context) {
+	stations, err := h.service.ListStations(c.Request.Context())
+	if err != nil {
+		log.Printf("Gagal mengambil station: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil station"})
+		return
+	}
+	c.JSON(http.StatusOK, stations)
+}
+
+// AssignCategories godoc
+// @Summary Arahkan kategori menu ke station dapur
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Station"
+// @Param request body AssignCategoriesRequest true "Daftar kategori"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/stations/{id}/categories [put]
+func (h *KitchenHandler) AssignCategories(c *gin.Context) {
+	stationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req AssignCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.AssignCategories(c.Request.Context(), stationID, req.CategoryIDs); err != nil {
+		log.Printf("Gagal assign kategori ke station %d: %v", stationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengatur kategori station"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kategori berhasil diarahkan ke station"})
+}
+
+// SetStationPrinter godoc
+// @Summary Atur printer tiket kertas untuk station dapur
+// @Description Nama printer harus terdaftar di POS_PRINTERS, kosongkan printer untuk berhenti mencetak
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Station"
+// @Param request body StationPrinterRequest true "Printer station"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/stations/{id}/printer [put]
+func (h *KitchenHandler) SetStationPrinter(c *gin.Context) {
+	stationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req StationPrinterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetStationPrinter(c.Request.Context(), stationID, req.Printer, req.PrinterWidth); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Station tidak ditemukan"})
+		case errors.Is(err, repositories.ErrPrinterInvalid):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal mengatur printer station %d: %v", stationID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengatur printer station"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Printer station berhasil diatur"})
+}
+
+// Queue godoc
+// @Summary Antrian item dapur (KDS) yang belum served
+// @Tags Kitchen
+// @Produce json
+// @Param station_id query int false "Filter station"
+// @Param outlet_id query int false "Filter outlet"
+// @Success 200 {array} models.KitchenQueueItem
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/queue [get]
+func (h *KitchenHandler) Queue(c *gin.Context) {
+	stationID, err := strconv.Atoi(c.DefaultQuery("station_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter station_id tidak valid"})
+		return
+	}
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+
+	queue, err := h.service.Queue(c.Request.Context(), stationID, outletID)
+	if err != nil {
+		log.Printf("Gagal mengambil antrian dapur: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil antrian dapur"})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// Bump godoc
+// @Summary Majukan status item dapur (queued -> cooking -> ready -> served)
+// @Description Item di tiket yang masih ditahan, item yang di-void atau item order void ditolak (409).
+// @Tags Kitchen
+// @Produce json
+// @Param id path int true "ID ticket item"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/items/{id}/bump [post]
+func (h *KitchenHandler) Bump(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	status, err := h.service.Bump(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item dapur tidak ditemukan"})
+		case errors.Is(err, repositories.ErrAlreadyServed), errors.Is(err, repositories.ErrTicketHeld),
+			errors.Is(err, repositories.ErrItemVoided):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal bump item dapur %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status item"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
+
+// PrintJobs godoc
+// @Summary Antrian cetak tiket dapur
+// @Tags Kitchen
+// @Produce json
+// @Param status query string false "pending, printing, printed atau failed"
+// @Param outlet_id query int false "Filter outlet"
+// @Success 200 {array} models.KitchenPrintJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/print-jobs [get]
+func (h *KitchenHandler) PrintJobs(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.PrintPending, models.PrintPrinting, models.PrintPrinted, models.PrintFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter status tidak valid"})
+		return
+	}
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+
+	jobs, err := h.service.PrintJobs(c.Request.Context(), status, outletID)
+	if err != nil {
+		log.Printf("Gagal mengambil antrian cetak: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil antrian cetak"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// RetryPrintJob godoc
+// @Summary Antrikan ulang job cetak yang gagal
+// @Tags Kitchen
+// @Produce json
+// @Param id path int true "ID job cetak"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/print-jobs/{id}/retry [post]
+func (h *KitchenHandler) RetryPrintJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	if err := h.service.RetryPrintJob(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job cetak tidak ditemukan"})
+		case errors.Is(err, repositories.ErrJobNotFailed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal mengulang job cetak %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengulang job cetak"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job cetak diantrikan ulang"})
+}
+
+// ReprintTicket godoc
+// @Summary Cetak ulang tiket dapur ke printer station
+// @Tags Kitchen
+// @Produce json
+// @Param id path int true "ID tiket dapur"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /kitchen/tickets/{id}/reprint [post]
+func (h *KitchenHandler) ReprintTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	if err := h.service.ReprintTicket(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tiket dapur tidak ditemukan"})
+		case errors.Is(err, repositories.ErrNoPrinter):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrTicketHeld):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Gagal cetak ulang tiket %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal cetak ulang tiket"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Tiket masuk antrian cetak"})
+}
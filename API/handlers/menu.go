@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
 	"pos-restaurant/services"
 	"strconv"
 
@@ -20,15 +21,15 @@ func NewMenuItemHandler(service *services.MenuService) *MenuItemHandler {
 }
 
 type CreateMenuItemRequest struct {
-	CategoryID      int      `json:"category_id" binding:"required"`
-	SKU             string   `json:"sku" binding:"required,max=50"`
-	Name            string   `json:"name" binding:"required,max=255"`
-	Description     string   `json:"description"`
-	Price           float64  `json:"price" binding:"required,gt=0"`
-	Cost            float64  `json:"cost" binding:"gte=0"`
-	IsActive        bool     `json:"is_active"`
-	PreparationTime *int     `json:"preparation_time"`
-	Tags            []string `json:"tags"`
+	CategoryID      int          `json:"category_id" binding:"required"`
+	SKU             string       `json:"sku" binding:"required,max=50"`
+	Name            string       `json:"name" binding:"required,max=255"`
+	Description     string       `json:"description"`
+	Price           money.Amount `json:"price" binding:"required,gt=0"`
+	Cost            money.Amount `json:"cost" binding:"gte=0"`
+	IsActive        bool         `json:"is_active"`
+	PreparationTime *int         `json:"preparation_time"`
+	Tags            []string     `json:"tags"`
 }
 
 // CreateMenuItem godoc
@@ -40,6 +41,7 @@ type CreateMenuItemRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items [post]
 func (h *MenuItemHandler) CreateMenuItem(c *gin.Context) {
 	var req CreateMenuItemRequest
@@ -82,6 +84,7 @@ func (h *MenuItemHandler) CreateMenuItem(c *gin.Context) {
 // @Success 200 {array} models.MenuItem
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items/category [get]
 func (h *MenuItemHandler) GetMenuItemsByCategory(c *gin.Context) {
 	categoryIDStr := c.Query("category_id")
@@ -108,6 +111,7 @@ func (h *MenuItemHandler) GetMenuItemsByCategory(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.MenuItem
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items [get]
 func (h *MenuItemHandler) ListMenuItems(c *gin.Context) {
 	items, err := h.service.ListMenuItems(c.Request.Context())
@@ -125,6 +129,7 @@ func (h *MenuItemHandler) ListMenuItems(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.MenuItem
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items-active [get]
 func (h *MenuItemHandler) ListActiveMenuItems(c *gin.Context) {
 	items, err := h.service.ListActiveMenuItems(c.Request.Context())
@@ -144,6 +149,7 @@ func (h *MenuItemHandler) ListActiveMenuItems(c *gin.Context) {
 // @Success 200 {array} models.MenuItem
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items/search [get]
 func (h *MenuItemHandler) SearchMenuItems(c *gin.Context) {
 	keyword := c.Query("search")
@@ -173,6 +179,7 @@ func (h *MenuItemHandler) SearchMenuItems(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items/{id} [put]
 func (h *MenuItemHandler) UpdateMenuItem(c *gin.Context) {
 	idStr := c.Param("id")
@@ -223,6 +230,7 @@ func (h *MenuItemHandler) UpdateMenuItem(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items/{id} [delete]
 func (h *MenuItemHandler) DeleteMenuItem(c *gin.Context) {
 	idStr := c.Param("id")
@@ -243,7 +251,7 @@ func (h *MenuItemHandler) DeleteMenuItem(c *gin.Context) {
 }
 
 // GetMenuDetail godoc
-// @Summary Dapatkan detail menu dengan bahan
+// @Summary Dapatkan detail menu dengan bahan dan modifier
 // @Tags Menu
 // @Produce json
 // @Param id path int true "ID Menu"
@@ -251,6 +259,7 @@ func (h *MenuItemHandler) DeleteMenuItem(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu/menu-items/detail/{id} [get]
 func (h *MenuItemHandler) GetMenuDetail(c *gin.Context) {
 	idStr := c.Param("id")
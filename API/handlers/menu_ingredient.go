@@ -35,6 +35,7 @@ type CreateMenuIngredientRequest struct {
 // @Success 201 {object} map[string]int
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu-ingredients [post]
 func (h *MenuIngredientHandler) Create(c *gin.Context) {
 	var req CreateMenuIngredientRequest
@@ -70,6 +71,7 @@ func (h *MenuIngredientHandler) Create(c *gin.Context) {
 // @Success 200 {array} models.MenuIngredient
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu-ingredients/{menu_item_id} [get]
 func (h *MenuIngredientHandler) ListByMenuItem(c *gin.Context) {
 	idStr := c.Param("menu_item_id")
@@ -100,6 +102,7 @@ func (h *MenuIngredientHandler) ListByMenuItem(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu-ingredients/{id} [put]
 func (h *MenuIngredientHandler) UpdateMenuIngredient(c *gin.Context) {
 	idStr := c.Param("id")
@@ -143,6 +146,7 @@ func (h *MenuIngredientHandler) UpdateMenuIngredient(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /menu-ingredients/{id} [delete]
 func (h *MenuIngredientHandler) DeleteMenuIngredient(c *gin.Context) {
 	idStr := c.Param("id")
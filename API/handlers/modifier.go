@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModifierHandler struct {
+	service *services.ModifierService
+}
+
+func NewModifierHandler(service *services.ModifierService) *ModifierHandler {
+	return &ModifierHandler{service: service}
+}
+
+type ModifierGroupRequest struct {
+	MenuItemID int    `json:"menu_item_id" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	MinSelect  int    `json:"min_select"`
+	MaxSelect  int    `json:"max_select" binding:"required"`
+}
+
+type ModifierOptionRequest struct {
+	Name          string       `json:"name" binding:"required"`
+	PriceDelta    money.Amount `json:"price_delta"`
+	IngredientID  int          `json:"ingredient_id"` // 0 = tidak memakai bahan tambahan
+	IngredientQty float64      `json:"ingredient_qty" binding:"gte=0"`
+	IsActive      *bool        `json:"is_active"` // default true
+}
+
+func (req *ModifierOptionRequest) toModel() *models.ModifierOption {
+	return &models.ModifierOption{
+		Name:          req.Name,
+		PriceDelta:    req.PriceDelta,
+		IngredientID:  sql.NullInt64{Int64: int64(req.IngredientID), Valid: req.IngredientID != 0},
+		IngredientQty: req.IngredientQty,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+}
+
+func modifierErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, true
+	case errors.Is(err, services.ErrInvalidModifierRule):
+		return http.StatusBadRequest, true
+	}
+	return 0, false
+}
+
+// CreateGroup godoc
+// @Summary Tambah modifier group ke menu (ukuran, kematangan, extra topping)
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Param request body ModifierGroupRequest true "Data modifier group"
+// @Success 201 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/groups [post]
+func (h *ModifierHandler) CreateGroup(c *gin.Context) {
+	var req ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	g := &models.ModifierGroup{
+		MenuItemID: req.MenuItemID,
+		Name:       req.Name,
+		MinSelect:  req.MinSelect,
+		MaxSelect:  req.MaxSelect,
+	}
+	id, err := h.service.CreateGroup(c.Request.Context(), g)
+	if err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal membuat modifier group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// UpdateGroup godoc
+// @Summary Ubah nama dan aturan min/max modifier group
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Param id path int true "ID modifier group"
+// @Param request body ModifierGroupRequest true "Data modifier group"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/groups/{id} [put]
+func (h *ModifierHandler) UpdateGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	g := &models.ModifierGroup{
+		ID:        id,
+		Name:      req.Name,
+		MinSelect: req.MinSelect,
+		MaxSelect: req.MaxSelect,
+	}
+	if err := h.service.UpdateGroup(c.Request.Context(), g); err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal update modifier group %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier group berhasil diupdate"})
+}
+
+// DeleteGroup godoc
+// @Summary Hapus modifier group (soft delete)
+// @Tags Modifiers
+// @Produce json
+// @Param id path int true "ID modifier group"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/groups/{id} [delete]
+func (h *ModifierHandler) DeleteGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	if err := h.service.DeleteGroup(c.Request.Context(), id); err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": "Modifier group tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal hapus modifier group %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier group berhasil dihapus"})
+}
+
+// CreateOption godoc
+// @Summary Tambah pilihan ke modifier group
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Param id path int true "ID modifier group"
+// @Param request body ModifierOptionRequest true "Data pilihan"
+// @Success 201 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/groups/{id}/options [post]
+func (h *ModifierHandler) CreateOption(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req ModifierOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	o := req.toModel()
+	o.GroupID = groupID
+	id, err := h.service.CreateOption(c.Request.Context(), o)
+	if err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": "Modifier group tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal membuat modifier option: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat pilihan modifier"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// UpdateOption godoc
+// @Summary Ubah pilihan modifier
+// @Tags Modifiers
+// @Accept json
+// @Produce json
+// @Param id path int true "ID modifier option"
+// @Param request body ModifierOptionRequest true "Data pilihan"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/options/{id} [put]
+func (h *ModifierHandler) UpdateOption(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req ModifierOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	o := req.toModel()
+	o.ID = id
+	if err := h.service.UpdateOption(c.Request.Context(), o); err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": "Pilihan modifier tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal update modifier option %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update pilihan modifier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pilihan modifier berhasil diupdate"})
+}
+
+// DeleteOption godoc
+// @Summary Hapus pilihan modifier (soft delete)
+// @Tags Modifiers
+// @Produce json
+// @Param id path int true "ID modifier option"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/options/{id} [delete]
+func (h *ModifierHandler) DeleteOption(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	if err := h.service.DeleteOption(c.Request.Context(), id); err != nil {
+		if status, ok := modifierErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": "Pilihan modifier tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal hapus modifier option %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus pilihan modifier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pilihan modifier berhasil dihapus"})
+}
+
+// ListByMenuItem godoc
+// @Summary Tampilkan modifier group beserta pilihannya untuk satu menu
+// @Tags Modifiers
+// @Produce json
+// @Param menu_item_id path int true "ID Menu Item"
+// @Success 200 {array} models.ModifierGroup
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /modifiers/menu-items/{menu_item_id} [get]
+func (h *ModifierHandler) ListByMenuItem(c *gin.Context) {
+	menuItemID, err := strconv.Atoi(c.Param("menu_item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	groups, err := h.service.ListByMenuItem(c.Request.Context(), menuItemID)
+	if err != nil {
+		log.Printf("Gagal mengambil modifier menu %d: %v", menuItemID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil modifier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"pos-restaurant/middleware"
 	"pos-restaurant/models"
+	"pos-restaurant/repositories"
 	"pos-restaurant/services"
 	"strconv"
 
@@ -28,18 +31,22 @@ type NewOrderRequest struct {
 	OutletID   int                     `json:"outlet_id"`
 	Status     string                  `json:"status"`
 	OrderType  string                  `json:"order_type"`
-	Items      []models.OrderItemInput `json:"items"`
+	Items      []models.OrderItemInput `json:"items" binding:"dive"`
 }
 
 // Create godoc
 // @Summary Buat order baru
+// @Description Harga item diambil dari menu, override harga wajib disetujui manager/supervisor (approved_by + approver_pin)
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Param request body NewOrderRequest true "Data order baru"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders [post]
 func (h *OrderHandler) Create(c *gin.Context) {
 	var req NewOrderRequest
@@ -60,8 +67,12 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		Items:      req.Items,
 	}
 
-	id, err := h.service.Create(c.Request.Context(), order)
+	id, err := h.service.Create(c.Request.Context(), order, middleware.StaffID(c))
 	if err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Create Order error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat order"})
 		return
@@ -76,6 +87,7 @@ func (h *OrderHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Order
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders [get]
 func (h *OrderHandler) List(c *gin.Context) {
 	orders, err := h.service.List(c.Request.Context())
@@ -95,6 +107,7 @@ func (h *OrderHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Order
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders/{id} [get]
 func (h *OrderHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -116,6 +129,7 @@ func (h *OrderHandler) GetByID(c *gin.Context) {
 
 // Update godoc
 // @Summary Perbarui data order
+// @Description Hanya order open/transferred. Perubahan status (kecuali void) disimpan bersama data order dalam satu transaksi.
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -123,7 +137,10 @@ func (h *OrderHandler) GetByID(c *gin.Context) {
 // @Param request body NewOrderRequest true "Data order yang diperbarui"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders/{id} [put]
 func (h *OrderHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -140,6 +157,11 @@ func (h *OrderHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if req.Status == models.OrderVoid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gunakan DELETE /orders/{id} untuk void order"})
+		return
+	}
+
 	req.ID = id
 	order := &models.Order{
 		ID:         req.ID,
@@ -152,7 +174,11 @@ func (h *OrderHandler) Update(c *gin.Context) {
 		OrderType:  req.OrderType,
 	}
 
-	if err := h.service.Update(c.Request.Context(), order); err != nil {
+	if err := h.service.Update(c.Request.Context(), order, middleware.StaffID(c)); err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Update Order error (ID %d): %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
 		return
@@ -163,6 +189,7 @@ func (h *OrderHandler) Update(c *gin.Context) {
 
 // AddItem godoc
 // @Summary Tambahkan item ke order
+// @Description Order harus open/transferred dan belum punya bill aktif (void bill lebih dulu)
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -170,7 +197,12 @@ func (h *OrderHandler) Update(c *gin.Context) {
 // @Param request body models.AddOrderItemRequest true "Data item baru"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders/{id}/add [post]
 func (h *OrderHandler) AddItem(c *gin.Context) {
 	orderID, err := strconv.Atoi(c.Param("id"))
@@ -187,7 +219,11 @@ func (h *OrderHandler) AddItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.AddItem(c.Request.Context(), orderID, &req); err != nil {
+	if err := h.service.AddItem(c.Request.Context(), orderID, &req, middleware.StaffID(c)); err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Add Item to Order error (Order ID %d): %v", orderID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menambahkan item"})
 		return
@@ -197,13 +233,19 @@ func (h *OrderHandler) AddItem(c *gin.Context) {
 }
 
 // Delete godoc
-// @Summary Soft delete order (status menjadi void)
+// @Summary Void order (status menjadi void) dan kembalikan stok bahan
+// @Description Bill order yang belum dibayar ikut di-void. Ditolak (409) jika masih ada bill yang memegang pembayaran, refund lebih dulu.
 // @Tags Orders
 // @Produce json
 // @Param id path int true "ID order"
+// @Param prepared query bool false "Makanan sudah dibuat, stok dicatat sebagai waste"
+// @Param reason query string false "Alasan void"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /orders/{id} [delete]
 func (h *OrderHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -213,7 +255,12 @@ func (h *OrderHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.SoftDelete(c.Request.Context(), id); err != nil {
+	prepared := c.Query("prepared") == "true"
+	if err := h.service.Void(c.Request.Context(), id, middleware.StaffID(c), prepared, c.Query("reason")); err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Delete Order error (ID %d): %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order"})
 		return
@@ -221,3 +268,303 @@ func (h *OrderHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Order deleted"})
 }
+
+type VoidItemRequest struct {
+	Prepared bool   `json:"prepared"`
+	Reason   string `json:"reason"`
+}
+
+// VoidItem godoc
+// @Summary Void satu item order dan kembalikan stok bahan
+// @Description Order harus open/transferred dan belum punya bill aktif (void bill lebih dulu)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "ID order"
+// @Param item_id path int true "ID order item"
+// @Param request body VoidItemRequest false "Opsi void"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/items/{item_id}/void [post]
+func (h *OrderHandler) VoidItem(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req VoidItemRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err = h.service.VoidItem(c.Request.Context(), orderID, itemID, middleware.StaffID(c), req.Prepared, req.Reason)
+	if err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Void Item error (Order ID %d, Item ID %d): %v", orderID, itemID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal void item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item berhasil di-void"})
+}
+
+type ChangeOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=settled"` // void lewat DELETE /orders/{id}, transferred lewat transfer meja
+	Reason string `json:"reason"`
+}
+
+// ChangeStatus godoc
+// @Summary Ubah status order sesuai state machine
+// @Description open/transferred -> settled, hanya jika semua bill lunas. Void lewat DELETE /orders/{id}, transferred lewat transfer meja.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "ID order"
+// @Param request body ChangeOrderStatusRequest true "Status tujuan"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/status [post]
+func (h *OrderHandler) ChangeStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ChangeOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ChangeStatus(c.Request.Context(), id, req.Status, middleware.StaffID(c), req.Reason); err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Change Order Status error (ID %d): %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Status order berhasil diubah"})
+}
+
+type MergeOrdersRequest struct {
+	TargetOrderID  int    `json:"target_order_id" binding:"required"`
+	SourceOrderIDs []int  `json:"source_order_ids" binding:"required,min=1"`
+	Reason         string `json:"reason"`
+}
+
+// Merge godoc
+// @Summary Gabungkan beberapa order open ke satu order (rombongan tamu bergabung)
+// @Description Item, paket dan tiket dapur order sumber dipindah ke order tujuan, bill sumber maupun tujuan yang belum dibayar di-void (buat bill baru setelah merge), order sumber menjadi merged dan meja sumber yang kosong kembali available.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param request body MergeOrdersRequest true "Order tujuan dan order sumber"
+// @Success 200 {object} models.MergeResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/merge [post]
+func (h *OrderHandler) Merge(c *gin.Context) {
+	var req MergeOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Merge(c.Request.Context(), req.TargetOrderID, req.SourceOrderIDs, middleware.StaffID(c), req.Reason)
+	if err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Merge Order error (target %d): %v", req.TargetOrderID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menggabungkan order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+type FireRequest struct {
+	Course    string `json:"course" binding:"omitempty,oneof=appetizer main dessert"` // fire satu course
+	TicketIDs []int  `json:"ticket_ids"`                                              // kosong = semua tiket yang ditahan
+}
+
+// Fire godoc
+// @Summary Kirim item yang ditahan (hold) ke dapur
+// @Description Tiket dapur yang ditahan masuk antrian KDS dan dicetak di printer station-nya.
+// @Description Isi course untuk melepas satu course (appetizer/main/dessert) order dine-in.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "ID order"
+// @Param request body FireRequest false "Tiket yang di-fire"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/fire [post]
+func (h *OrderHandler) Fire(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req FireRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	fired, err := h.service.Fire(c.Request.Context(), id, req.Course, req.TicketIDs, middleware.StaffID(c))
+	if err != nil {
+		if status, ok := orderErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Fire Order error (ID %d): %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengirim item ke dapur"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item dikirim ke dapur", "ticket_ids": fired})
+}
+
+// Merges godoc
+// @Summary Jejak merge order (sebagai tujuan maupun sumber)
+// @Tags Orders
+// @Produce json
+// @Param id path int true "ID order"
+// @Success 200 {array} models.OrderMerge
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/merges [get]
+func (h *OrderHandler) Merges(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	merges, err := h.service.Merges(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Order Merges error (ID %d): %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil jejak merge order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, merges)
+}
+
+// StatusHistory godoc
+// @Summary Riwayat perubahan status order
+// @Tags Orders
+// @Produce json
+// @Param id path int true "ID order"
+// @Success 200 {array} models.OrderStatusChange
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/history [get]
+func (h *OrderHandler) StatusHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	history, err := h.service.StatusHistory(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Order Status History error (ID %d): %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat status order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// Courses godoc
+// @Summary Status course order (held, fired, served)
+// @Tags Orders
+// @Produce json
+// @Param id path int true "ID order"
+// @Success 200 {array} models.OrderCourse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/{id}/courses [get]
+func (h *OrderHandler) Courses(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	courses, err := h.service.Courses(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Order Courses error (ID %d): %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil course order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// orderErrorStatus memetakan error domain order ke HTTP status
+func orderErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, repositories.ErrNothingToVoid):
+		return http.StatusNotFound, true
+	case errors.Is(err, services.ErrInvalidPriceOverride), errors.Is(err, services.ErrInvalidQty),
+		errors.Is(err, repositories.ErrMenuUnavailable),
+		errors.Is(err, repositories.ErrInvalidModifier), errors.Is(err, repositories.ErrComboUnavailable),
+		errors.Is(err, repositories.ErrInvalidComboChoice), errors.Is(err, repositories.ErrInvalidMerge),
+		errors.Is(err, repositories.ErrInvalidCourse):
+		return http.StatusBadRequest, true
+	case errors.Is(err, services.ErrApprovalDenied):
+		return http.StatusForbidden, true
+	case errors.Is(err, services.ErrTooManyAttempts):
+		return http.StatusTooManyRequests, true
+	case errors.Is(err, services.ErrInvalidOrderTransition),
+		errors.Is(err, repositories.ErrOrderNotEditable),
+		errors.Is(err, repositories.ErrOrderBilled),
+		errors.Is(err, repositories.ErrOrderHasPayments),
+		errors.Is(err, repositories.ErrStatusConflict),
+		errors.Is(err, repositories.ErrOrderUnpaid),
+		errors.Is(err, repositories.ErrNothingToFire):
+		return http.StatusConflict, true
+	}
+	return 0, false
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/pricing"
 	"pos-restaurant/services"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,7 +28,49 @@ type CreateOutletRequest struct {
 	Location             string  `json:"location"`
 	ServiceChargePercent float64 `json:"service_charge_percentage"`
 	TaxPercentage        float64 `json:"tax_percentage"`
-	IsActive             bool    `json:"is_active"`
+	PricesIncludeTax     bool    `json:"prices_include_tax"`
+	TaxOnServiceCharge   *bool   `json:"tax_on_service_charge"` // default true
+	// default takeaway & delivery, kirim [] agar semua order_type kena service charge
+	ServiceExemptTypes []string     `json:"service_charge_exempt_types" binding:"omitempty,dive,oneof=dine_in takeaway delivery room_service"`
+	RoundingUnit       money.Amount `json:"rounding_unit" binding:"gte=0"`
+	RoundingMode       string       `json:"rounding_mode" binding:"omitempty,oneof=nearest up down"` // default nearest
+	Currency           string       `json:"currency" binding:"omitempty,len=3"`                      // default IDR
+	IsActive           bool         `json:"is_active"`
+}
+
+func (req *CreateOutletRequest) toModel() *models.Outlet {
+	outlet := &models.Outlet{
+		Name:                 req.Name,
+		Location:             sql.NullString{String: req.Location, Valid: req.Location != ""},
+		ServiceChargePercent: req.ServiceChargePercent,
+		TaxPercentage:        req.TaxPercentage,
+		PricesIncludeTax:     req.PricesIncludeTax,
+		TaxOnServiceCharge:   req.TaxOnServiceCharge == nil || *req.TaxOnServiceCharge,
+		ServiceExemptTypes:   req.ServiceExemptTypes,
+		RoundingUnit:         req.RoundingUnit,
+		RoundingMode:         req.RoundingMode,
+		Currency:             strings.ToUpper(req.Currency),
+		IsActive:             req.IsActive,
+	}
+	if outlet.ServiceExemptTypes == nil {
+		outlet.ServiceExemptTypes = []string{"takeaway", "delivery"}
+	}
+	if outlet.RoundingMode == "" {
+		outlet.RoundingMode = pricing.RoundNearest
+	}
+	if outlet.Currency == "" {
+		outlet.Currency = money.DefaultCurrency.Code
+	}
+	return outlet
+}
+
+type CategoryTaxRateRequest struct {
+	Rates []CategoryTaxRateInput `json:"rates" binding:"dive"`
+}
+
+type CategoryTaxRateInput struct {
+	CategoryID    int     `json:"category_id" binding:"required"`
+	TaxPercentage float64 `json:"tax_percentage" binding:"gte=0,lte=100"`
 }
 
 // Create godoc
@@ -36,6 +82,7 @@ type CreateOutletRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /outlets [post]
 func (h *OutletHandler) Create(c *gin.Context) {
 	var req CreateOutletRequest
@@ -45,16 +92,14 @@ func (h *OutletHandler) Create(c *gin.Context) {
 		return
 	}
 
-	outlet := &models.Outlet{
-		Name:                 req.Name,
-		Location:             sql.NullString{String: req.Location, Valid: req.Location != ""},
-		ServiceChargePercent: req.ServiceChargePercent,
-		TaxPercentage:        req.TaxPercentage,
-		IsActive:             req.IsActive,
-	}
+	outlet := req.toModel()
 
 	id, err := h.service.CreateOutlet(c.Request.Context(), outlet)
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("DB error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat outlet"})
 		return
@@ -68,6 +113,7 @@ func (h *OutletHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Outlet
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /outlets [get]
 func (h *OutletHandler) List(c *gin.Context) {
 	outlets, err := h.service.ListOutlets(c.Request.Context())
@@ -88,6 +134,7 @@ func (h *OutletHandler) List(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /outlets/{id} [get]
 func (h *OutletHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -120,6 +167,7 @@ func (h *OutletHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /outlets/{id} [put]
 func (h *OutletHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -135,16 +183,14 @@ func (h *OutletHandler) Update(c *gin.Context) {
 		return
 	}
 
-	outlet := &models.Outlet{
-		ID:                   id,
-		Name:                 req.Name,
-		Location:             sql.NullString{String: req.Location, Valid: req.Location != ""},
-		ServiceChargePercent: req.ServiceChargePercent,
-		TaxPercentage:        req.TaxPercentage,
-		IsActive:             req.IsActive,
-	}
+	outlet := req.toModel()
+	outlet.ID = id
 
 	if err := h.service.UpdateOutlet(c.Request.Context(), outlet); err != nil {
+		if errors.Is(err, services.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("DB error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui outlet"})
 		return
@@ -161,6 +207,7 @@ func (h *OutletHandler) Update(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /outlets/{id} [delete]
 func (h *OutletHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -178,3 +225,148 @@ func (h *OutletHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Outlet berhasil dihapus (soft delete)"})
 }
+
+// ListCategoryTaxes godoc
+// @Summary Tampilkan tarif pajak khusus per kategori di outlet
+// @Description Kategori yang tidak tercantum memakai tax_percentage outlet
+// @Tags Outlet
+// @Produce json
+// @Param id path int true "ID outlet"
+// @Success 200 {array} models.CategoryTaxRate
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /outlets/{id}/category-taxes [get]
+func (h *OutletHandler) ListCategoryTaxes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	rates, err := h.service.ListCategoryTaxRates(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Gagal mengambil pajak kategori outlet %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil pajak kategori"})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// ReplaceCategoryTaxes godoc
+// @Summary Atur tarif pajak khusus per kategori di outlet (mengganti seluruh daftar)
+// @Tags Outlet
+// @Accept json
+// @Produce json
+// @Param id path int true "ID outlet"
+// @Param request body CategoryTaxRateRequest true "Daftar tarif pajak kategori"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /outlets/{id}/category-taxes [put]
+func (h *OutletHandler) ReplaceCategoryTaxes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req CategoryTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rates := make([]models.CategoryTaxRate, 0, len(req.Rates))
+	for _, r := range req.Rates {
+		rates = append(rates, models.CategoryTaxRate{CategoryID: r.CategoryID, TaxPercentage: r.TaxPercentage})
+	}
+
+	if err := h.service.ReplaceCategoryTaxRates(c.Request.Context(), id, rates); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTaxRule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Outlet tidak ditemukan"})
+		default:
+			log.Printf("Gagal mengatur pajak kategori outlet %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengatur pajak kategori"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pajak kategori berhasil diperbarui"})
+}
+
+type DiningPeriodRequest struct {
+	Periods []models.DiningPeriod `json:"periods" binding:"dive"`
+}
+
+// ListDiningPeriods godoc
+// @Summary Tampilkan periode makan outlet (jam reservasi dan lama makan)
+// @Tags Outlet
+// @Produce json
+// @Param id path int true "ID outlet"
+// @Success 200 {array} models.DiningPeriod
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /outlets/{id}/dining-periods [get]
+func (h *OutletHandler) ListDiningPeriods(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	periods, err := h.service.ListDiningPeriods(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Gagal mengambil periode makan outlet %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil periode makan"})
+		return
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+// ReplaceDiningPeriods godoc
+// @Summary Atur periode makan outlet (mengganti seluruh daftar)
+// @Description Setiap periode (breakfast, lunch, dinner, event) punya slot reservasi pertama & terakhir (HH:MM) dan lama makan dalam menit yang dipakai untuk cek bentrok reservasi
+// @Tags Outlet
+// @Accept json
+// @Produce json
+// @Param id path int true "ID outlet"
+// @Param request body DiningPeriodRequest true "Daftar periode makan"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /outlets/{id}/dining-periods [put]
+func (h *OutletHandler) ReplaceDiningPeriods(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req DiningPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ReplaceDiningPeriods(c.Request.Context(), id, req.Periods); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidDiningPeriod):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Outlet tidak ditemukan"})
+		default:
+			log.Printf("Gagal mengatur periode makan outlet %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengatur periode makan"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Periode makan berhasil diperbarui"})
+}
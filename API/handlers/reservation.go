@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"pos-restaurant/models"
+	"pos-restaurant/repositories"
 	"pos-restaurant/services"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -24,22 +25,42 @@ func NewReservationHandler(service *services.ReservationService) *ReservationHan
 type CreateReservationRequest struct {
 	CustomerID      int    `json:"customer_id" binding:"required"`
 	ReservationTime string `json:"reservation_time" binding:"required"`
-	Pax             int    `json:"pax" binding:"required"`
-	TableID         int    `json:"table_id" binding:"required"`
-	Status          string `json:"status" binding:"required"`
+	Pax             int    `json:"pax" binding:"required,gt=0"`
+	OutletID        int    `json:"outlet_id"`      // wajib jika table_id kosong
+	TableID         int    `json:"table_id"`       // kosong = meja dipilih otomatis
+	JoinTableIDs    []int  `json:"join_table_ids"` // meja tambahan yang digabung dengan table_id
+	VisitType       string `json:"visit_type" binding:"omitempty,oneof=breakfast lunch dinner event"`
+	Status          string `json:"status" binding:"required,oneof=confirmed waiting canceled no_show seated"`
 	SpecialRequest  string `json:"special_request"`
 }
 
+func (req *CreateReservationRequest) reservation(id int, reservationTime time.Time) *models.Reservation {
+	return &models.Reservation{
+		ID:              id,
+		CustomerID:      req.CustomerID,
+		OutletID:        req.OutletID,
+		ReservationTime: reservationTime,
+		Pax:             req.Pax,
+		TableID:         req.TableID,
+		TableIDs:        req.JoinTableIDs,
+		VisitType:       sql.NullString{String: req.VisitType, Valid: req.VisitType != ""},
+		Status:          req.Status,
+		SpecialRequest:  sql.NullString{String: req.SpecialRequest, Valid: req.SpecialRequest != ""},
+	}
+}
+
 // Create godoc
 // @Summary Tambah reservasi baru
+// @Description Lama makan diambil dari periode makan outlet (visit_type atau jam reservasi). Tanpa table_id, meja terkecil yang cukup dipilih otomatis, atau gabungan meja bersebelahan jika tidak ada. Reservasi confirmed/seated ditolak jika bentrok dengan reservasi lain di meja yang sama.
 // @Tags Reservations
 // @Accept json
 // @Produce json
 // @Param request body CreateReservationRequest true "Data reservasi"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
-// @Failure 409 {object} map[string]string "Meja sudah dipesan"
+// @Failure 409 {object} map[string]string "Meja sudah dipesan / tidak ada meja kosong"
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /reservations [post]
 func (h *ReservationHandler) Create(c *gin.Context) {
 	var req CreateReservationRequest
@@ -56,29 +77,20 @@ func (h *ReservationHandler) Create(c *gin.Context) {
 		return
 	}
 
-	res := &models.Reservation{
-		CustomerID:      req.CustomerID,
-		ReservationTime: timeParsed,
-		Pax:             req.Pax,
-		TableID:         req.TableID,
-		Status:          req.Status,
-		SpecialRequest:  sql.NullString{String: req.SpecialRequest, Valid: req.SpecialRequest != ""},
-	}
+	res := req.reservation(0, timeParsed)
 
 	id, err := h.service.Create(c.Request.Context(), res)
 	if err != nil {
-
-		if strings.Contains(err.Error(), "meja sudah dipesan") {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		if status, ok := reservationErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
-
 		log.Println("Gagal create reservation:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat reservasi"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"id": id})
+	c.JSON(http.StatusCreated, gin.H{"id": id, "table_ids": res.TableIDs, "duration_minutes": res.DurationMinutes})
 }
 
 // List godoc
@@ -88,6 +100,7 @@ func (h *ReservationHandler) Create(c *gin.Context) {
 // @Param sort query string false "Kolom untuk sorting (default: reservation_time)"
 // @Success 200 {array} models.Reservation
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /reservations [get]
 func (h *ReservationHandler) List(c *gin.Context) {
 	sortBy := c.DefaultQuery("sort", "reservation_time")
@@ -110,6 +123,7 @@ func (h *ReservationHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Reservation
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /reservations/{id} [get]
 func (h *ReservationHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -139,7 +153,10 @@ func (h *ReservationHandler) GetByID(c *gin.Context) {
 // @Param request body CreateReservationRequest true "Data reservasi baru"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /reservations/{id} [put]
 func (h *ReservationHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -164,17 +181,17 @@ func (h *ReservationHandler) Update(c *gin.Context) {
 		return
 	}
 
-	res := &models.Reservation{
-		ID:              id,
-		CustomerID:      req.CustomerID,
-		ReservationTime: timeParsed,
-		Pax:             req.Pax,
-		TableID:         req.TableID,
-		Status:          req.Status,
-		SpecialRequest:  sql.NullString{String: req.SpecialRequest, Valid: req.SpecialRequest != ""},
-	}
+	res := req.reservation(id, timeParsed)
 
 	if err := h.service.Update(c.Request.Context(), res); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservasi tidak ditemukan"})
+			return
+		}
+		if status, ok := reservationErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		log.Println("Gagal update reservation:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update reservasi"})
 		return
@@ -190,7 +207,9 @@ func (h *ReservationHandler) Update(c *gin.Context) {
 // @Param id path int true "ID reservasi"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /reservations/{id} [delete]
 func (h *ReservationHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -202,6 +221,10 @@ func (h *ReservationHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservasi tidak ditemukan"})
+			return
+		}
 		log.Println("Gagal delete reservation:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus reservasi"})
 		return
@@ -209,3 +232,111 @@ func (h *ReservationHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Reservasi berhasil dihapus"})
 }
+
+// Availability godoc
+// @Summary Cari slot reservasi yang masih tersedia
+// @Description Slot per 15 menit dari first_seating sampai last_seating setiap periode makan outlet yang masih punya meja (atau gabungan meja bersebelahan) untuk jumlah tamu, beserta meja yang disarankan
+// @Tags Reservations
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Param date query string true "Tanggal (YYYY-MM-DD)"
+// @Param pax query int true "Jumlah tamu"
+// @Param visit_type query string false "breakfast, lunch, dinner atau event"
+// @Success 200 {array} models.AvailabilitySlot
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Periode makan outlet belum diatur"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reservations/availability [get]
+func (h *ReservationHandler) Availability(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+	pax, err := strconv.Atoi(c.Query("pax"))
+	if err != nil || pax <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter pax wajib diisi"})
+		return
+	}
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format 'date' tidak valid (YYYY-MM-DD)"})
+		return
+	}
+	visitType := c.Query("visit_type")
+	switch visitType {
+	case "", "breakfast", "lunch", "dinner", "event":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter visit_type tidak valid"})
+		return
+	}
+
+	slots, err := h.service.Availability(c.Request.Context(), outletID, date, pax, visitType)
+	if err != nil {
+		if errors.Is(err, services.ErrNoDiningPeriods) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal mencari slot reservasi outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencari slot reservasi"})
+		return
+	}
+	c.JSON(http.StatusOK, slots)
+}
+
+// reservationErrorStatus memetakan error reservasi ke HTTP status
+// Seat godoc
+// @Summary Dudukkan tamu reservasi
+// @Description Reservasi confirmed, waiting atau no_show (tamu terlambat) menjadi seated. Tanpa table_id dipakai meja reservasi confirmed, atau meja kosong yang dipilih otomatis. Kunjungan pelanggan (terhubung ke reservasi) dan order dine-in langsung dibuka.
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "ID reservasi"
+// @Param request body SeatRequest false "Meja dan waiter"
+// @Success 200 {object} models.SeatResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reservations/{id}/seat [post]
+func (h *ReservationHandler) Seat(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+	var req SeatRequest
+	tableIDs, waiterID, err := req.bind(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Seat(c.Request.Context(), id, tableIDs, waiterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservasi tidak ditemukan"})
+			return
+		}
+		if status, ok := reservationErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal mendudukkan reservasi %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mendudukkan tamu reservasi"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func reservationErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, repositories.ErrReservationOverlap), errors.Is(err, repositories.ErrReservationNotSeatable):
+		return http.StatusConflict, true
+	case errors.Is(err, repositories.ErrTableCapacity), errors.Is(err, repositories.ErrInvalidReservationTable):
+		return http.StatusBadRequest, true
+	}
+	return seatErrorStatus(err)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SalesAnalysisHandler struct {
+	service *services.SalesAnalysisService
+}
+
+func NewSalesAnalysisHandler(service *services.SalesAnalysisService) *SalesAnalysisHandler {
+	return &SalesAnalysisHandler{service: service}
+}
+
+// Run godoc
+// @Summary Hitung ulang ringkasan penjualan harian (manual)
+// @Description Default hanya hari ini. Aman dijalankan berulang (upsert per outlet & tanggal)
+// @Tags Reports
+// @Produce json
+// @Param from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/sales-daily/run [post]
+func (h *SalesAnalysisHandler) Run(c *gin.Context) {
+	from, to, err := parseDateRange(c, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := h.service.Run(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrAnalysisRangeTooLong) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal menghitung penjualan harian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung penjualan harian"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ringkasan penjualan berhasil dihitung", "days": days})
+}
+
+// List godoc
+// @Summary Ringkasan penjualan harian per outlet
+// @Tags Reports
+// @Produce json
+// @Param outlet_id query int false "Filter outlet"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD), default 30 hari lalu"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD), default hari ini"
+// @Success 200 {array} models.SalesAnalysisDaily
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/sales-daily [get]
+func (h *SalesAnalysisHandler) List(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.ListRange(c.Request.Context(), outletID, from, to)
+	if err != nil {
+		log.Printf("Gagal mengambil penjualan harian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil penjualan harian"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CourseTimes godoc
+// @Summary Waktu layanan per course (fire sampai served)
+// @Description Rata-rata dan maksimum menit dari course di-fire sampai semua itemnya served, serta rata-rata menit dari order dibuka sampai course di-fire
+// @Tags Reports
+// @Produce json
+// @Param outlet_id query int false "Filter outlet"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD), default 30 hari lalu"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD), default hari ini"
+// @Success 200 {array} models.CourseServiceTime
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /reports/course-times [get]
+func (h *SalesAnalysisHandler) CourseTimes(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.DefaultQuery("outlet_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id tidak valid"})
+		return
+	}
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.CourseTimes(c.Request.Context(), outletID, from, to)
+	if err != nil {
+		log.Printf("Gagal mengambil waktu layanan course: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil waktu layanan course"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -21,7 +21,15 @@ func NewStaffHandler(service *services.StaffService) *StaffHandler {
 type StaffRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Role     string `json:"role" binding:"required"`
-	PinCode  string `json:"pin_code" binding:"required"`
+	PinCode  string `json:"pin_code" binding:"required,min=4,max=10,numeric"`
+	IsActive bool   `json:"is_active"`
+}
+
+// UpdateStaffRequest sama dengan StaffRequest, tetapi pin_code kosong berarti PIN lama dipertahankan
+type UpdateStaffRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+	PinCode  string `json:"pin_code" binding:"omitempty,min=4,max=10,numeric"`
 	IsActive bool   `json:"is_active"`
 }
 
@@ -34,6 +42,7 @@ type StaffRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /staff [post]
 func (h *StaffHandler) Create(c *gin.Context) {
 	var req StaffRequest
@@ -62,6 +71,7 @@ func (h *StaffHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Staff
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /staff [get]
 func (h *StaffHandler) List(c *gin.Context) {
 	staff, err := h.service.ListStaff(c.Request.Context())
@@ -81,6 +91,7 @@ func (h *StaffHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Staff
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Security BearerAuth
 // @Router /staff/{id} [get]
 func (h *StaffHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -103,10 +114,11 @@ func (h *StaffHandler) GetByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID staff"
-// @Param request body StaffRequest true "Data staff yang diperbarui"
+// @Param request body UpdateStaffRequest true "Data staff yang diperbarui, pin_code kosong = PIN tidak diubah"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /staff/{id} [put]
 func (h *StaffHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -114,7 +126,7 @@ func (h *StaffHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
 		return
 	}
-	var req StaffRequest
+	var req UpdateStaffRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -142,6 +154,7 @@ func (h *StaffHandler) Update(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /staff/{id} [delete]
 func (h *StaffHandler) SoftDelete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
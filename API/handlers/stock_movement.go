@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/middleware"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"pos-restaurant/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockMovementHandler struct {
+	service *services.StockMovementService
+}
+
+func NewStockMovementHandler(service *services.StockMovementService) *StockMovementHandler {
+	return &StockMovementHandler{service: service}
+}
+
+type CreateStockMovementRequest struct {
+	MovementType string  `json:"movement_type" binding:"required,oneof=receiving waste adjustment"`
+	Qty          float64 `json:"qty" binding:"required"`
+	Notes        string  `json:"notes"`
+}
+
+// Create godoc
+// @Summary Catat pergerakan stok manual (receiving, waste, adjustment)
+// @Tags Stock Movements
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Ingredient"
+// @Param request body CreateStockMovementRequest true "Data pergerakan stok"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /ingredients/{id}/movements [post]
+func (h *StockMovementHandler) Create(c *gin.Context) {
+	ingredientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	var req CreateStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	staffID := middleware.StaffID(c)
+	movement := &models.StockMovement{
+		IngredientID: ingredientID,
+		MovementType: req.MovementType,
+		QtyChange:    req.Qty,
+		StaffID:      sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+		Notes:        sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	}
+
+	id, err := h.service.Record(c.Request.Context(), movement)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStockMovement) || errors.Is(err, repositories.ErrInsufficientStock) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal mencatat movement stok ingredient %d: %v", ingredientID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencatat pergerakan stok"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Pergerakan stok berhasil dicatat"})
+}
+
+// ListByIngredient godoc
+// @Summary Riwayat pergerakan stok sebuah ingredient
+// @Tags Stock Movements
+// @Produce json
+// @Param id path int true "ID Ingredient"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD), default 30 hari terakhir"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD), inklusif, default hari ini"
+// @Success 200 {array} models.StockMovement
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /ingredients/{id}/movements [get]
+func (h *StockMovementHandler) ListByIngredient(c *gin.Context) {
+	ingredientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	from, to, err := parseDateRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movements, err := h.service.ListByIngredient(c.Request.Context(), ingredientID, from, to)
+	if err != nil {
+		log.Printf("Gagal mengambil movement stok ingredient %d: %v", ingredientID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat stok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+// Reconcile godoc
+// @Summary Bandingkan qty ingredient saat ini dengan total ledger
+// @Tags Stock Movements
+// @Produce json
+// @Param id path int true "ID Ingredient"
+// @Success 200 {object} models.StockReconciliation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /ingredients/{id}/reconcile [get]
+func (h *StockMovementHandler) Reconcile(c *gin.Context) {
+	ingredientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	rec, err := h.service.Reconcile(c.Request.Context(), ingredientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal rekonsiliasi stok ingredient %d: %v", ingredientID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal rekonsiliasi stok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rec)
+}
+
+// parseDateRange membaca query ?from=YYYY-MM-DD&to=YYYY-MM-DD menjadi rentang [from, to+1 hari)
+func parseDateRange(c *gin.Context, defaultDays int) (time.Time, time.Time, error) {
+	const layout = "2006-01-02"
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(layout, v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("format 'to' tidak valid (YYYY-MM-DD)")
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, -defaultDays)
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(layout, v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("format 'from' tidak valid (YYYY-MM-DD)")
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("'from' tidak boleh setelah 'to'")
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"pos-restaurant/models"
+	"pos-restaurant/repositories"
 	"pos-restaurant/services"
 	"strconv"
 
@@ -24,12 +26,16 @@ type newTableRequest struct {
 	TableNumber  string `json:"table_number"`
 	Capacity     int    `json:"capacity"`
 	LocationType string `json:"location_type"`
-	Status       string `json:"status"`
+	Status       string `json:"status" binding:"omitempty,oneof=available occupied reserved out_of_order"`
+	Section      string `json:"section"`
+	Shape        string `json:"shape" binding:"omitempty,oneof=square round rectangle"`
+	PosX         int    `json:"pos_x"`
+	PosY         int    `json:"pos_y"`
 }
 
 // Create godoc
 // @Summary Tambah meja baru
-// @Description Menambahkan data meja baru ke dalam sistem
+// @Description Menambahkan data meja baru ke dalam sistem. Status hanya bisa diisi out_of_order, selain itu dihitung otomatis dari order dan reservasi.
 // @Tags Table
 // @Accept json
 // @Produce json
@@ -37,6 +43,7 @@ type newTableRequest struct {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /tables [post]
 func (h *TableHandler) Create(c *gin.Context) {
 	var req newTableRequest
@@ -52,6 +59,10 @@ func (h *TableHandler) Create(c *gin.Context) {
 		Capacity:     req.Capacity,
 		LocationType: sql.NullString{String: req.LocationType, Valid: req.LocationType != ""},
 		Status:       req.Status,
+		Section:      sql.NullString{String: req.Section, Valid: req.Section != ""},
+		Shape:        req.Shape,
+		PosX:         req.PosX,
+		PosY:         req.PosY,
 	}
 
 	id, err := h.service.CreateTable(c.Request.Context(), table)
@@ -69,6 +80,7 @@ func (h *TableHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.Table
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /tables [get]
 func (h *TableHandler) List(c *gin.Context) {
 	tables, err := h.service.ListTables(c.Request.Context())
@@ -88,6 +100,7 @@ func (h *TableHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Table
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /tables/{id} [get]
 func (h *TableHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -107,6 +120,7 @@ func (h *TableHandler) GetByID(c *gin.Context) {
 
 // Update godoc
 // @Summary Update data meja
+// @Description Isi status out_of_order untuk menandai meja tidak bisa dipakai, status lain melepas out_of_order, kosong = tidak berubah. Selain out_of_order, status selalu dihitung ulang dari order dan reservasi. Posisi di denah diubah lewat PUT /tables/layout.
 // @Tags Table
 // @Accept json
 // @Produce json
@@ -115,6 +129,7 @@ func (h *TableHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /tables/{id} [put]
 func (h *TableHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -155,6 +170,7 @@ func (h *TableHandler) Update(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /tables/{id} [delete]
 func (h *TableHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -170,3 +186,67 @@ func (h *TableHandler) Delete(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Meja berhasil dihapus"})
 }
+
+type saveLayoutRequest struct {
+	OutletID int                  `json:"outlet_id" binding:"required"`
+	Tables   []models.TableLayout `json:"tables" binding:"required,min=1,dive"`
+}
+
+// SaveLayout godoc
+// @Summary Simpan denah meja outlet
+// @Description Menyimpan area, bentuk, posisi dan meja sebelah (yang bisa digabung untuk reservasi rombongan besar) beberapa meja sekaligus. Semua meja harus milik outlet tersebut.
+// @Tags Table
+// @Accept json
+// @Produce json
+// @Param request body saveLayoutRequest true "Posisi meja"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tables/layout [put]
+func (h *TableHandler) SaveLayout(c *gin.Context) {
+	var req saveLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SaveLayout(c.Request.Context(), req.OutletID, req.Tables); err != nil {
+		if errors.Is(err, repositories.ErrInvalidLayout) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println("Failed to save table layout:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan denah meja"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Denah meja berhasil disimpan"})
+}
+
+// Floor godoc
+// @Summary Tampilan denah meja outlet
+// @Description Setiap meja dengan status terkini, order yang sedang berjalan (waiter, jumlah item, total, sudah dibayar), lama duduk sejak order pertama, total berjalan dan reservasi berikutnya hari ini
+// @Tags Table
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Param section query string false "Filter area"
+// @Success 200 {array} models.FloorTable
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /tables/floor [get]
+func (h *TableHandler) Floor(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+
+	floor, err := h.service.Floor(c.Request.Context(), outletID, c.Query("section"))
+	if err != nil {
+		log.Printf("Gagal menyusun denah outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil denah meja"})
+		return
+	}
+	c.JSON(http.StatusOK, floor)
+}
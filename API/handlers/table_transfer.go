@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"pos-restaurant/models"
+	"pos-restaurant/repositories"
 	"pos-restaurant/services"
 	"strconv"
 
@@ -29,13 +31,17 @@ type CreateTableTransferRequest struct {
 
 // Create godoc
 // @Summary Buat data pemindahan meja
+// @Description Hanya order open/transferred, order open berubah status menjadi transferred
 // @Tags Table Transfers
 // @Accept json
 // @Produce json
 // @Param request body CreateTableTransferRequest true "Data pemindahan meja"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /table-transfers [post]
 func (h *TableTransferHandler) Create(c *gin.Context) {
 	var req CreateTableTransferRequest
@@ -54,6 +60,14 @@ func (h *TableTransferHandler) Create(c *gin.Context) {
 
 	id, err := h.service.Create(c.Request.Context(), data)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, repositories.ErrOrderNotEditable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Gagal create table transfer: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menambahkan data"})
 		return
@@ -68,6 +82,7 @@ func (h *TableTransferHandler) Create(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} models.TableTransfer
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /table-transfers [get]
 func (h *TableTransferHandler) List(c *gin.Context) {
 	data, err := h.service.List(c.Request.Context())
@@ -85,6 +100,7 @@ func (h *TableTransferHandler) List(c *gin.Context) {
 // @Param id path int true "ID pemindahan meja"
 // @Success 200 {object} models.TableTransfer
 // @Failure 404 {object} map[string]string
+// @Security BearerAuth
 // @Router /table-transfers/{id} [get]
 func (h *TableTransferHandler) GetByID(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
@@ -106,6 +122,7 @@ func (h *TableTransferHandler) GetByID(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /table-transfers/{id} [put]
 func (h *TableTransferHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -146,6 +163,7 @@ func (h *TableTransferHandler) Update(c *gin.Context) {
 // @Param id path int true "ID pemindahan meja"
 // @Success 200 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
 // @Router /table-transfers/{id} [delete]
 func (h *TableTransferHandler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
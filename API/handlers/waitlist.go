@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"pos-restaurant/middleware"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"pos-restaurant/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	service *services.WaitlistService
+}
+
+func NewWaitlistHandler(service *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{service: service}
+}
+
+type CreateWaitlistRequest struct {
+	OutletID   int    `json:"outlet_id" binding:"required"`
+	CustomerID int    `json:"customer_id"` // kosong = tamu walk-in baru
+	GuestName  string `json:"guest_name" binding:"required"`
+	Phone      string `json:"phone"`
+	Pax        int    `json:"pax" binding:"required,gt=0"`
+	Notes      string `json:"notes"`
+}
+
+// SeatRequest dipakai saat antrian walk-in atau tamu reservasi didudukkan
+type SeatRequest struct {
+	TableID      int   `json:"table_id"`       // kosong = meja dipilih otomatis
+	JoinTableIDs []int `json:"join_table_ids"` // meja tambahan yang digabung dengan table_id
+	WaiterID     int   `json:"waiter_id"`      // kosong = staf yang memanggil
+}
+
+// bind membaca body yang boleh kosong lalu mengembalikan meja dan waiter (default staf yang login)
+func (req *SeatRequest) bind(c *gin.Context) ([]int, int, error) {
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			return nil, 0, err
+		}
+	}
+	var tableIDs []int
+	if req.TableID > 0 {
+		tableIDs = append([]int{req.TableID}, req.JoinTableIDs...)
+	}
+	waiterID := req.WaiterID
+	if waiterID <= 0 {
+		waiterID = middleware.StaffID(c)
+	}
+	return tableIDs, waiterID, nil
+}
+
+type RemoveWaitlistRequest struct {
+	Status string `json:"status" binding:"required,oneof=left canceled"`
+}
+
+// Create godoc
+// @Summary Daftarkan tamu walk-in ke antrian
+// @Description Perkiraan lama tunggu dihitung dari kondisi meja, rata-rata lama meja dipakai, dan antrian di depan.
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param request body CreateWaitlistRequest true "Data antrian"
+// @Success 201 {object} models.WaitlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist [post]
+func (h *WaitlistHandler) Create(c *gin.Context) {
+	var req CreateWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := &models.WaitlistEntry{
+		OutletID:   req.OutletID,
+		CustomerID: sql.NullInt64{Int64: int64(req.CustomerID), Valid: req.CustomerID > 0},
+		GuestName:  req.GuestName,
+		Phone:      sql.NullString{String: req.Phone, Valid: req.Phone != ""},
+		Pax:        req.Pax,
+		Notes:      sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		CreatedBy:  sql.NullInt64{Int64: int64(middleware.StaffID(c)), Valid: middleware.StaffID(c) > 0},
+	}
+
+	entry, err := h.service.Create(c.Request.Context(), entry)
+	if err != nil {
+		log.Printf("Gagal menambahkan antrian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menambahkan antrian"})
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// List godoc
+// @Summary Ambil antrian walk-in outlet
+// @Description Tanpa status, yang diambil adalah antrian hari ini. Antrian waiting dilengkapi posisi dan perkiraan sisa tunggu.
+// @Tags Waitlist
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Param status query string false "Status antrian" Enums(waiting, seated, left, canceled)
+// @Success 200 {array} models.WaitlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist [get]
+func (h *WaitlistHandler) List(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", models.WaitlistWaiting, models.WaitlistSeated, models.WaitlistLeft, models.WaitlistCanceled:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter status tidak valid"})
+		return
+	}
+
+	data, err := h.service.List(c.Request.Context(), outletID, status)
+	if err != nil {
+		log.Printf("Gagal mengambil antrian outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil antrian"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// Quote godoc
+// @Summary Perkiraan lama tunggu untuk rombongan baru
+// @Tags Waitlist
+// @Produce json
+// @Param outlet_id query int true "ID outlet"
+// @Param pax query int true "Jumlah tamu"
+// @Success 200 {object} models.WaitQuote
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist/quote [get]
+func (h *WaitlistHandler) Quote(c *gin.Context) {
+	outletID, err := strconv.Atoi(c.Query("outlet_id"))
+	if err != nil || outletID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter outlet_id wajib diisi"})
+		return
+	}
+	pax, err := strconv.Atoi(c.Query("pax"))
+	if err != nil || pax <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter pax wajib diisi"})
+		return
+	}
+
+	quote, err := h.service.Quote(c.Request.Context(), outletID, pax)
+	if err != nil {
+		log.Printf("Gagal menghitung perkiraan tunggu outlet %d: %v", outletID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung perkiraan tunggu"})
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// GetByID godoc
+// @Summary Ambil detail antrian
+// @Tags Waitlist
+// @Produce json
+// @Param id path int true "ID antrian"
+// @Success 200 {object} models.WaitlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist/{id} [get]
+func (h *WaitlistHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+
+	entry, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Antrian tidak ditemukan"})
+			return
+		}
+		log.Printf("Gagal ambil antrian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil antrian"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Seat godoc
+// @Summary Panggil antrian ke meja
+// @Description Tanpa table_id, meja kosong terkecil yang cukup dipilih otomatis, atau gabungan meja bersebelahan. Kunjungan pelanggan dan order dine-in langsung dibuka.
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "ID antrian"
+// @Param request body SeatRequest false "Meja dan waiter"
+// @Success 200 {object} models.SeatResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist/{id}/seat [post]
+func (h *WaitlistHandler) Seat(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+	var req SeatRequest
+	tableIDs, waiterID, err := req.bind(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Seat(c.Request.Context(), id, tableIDs, waiterID)
+	if err != nil {
+		if status, ok := waitlistErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal memanggil antrian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memanggil antrian"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Remove godoc
+// @Summary Keluarkan tamu dari antrian
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "ID antrian"
+// @Param request body RemoveWaitlistRequest true "left = tamu pergi, canceled = dibatalkan"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /waitlist/{id}/remove [post]
+func (h *WaitlistHandler) Remove(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return
+	}
+	var req RemoveWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), id, req.Status); err != nil {
+		if status, ok := waitlistErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Gagal mengeluarkan antrian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengeluarkan antrian"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Antrian diperbarui"})
+}
+
+func waitlistErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, true
+	case errors.Is(err, repositories.ErrWaitlistNotWaiting):
+		return http.StatusConflict, true
+	}
+	return seatErrorStatus(err)
+}
+
+func seatErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, repositories.ErrTableNotFree), errors.Is(err, services.ErrNoTableAvailable):
+		return http.StatusConflict, true
+	case errors.Is(err, repositories.ErrInvalidSeatTable):
+		return http.StatusBadRequest, true
+	}
+	return 0, false
+}
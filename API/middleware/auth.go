@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"pos-restaurant/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ctxStaffID    = "staff_id"
+	ctxStaffRole  = "staff_role"
+	ctxQueryToken = "query_access_token"
+
+	queryTokenParam = "access_token"
+)
+
+// AuthRequired memvalidasi header "Authorization: Bearer <token>" dan menyimpan identitas staff ke context.
+// Staff yang sudah nonaktif atau berganti role ditolak walaupun tokennya belum kedaluwarsa.
+func AuthRequired(auth *services.AuthService) gin.HandlerFunc {
+	return authenticate(auth, false)
+}
+
+// StreamAuthRequired sama dengan AuthRequired, tetapi juga menerima query access_token karena EventSource di
+// browser tidak bisa mengirim header. Hanya untuk route SSE, token diambil dari StripQueryToken.
+func StreamAuthRequired(auth *services.AuthService) gin.HandlerFunc {
+	return authenticate(auth, true)
+}
+
+// StripQueryToken memindahkan query access_token ke context sebelum logger mencatat URL, sehingga token sesi
+// tidak pernah tertulis di access log. Harus dipasang sebelum gin.Logger.
+func StripQueryToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.Contains(c.Request.URL.RawQuery, queryTokenParam) {
+			q := c.Request.URL.Query()
+			if q.Has(queryTokenParam) {
+				c.Set(ctxQueryToken, q.Get(queryTokenParam))
+				q.Del(queryTokenParam)
+				c.Request.URL.RawQuery = q.Encode()
+			}
+		}
+		c.Next()
+	}
+}
+
+func authenticate(auth *services.AuthService, allowQueryToken bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok && header == "" && allowQueryToken {
+			token, ok = c.GetString(ctxQueryToken), true
+		}
+		if !ok || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token tidak ditemukan"})
+			return
+		}
+
+		claims, err := auth.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			var msg string
+			switch {
+			case errors.Is(err, services.ErrTokenExpired), errors.Is(err, services.ErrSessionRevoked):
+				msg = "Sesi sudah berakhir, silakan login ulang"
+			case errors.Is(err, services.ErrInvalidToken):
+				msg = "Token tidak valid"
+			default:
+				log.Printf("Gagal memverifikasi sesi: %v", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Gagal memverifikasi sesi"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": msg})
+			return
+		}
+
+		c.Set(ctxStaffID, claims.StaffID)
+		c.Set(ctxStaffRole, claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole hanya meneruskan request jika role staff termasuk dalam roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, StaffRole(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Role anda tidak memiliki akses"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// StaffID mengembalikan ID staff yang sedang login (0 jika tidak ada)
+func StaffID(c *gin.Context) int {
+	return c.GetInt(ctxStaffID)
+}
+
+// StaffRole mengembalikan role staff yang sedang login
+func StaffRole(c *gin.Context) string {
+	return c.GetString(ctxStaffRole)
+}
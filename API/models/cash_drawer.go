@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql"
+	"pos-restaurant/money"
+	"time"
+)
+
+// Status sesi laci kas
+const (
+	DrawerOpen   = "open"
+	DrawerClosed = "closed"
+)
+
+// Jenis laporan laci kas: X dicetak di tengah shift tanpa menutup sesi, Z saat sesi ditutup
+const (
+	DrawerReportX = "X"
+	DrawerReportZ = "Z"
+)
+
+// Metode yang fisiknya ada di laci (uang, slip EDC, voucher) sehingga dihitung saat tutup sesi.
+// Room charge sudah diposting ke folio kamar dan hanya dilaporkan.
+var drawerCountable = map[string]bool{
+	PaymentCash:       true,
+	PaymentCreditCard: true,
+	PaymentDebitCard:  true,
+	PaymentVoucher:    true,
+}
+
+func DrawerCountable(method string) bool {
+	return drawerCountable[method]
+}
+
+// Cash Drawer Sessions
+type CashDrawerSession struct {
+	ID           int            `json:"id"`
+	OutletID     int            `json:"outlet_id"`
+	CashierID    int            `json:"cashier_id"`
+	CashierName  string         `json:"cashier_name"`
+	OpeningFloat money.Amount   `json:"opening_float"`
+	Status       string         `json:"status"`
+	OpenedAt     time.Time      `json:"opened_at"`
+	ClosedAt     sql.NullTime   `json:"closed_at"`
+	ClosedBy     sql.NullInt64  `json:"closed_by"`
+	Notes        sql.NullString `json:"notes"`
+}
+
+// DrawerCount adalah hasil hitung fisik kasir untuk satu metode pembayaran
+type DrawerCount struct {
+	PaymentMethod string       `json:"payment_method" binding:"required,oneof=cash credit_card debit_card voucher"`
+	Amount        money.Amount `json:"amount" binding:"gte=0"`
+}
+
+// DrawerMethodTotal merangkum satu metode pembayaran dalam sesi
+type DrawerMethodTotal struct {
+	PaymentMethod string        `json:"payment_method"`
+	Payments      int           `json:"payments"`
+	Sales         money.Amount  `json:"sales"` // nominal yang mengurangi tagihan
+	Tips          money.Amount  `json:"tips"`
+	Refunds       money.Amount  `json:"refunds"`
+	Expected      money.Amount  `json:"expected"`             // seharusnya ada di laci, cash termasuk modal awal
+	Counted       *money.Amount `json:"counted,omitempty"`    // hanya di laporan Z untuk metode yang dihitung
+	OverShort     *money.Amount `json:"over_short,omitempty"` // counted - expected, negatif = kurang
+}
+
+// DrawerReport adalah laporan X/Z satu sesi laci kas
+type DrawerReport struct {
+	Type         string              `json:"type"`
+	Session      *CashDrawerSession  `json:"session"`
+	Methods      []DrawerMethodTotal `json:"methods"`
+	TotalSales   money.Amount        `json:"total_sales"`
+	TotalTips    money.Amount        `json:"total_tips"`
+	TotalRefunds money.Amount        `json:"total_refunds"`
+	OverShort    money.Amount        `json:"over_short"` // jumlah selisih semua metode yang dihitung
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
+
+// OutletDrawerReport menggabungkan laporan X (sesi yang masih buka) atau Z (sesi yang ditutup pada satu tanggal) per outlet
+type OutletDrawerReport struct {
+	Type         string              `json:"type"`
+	OutletID     int                 `json:"outlet_id"`
+	Date         string              `json:"date,omitempty"` // laporan Z: tanggal tutup sesi
+	Sessions     []*DrawerReport     `json:"sessions"`
+	Methods      []DrawerMethodTotal `json:"methods"`
+	TotalSales   money.Amount        `json:"total_sales"`
+	TotalTips    money.Amount        `json:"total_tips"`
+	TotalRefunds money.Amount        `json:"total_refunds"`
+	OverShort    money.Amount        `json:"over_short"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Course hidangan untuk order dine-in, dikirim ke dapur bertahap sesuai urutan
+const (
+	CourseAppetizer = "appetizer"
+	CourseMain      = "main"
+	CourseDessert   = "dessert"
+)
+
+// Urutan penyajian course
+var courseSeq = map[string]int{
+	CourseAppetizer: 1,
+	CourseMain:      2,
+	CourseDessert:   3,
+}
+
+// ValidCourse memeriksa nama course, string kosong berarti item tanpa course (langsung ke dapur)
+func ValidCourse(course string) bool {
+	_, ok := courseSeq[course]
+	return ok || course == ""
+}
+
+// Status course di satu order
+const (
+	CourseHeld   = "held"
+	CourseFired  = "fired"
+	CourseServed = "served"
+)
+
+// Status course per order: ditahan sampai waiter fire, served saat semua item aktifnya sudah disajikan
+type OrderCourse struct {
+	OrderID   int           `json:"order_id"`
+	Course    string        `json:"course"`
+	Status    string        `json:"status"`
+	Items     int           `json:"items"` // item aktif (belum void)
+	FiredAt   sql.NullTime  `json:"fired_at"`
+	FiredBy   sql.NullInt64 `json:"fired_by"`
+	ServedAt  sql.NullTime  `json:"served_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Waktu layanan per course untuk laporan, dalam menit
+type CourseServiceTime struct {
+	OutletID       int     `json:"outlet_id"`
+	Course         string  `json:"course"`
+	Orders         int     `json:"orders"`
+	AvgFireToServe float64 `json:"avg_fire_to_serve_minutes"` // fire sampai semua item served
+	MaxFireToServe float64 `json:"max_fire_to_serve_minutes"`
+	AvgOrderToFire float64 `json:"avg_order_to_fire_minutes"` // order dibuka sampai course di-fire
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"pos-restaurant/money"
+	"time"
+)
+
+// Status meja. available/occupied/reserved dihitung otomatis dari order dan reservasi, out_of_order diatur manual.
+const (
+	TableAvailable  = "available"
+	TableOccupied   = "occupied"
+	TableReserved   = "reserved"
+	TableOutOfOrder = "out_of_order"
+)
+
+// TableStatusChange adalah hasil perhitungan ulang status satu meja, dipakai untuk event
+type TableStatusChange struct {
+	TableID     int
+	OutletID    int
+	TableNumber string
+	From        string
+	To          string
+}
+
+// TableLayout adalah posisi satu meja di denah lantai
+type TableLayout struct {
+	TableID int    `json:"table_id" binding:"required"`
+	Section string `json:"section"`
+	Shape   string `json:"shape" binding:"omitempty,oneof=square round rectangle"`
+	PosX    int    `json:"pos_x"`
+	PosY    int    `json:"pos_y"`
+	// Meja sebelah yang bisa digabung untuk rombongan besar, null = tidak diubah, [] = hapus semua
+	Adjacent []int `json:"adjacent"`
+}
+
+// FloorTable adalah satu meja di tampilan denah beserta order yang sedang berjalan
+type FloorTable struct {
+	TableID         int               `json:"table_id"`
+	TableNumber     string            `json:"table_number"`
+	Capacity        int               `json:"capacity"`
+	Section         string            `json:"section"`
+	Shape           string            `json:"shape"`
+	PosX            int               `json:"pos_x"`
+	PosY            int               `json:"pos_y"`
+	Status          string            `json:"status"`
+	Adjacent        []int             `json:"adjacent"` // meja yang bisa digabung
+	Orders          []FloorOrder      `json:"orders"`
+	ElapsedMinutes  int               `json:"elapsed_minutes"` // sejak order pertama di meja dibuka
+	RunningTotal    money.Amount      `json:"running_total"`   // total semua order aktif termasuk service & pajak
+	NextReservation *FloorReservation `json:"next_reservation,omitempty"`
+}
+
+type FloorOrder struct {
+	OrderID     int          `json:"order_id"`
+	OrderNumber string       `json:"order_number"`
+	Waiter      string       `json:"waiter"`
+	Items       int          `json:"items"` // item aktif
+	Total       money.Amount `json:"total"`
+	Paid        money.Amount `json:"paid"`
+	OpenedAt    time.Time    `json:"opened_at"`
+}
+
+type FloorReservation struct {
+	ReservationID   int       `json:"reservation_id"`
+	CustomerName    string    `json:"customer_name"`
+	ReservationTime time.Time `json:"reservation_time"`
+	Pax             int       `json:"pax"`
+	Status          string    `json:"status"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Kitchen Stations (bar, grill, pastry, dll)
+type KitchenStation struct {
+	ID           int          `json:"id"`
+	Name         string       `json:"name"`
+	Printer      string       `json:"printer"`       // nama printer di POS_PRINTERS, kosong = hanya KDS
+	PrinterWidth int          `json:"printer_width"` // karakter per baris tiket
+	CreatedAt    time.Time    `json:"created_at"`
+	DeletedAt    sql.NullTime `json:"deleted_at"`
+}
+
+// Kitchen Tickets, satu tiket per order per station setiap kali item dikirim ke dapur
+type KitchenTicket struct {
+	ID        int           `json:"id"`
+	OrderID   int           `json:"order_id"`
+	StationID sql.NullInt64 `json:"station_id"`
+	Course    string        `json:"course"`
+	FiredAt   sql.NullTime  `json:"fired_at"` // NULL = ditahan (hold)
+	FiredBy   sql.NullInt64 `json:"fired_by"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Status item di dapur
+const (
+	KitchenQueued  = "queued"
+	KitchenCooking = "cooking"
+	KitchenReady   = "ready"
+	KitchenServed  = "served"
+)
+
+// Urutan status saat item di-bump
+var kitchenNextStatus = map[string]string{
+	KitchenQueued:  KitchenCooking,
+	KitchenCooking: KitchenReady,
+	KitchenReady:   KitchenServed,
+}
+
+// NextKitchenStatus mengembalikan status berikutnya, false jika item sudah served
+func NextKitchenStatus(status string) (string, bool) {
+	next, ok := kitchenNextStatus[status]
+	return next, ok
+}
+
+// Item pada antrian KDS
+type KitchenQueueItem struct {
+	TicketItemID        int           `json:"ticket_item_id"`
+	TicketID            int           `json:"ticket_id"`
+	OrderID             int           `json:"order_id"`
+	OrderNumber         string        `json:"order_number"`
+	TableNumber         string        `json:"table_number"`
+	StationID           sql.NullInt64 `json:"station_id"`
+	StationName         string        `json:"station_name"`
+	OrderItemID         int           `json:"order_item_id"`
+	MenuItemID          int           `json:"menu_item_id"`
+	MenuName            string        `json:"menu_name"`
+	Course              string        `json:"course"`
+	Qty                 float64       `json:"qty"`
+	Notes               string        `json:"notes"`
+	ExcludedIngredients []string      `json:"excluded_ingredients"`
+	PreparationTime     sql.NullInt64 `json:"preparation_time"`
+	Status              string        `json:"status"`
+	OrderedAt           time.Time     `json:"ordered_at"`
+	FiredAt             time.Time     `json:"fired_at"` // sama dengan ordered_at kecuali item sempat ditahan
+	StartedAt           sql.NullTime  `json:"started_at"`
+	ReadyAt             sql.NullTime  `json:"ready_at"`
+}
+
+// Status job cetak tiket dapur
+const (
+	PrintPending  = "pending"
+	PrintPrinting = "printing"
+	PrintPrinted  = "printed"
+	PrintFailed   = "failed"
+)
+
+// Job cetak tiket dapur di antrian spooler
+type KitchenPrintJob struct {
+	ID            int            `json:"id"`
+	TicketID      int            `json:"ticket_id"`
+	OrderID       int            `json:"order_id"`
+	StationName   string         `json:"station_name"`
+	Printer       string         `json:"printer"`
+	Reprint       bool           `json:"reprint"`
+	Status        string         `json:"status"`
+	Attempts      int            `json:"attempts"`
+	LastError     sql.NullString `json:"last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	PrintedAt     sql.NullTime   `json:"printed_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
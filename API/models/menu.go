@@ -2,15 +2,17 @@ package models
 
 import (
 	"database/sql"
+	"pos-restaurant/money"
 	"time"
 )
 
 // Menu Category
 type MenuCategory struct {
-	ID        int          `json:"id"`
-	Name      string       `json:"name"`
-	CreatedAt time.Time    `json:"created_at"`
-	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	StationID sql.NullInt64 `json:"station_id"` // Station dapur tujuan tiket
+	CreatedAt time.Time     `json:"created_at"`
+	DeletedAt sql.NullTime  `json:"deleted_at"`
 }
 
 // Ingredients
@@ -34,8 +36,8 @@ type MenuItem struct {
 	SKU             string         `json:"sku"`
 	Name            string         `json:"name"`
 	Description     sql.NullString `json:"description"`
-	Price           float64        `json:"price"`
-	Cost            float64        `json:"cost"`
+	Price           money.Amount   `json:"price"`
+	Cost            money.Amount   `json:"cost"`
 	IsActive        bool           `json:"is_active"`
 	PreparationTime sql.NullInt64  `json:"preparation_time"`
 	Tags            []string       `json:"tags"` // parsed manually from JSONB
@@ -59,5 +61,87 @@ type MenuIngredient struct {
 // Menu details with ingredients
 type MenuItemWithIngredients struct {
 	MenuItem
-	Ingredients []Ingredient `json:"ingredients"`
+	Ingredients    []Ingredient    `json:"ingredients"`
+	ModifierGroups []ModifierGroup `json:"modifier_groups"`
+}
+
+// Modifier Groups (ukuran, kematangan, extra topping) per menu item
+type ModifierGroup struct {
+	ID         int              `json:"id"`
+	MenuItemID int              `json:"menu_item_id"`
+	Name       string           `json:"name"`
+	MinSelect  int              `json:"min_select"` // 1 = wajib pilih
+	MaxSelect  int              `json:"max_select"`
+	Options    []ModifierOption `json:"options"`
+}
+
+type ModifierOption struct {
+	ID            int           `json:"id"`
+	GroupID       int           `json:"group_id"`
+	Name          string        `json:"name"`
+	PriceDelta    money.Amount  `json:"price_delta"`
+	IngredientID  sql.NullInt64 `json:"ingredient_id"` // Bahan extra yang ikut dipakai
+	IngredientQty float64       `json:"ingredient_qty"`
+	IsActive      bool          `json:"is_active"`
+}
+
+// Combo / set menu dengan harga paket
+type Combo struct {
+	ID          int            `json:"id"`
+	SKU         sql.NullString `json:"sku"`
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	Price       money.Amount   `json:"price"`
+	IsActive    bool           `json:"is_active"`
+	Slots       []ComboSlot    `json:"slots"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Slot paket (Main, Drink, Dessert), slot dengan satu pilihan berarti komponen tetap
+type ComboSlot struct {
+	ID      int               `json:"id"`
+	ComboID int               `json:"combo_id"`
+	Name    string            `json:"name"`
+	Options []ComboSlotOption `json:"options"`
+}
+
+type ComboSlotOption struct {
+	ID         int          `json:"id"`
+	SlotID     int          `json:"slot_id"`
+	MenuItemID int          `json:"menu_item_id"`
+	MenuName   string       `json:"menu_name"`
+	PriceDelta money.Amount `json:"price_delta"` // Upcharge
+	IsDefault  bool         `json:"is_default"`
+}
+
+// Stock Movements (ledger stok bahan, append-only)
+type StockMovement struct {
+	ID           int            `json:"id"`
+	IngredientID int            `json:"ingredient_id"`
+	MovementType string         `json:"movement_type"`
+	QtyChange    float64        `json:"qty_change"` // Negatif = stok keluar
+	OrderItemID  sql.NullInt64  `json:"order_item_id"`
+	StaffID      sql.NullInt64  `json:"staff_id"`
+	Notes        sql.NullString `json:"notes"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// Jenis pergerakan stok
+const (
+	StockOpeningBalance = "opening_balance" // saldo awal bahan yang sudah ada sebelum ledger dipakai
+	StockSale           = "sale"
+	StockReceiving      = "receiving"
+	StockWaste          = "waste"
+	StockAdjustment     = "adjustment"
+	StockVoidReversal   = "void_reversal"
+)
+
+// Perbandingan stok saat ini dengan total ledger
+type StockReconciliation struct {
+	IngredientID int     `json:"ingredient_id"`
+	CurrentQty   float64 `json:"current_qty"`
+	LedgerQty    float64 `json:"ledger_qty"`
+	Difference   float64 `json:"difference"`
+	IsBalanced   bool    `json:"is_balanced"`
 }
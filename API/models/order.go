@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"pos-restaurant/money"
+	"slices"
 	"time"
 )
 
@@ -20,6 +22,42 @@ type Order struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
+// Status order
+const (
+	OrderOpen        = "open"
+	OrderSettled     = "settled"
+	OrderVoid        = "void"
+	OrderTransferred = "transferred"
+	OrderMerged      = "merged" // item sudah dipindah ke order lain lewat merge
+)
+
+// Transisi status order yang diizinkan, settled, void & merged adalah status akhir
+var orderTransitions = map[string][]string{
+	OrderOpen:        {OrderSettled, OrderVoid, OrderTransferred, OrderMerged},
+	OrderTransferred: {OrderSettled, OrderVoid, OrderMerged},
+}
+
+func CanTransitionOrder(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// IsLiveOrder: order masih berjalan (open, atau transferred = sudah pindah meja), masih bisa diubah,
+// ditagih dan menempati meja
+func IsLiveOrder(status string) bool {
+	return status == OrderOpen || status == OrderTransferred
+}
+
+// Riwayat perubahan status order
+type OrderStatusChange struct {
+	ID         int            `json:"id"`
+	OrderID    int            `json:"order_id"`
+	FromStatus sql.NullString `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	ChangedBy  sql.NullInt64  `json:"changed_by"`
+	Reason     sql.NullString `json:"reason"`
+	ChangedAt  time.Time      `json:"changed_at"`
+}
+
 type OrderRequest struct {
 	ID          int              `json:"id"`
 	OrderNumber string           `json:"order_number"`
@@ -30,24 +68,58 @@ type OrderRequest struct {
 	OutletID    int              `json:"outlet_id"`
 	Status      string           `json:"status"`
 	OrderType   string           `json:"order_type"`
-	Items       []OrderItemInput `json:"items"`
+	Items       []OrderItemInput `json:"items" binding:"dive"`
 }
 
 type OrderItemInput struct {
-	ID                    int     `json:"id"`
-	MenuItemID            int     `json:"menu_item_id"`
-	Qty                   float64 `json:"qty"`
-	Notes                 string  `json:"notes,omitempty"`
-	UnitPrice             float64 `json:"unit_price"` // captured per item
-	ExcludedIngredientIDs []int   `json:"excluded_ingredients"`
+	ID                    int            `json:"id"`
+	MenuItemID            int            `json:"menu_item_id"`
+	Qty                   float64        `json:"qty" binding:"required,gt=0"`
+	Notes                 string         `json:"notes,omitempty"`
+	SeatNo                int            `json:"seat_no,omitempty" binding:"gte=0"` // 0 = hidangan bersama
+	UnitPrice             money.Amount   `json:"unit_price"`                        // diisi server dari menu_items.price, nilai dari client diabaikan
+	PriceOverride         *PriceOverride `json:"price_override,omitempty"`
+	ModifierOptionIDs     []int          `json:"modifier_option_ids"`
+	ModifiersPrice        money.Amount   `json:"modifiers_price"` // diisi server, total price_delta per unit
+	ExcludedIngredientIDs []int          `json:"excluded_ingredients"`
+	Course                string         `json:"course,omitempty"` // appetizer, main, dessert; dine-in ditahan sampai course di-fire
+	Hold                  bool           `json:"hold,omitempty"`   // tahan di dapur sampai waiter fire
+
+	// Paket: isi combo_id (menu_item_id diabaikan), komponen dikembalikan sebagai item dengan order_combo_id
+	ComboID         int              `json:"combo_id,omitempty"`
+	ComboSelections []ComboSelection `json:"combo_selections,omitempty"`
+	OrderComboID    int              `json:"order_combo_id,omitempty"`
 }
 
 type AddOrderItemRequest struct {
-	MenuItemID            int     `json:"menu_item_id" binding:"required"`
-	Qty                   float64 `json:"qty" binding:"required"`
-	Notes                 string  `json:"notes,omitempty"`
-	UnitPrice             float64 `json:"unit_price"` // captured per item
-	ExcludedIngredientIDs []int   `json:"excluded_ingredient_ids"`
+	MenuItemID            int              `json:"menu_item_id" binding:"required_without=ComboID"`
+	Qty                   float64          `json:"qty" binding:"required,gt=0"`
+	Notes                 string           `json:"notes,omitempty"`
+	SeatNo                int              `json:"seat_no,omitempty" binding:"gte=0"`
+	PriceOverride         *PriceOverride   `json:"price_override,omitempty"`
+	ModifierOptionIDs     []int            `json:"modifier_option_ids"`
+	ExcludedIngredientIDs []int            `json:"excluded_ingredient_ids"`
+	ComboID               int              `json:"combo_id,omitempty"`
+	ComboSelections       []ComboSelection `json:"combo_selections,omitempty"`
+	Course                string           `json:"course,omitempty" binding:"omitempty,oneof=appetizer main dessert"`
+	Hold                  bool             `json:"hold,omitempty"`
+}
+
+// ComboSelection adalah pilihan menu untuk satu slot paket.
+// Slot yang tidak dipilih memakai option default (atau satu-satunya option).
+type ComboSelection struct {
+	SlotID                int   `json:"slot_id"`
+	MenuItemID            int   `json:"menu_item_id"`
+	ModifierOptionIDs     []int `json:"modifier_option_ids"`
+	ExcludedIngredientIDs []int `json:"excluded_ingredient_ids"`
+}
+
+// PriceOverride adalah harga khusus per item yang harus disetujui manager/supervisor (dengan PIN)
+type PriceOverride struct {
+	Price       money.Amount `json:"price"`
+	Reason      string       `json:"reason"`
+	ApprovedBy  int          `json:"approved_by"`
+	ApproverPin string       `json:"approver_pin,omitempty"` // hanya input, tidak pernah dikembalikan
 }
 
 // Bills
@@ -57,26 +129,48 @@ type Bill struct {
 	OrderID        int           `json:"order_id"`
 	OriginalBillID sql.NullInt64 `json:"original_bill_id"`
 	Status         string        `json:"status"`
-	Subtotal       float64       `json:"subtotal"`
-	TaxAmount      float64       `json:"tax_amount"`
-	ServiceCharge  float64       `json:"service_charge"`
-	DiscountAmount float64       `json:"discount_amount"`
-	TotalAmount    float64       `json:"total_amount"`
-	PaidAmount     float64       `json:"paid_amount"`
-	BalanceDue     float64       `json:"balance_due"`
+	Subtotal       money.Amount  `json:"subtotal"`
+	TaxAmount      money.Amount  `json:"tax_amount"`
+	ServiceCharge  money.Amount  `json:"service_charge"`
+	DiscountAmount money.Amount  `json:"discount_amount"`
+	TaxIncluded    money.Amount  `json:"tax_included"` // Bagian tax_amount yang sudah termasuk harga menu
+	RoundingAmount money.Amount  `json:"rounding_amount"`
+	TotalAmount    money.Amount  `json:"total_amount"`
+	PaidAmount     money.Amount  `json:"paid_amount"`
+	RefundedAmount money.Amount  `json:"refunded_amount"`
+	BalanceDue     money.Amount  `json:"balance_due"` // total_amount - refunded_amount - paid_amount
+	TipAmount      money.Amount  `json:"tip_amount"`
 	CreatedAt      time.Time     `json:"created_at"`
 	UpdatedAt      time.Time     `json:"updated_at"`
 }
 
+// Mode split bill
+const (
+	SplitByItems    = "items"    // item utuh per bill (default)
+	SplitByQuantity = "quantity" // qty satu item bisa dibagi ke beberapa bill
+	SplitBySeat     = "seat"     // per nomor kursi, hidangan tanpa kursi dibagi rata
+	SplitEvenly     = "even"     // seluruh tagihan dibagi rata ke N tamu
+)
+
 type SplitBillRequest struct {
 	OriginalOrderID int              `json:"original_order_id"`
 	OriginalBillID  int              `json:"original_bill_id"` // <- Tambahan
+	Mode            string           `json:"mode"`
+	Guests          int              `json:"guests,omitempty"`          // mode even
+	DiscountAmount  money.Amount     `json:"discount_amount,omitempty"` // mode even, dibagi rata ke semua tamu
 	Splits          []SplitBillInput `json:"splits"`
 }
 
 type SplitBillInput struct {
-	ItemIDs        []int   `json:"item_ids"`
-	DiscountAmount float64 `json:"discount_amount"`
+	ItemIDs        []int          `json:"item_ids,omitempty"` // mode items
+	Items          []SplitItemQty `json:"items,omitempty"`    // mode quantity
+	Seats          []int          `json:"seats,omitempty"`    // mode seat
+	DiscountAmount money.Amount   `json:"discount_amount"`
+}
+
+type SplitItemQty struct {
+	OrderItemID int     `json:"order_item_id"`
+	Qty         float64 `json:"qty"`
 }
 
 // Bill Payments
@@ -84,12 +178,69 @@ type BillPayment struct {
 	ID                   int            `json:"id"`
 	BillID               int            `json:"bill_id"`
 	PaymentMethod        string         `json:"payment_method"`
-	Amount               float64        `json:"amount"`
+	Amount               money.Amount   `json:"amount"`          // nominal yang mengurangi tagihan
+	TenderedAmount       money.Amount   `json:"tendered_amount"` // diterima dari tamu, termasuk tip & kembalian
+	TipAmount            money.Amount   `json:"tip_amount"`
+	ChangeAmount         money.Amount   `json:"change_amount"`
 	ReferenceNumber      sql.NullString `json:"reference_number"`
 	RoomChargeApprovedBy sql.NullInt64  `json:"room_charge_approved_by"`
+	ReceivedBy           sql.NullInt64  `json:"received_by"`
+	PMSGuestID           sql.NullString `json:"pms_guest_id"`
+	PMSPostingID         sql.NullString `json:"pms_posting_id"`
+	DrawerSessionID      sql.NullInt64  `json:"drawer_session_id"`
 	PaymentTime          time.Time      `json:"payment_time"`
 }
 
+// Metode pembayaran, selain cash tidak boleh melebihi sisa tagihan
+const (
+	PaymentCash       = "cash"
+	PaymentCreditCard = "credit_card"
+	PaymentDebitCard  = "debit_card"
+	PaymentRoomCharge = "room_charge"
+	PaymentVoucher    = "voucher"
+	PaymentSplit      = "split"
+)
+
+// Bill Refunds: pengembalian sebagian/seluruh satu pembayaran, mengurangi tagihan bersih bill
+type BillRefund struct {
+	ID              int            `json:"id"`
+	BillID          int            `json:"bill_id"`
+	PaymentID       int            `json:"payment_id"`
+	Amount          money.Amount   `json:"amount"`
+	RefundMethod    string         `json:"refund_method"`
+	ReferenceNumber sql.NullString `json:"reference_number"`
+	Reason          string         `json:"reason"`
+	ApprovedBy      int            `json:"approved_by"`
+	RefundedBy      sql.NullInt64  `json:"refunded_by"`
+	PMSPostingID    sql.NullString `json:"pms_posting_id"`
+	DrawerSessionID sql.NullInt64  `json:"drawer_session_id"`
+	IsReversal      bool           `json:"is_reversal"` // bill belum lunas: hanya membuka lagi sisa tagihan, tidak dihitung refund penjualan
+	RefundedAt      time.Time      `json:"refunded_at"`
+	BillStatus      string         `json:"bill_status,omitempty"` // status bill setelah refund
+}
+
+// PaymentReceipt adalah bukti satu pembayaran beserta posisi bill setelahnya
+type PaymentReceipt struct {
+	PaymentID       int          `json:"payment_id"`
+	BillID          int          `json:"bill_id"`
+	BillNumber      string       `json:"bill_number"`
+	OrderID         int          `json:"order_id"`
+	PaymentMethod   string       `json:"payment_method"`
+	ReferenceNumber string       `json:"reference_number,omitempty"`
+	GuestName       string       `json:"guest_name,omitempty"`     // room_charge
+	PMSPostingID    string       `json:"pms_posting_id,omitempty"` // room_charge
+	TenderedAmount  money.Amount `json:"tendered_amount"`
+	Amount          money.Amount `json:"amount"`
+	TipAmount       money.Amount `json:"tip_amount"`
+	ChangeAmount    money.Amount `json:"change_amount"`
+	BillTotal       money.Amount `json:"bill_total"`
+	PaidAmount      money.Amount `json:"paid_amount"`
+	BalanceDue      money.Amount `json:"balance_due"`
+	BillStatus      string       `json:"bill_status"`
+	OrderSettled    bool         `json:"order_settled"`
+	PaymentTime     time.Time    `json:"payment_time"`
+}
+
 // Table Transfers
 type TableTransfer struct {
 	ID            int            `json:"id"`
@@ -100,3 +251,33 @@ type TableTransfer struct {
 	TransferredAt time.Time      `json:"transferred_at"`
 	Reason        sql.NullString `json:"reason"`
 }
+
+// Order Merges: jejak audit penggabungan order, satu baris per order sumber
+type OrderMerge struct {
+	ID            int            `json:"id"`
+	TargetOrderID int            `json:"target_order_id"`
+	SourceOrderID int            `json:"source_order_id"`
+	SourceTableID sql.NullInt64  `json:"source_table_id"`
+	ItemsMoved    int            `json:"items_moved"`
+	BillID        sql.NullInt64  `json:"bill_id"` // bill gabungan jika merge dilakukan dari bill
+	MergedBy      sql.NullInt64  `json:"merged_by"`
+	Reason        sql.NullString `json:"reason"`
+	MergedAt      time.Time      `json:"merged_at"`
+}
+
+// MergeResult adalah hasil penggabungan order (atau bill) ke satu order tujuan
+type MergeResult struct {
+	OutletID       int          `json:"outlet_id"`
+	TargetOrderID  int          `json:"target_order_id"`
+	MergedOrderIDs []int        `json:"merged_order_ids"`
+	ItemsMoved     int          `json:"items_moved"`
+	VoidedBillIDs  []int        `json:"voided_bill_ids"`
+	BillID         int          `json:"bill_id,omitempty"` // bill gabungan, hanya untuk merge bill
+	FreedTables    []FreedTable `json:"freed_tables"`
+}
+
+// FreedTable adalah meja order sumber yang kembali available setelah merge
+type FreedTable struct {
+	TableID     int    `json:"table_id"`
+	TableNumber string `json:"table_number"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"pos-restaurant/money"
 	"time"
 )
 
@@ -12,12 +13,25 @@ type Outlet struct {
 	Location             sql.NullString `json:"location"`
 	ServiceChargePercent float64        `json:"service_charge_percentage"`
 	TaxPercentage        float64        `json:"tax_percentage"`
+	PricesIncludeTax     bool           `json:"prices_include_tax"`
+	TaxOnServiceCharge   bool           `json:"tax_on_service_charge"`
+	ServiceExemptTypes   []string       `json:"service_charge_exempt_types"` // order_type tanpa service charge
+	RoundingUnit         money.Amount   `json:"rounding_unit"`               // 0 = tanpa pembulatan
+	RoundingMode         string         `json:"rounding_mode"`               // nearest, up, down
+	Currency             string         `json:"currency"`                    // ISO 4217, menentukan presisi pembulatan
 	IsActive             bool           `json:"is_active"`
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
 	DeletedAt            sql.NullTime   `json:"deleted_at"`
 }
 
+// Tarif pajak khusus kategori menu di satu outlet
+type CategoryTaxRate struct {
+	CategoryID    int     `json:"category_id"`
+	CategoryName  string  `json:"category_name,omitempty"`
+	TaxPercentage float64 `json:"tax_percentage"`
+}
+
 // Tables
 type Table struct {
 	ID           int            `json:"id"`
@@ -26,6 +40,10 @@ type Table struct {
 	Capacity     int            `json:"capacity"`
 	LocationType sql.NullString `json:"location_type"` // 'Indoor' or 'Outdoor'
 	Status       string         `json:"status"`        // available, occupied, etc.
+	Section      sql.NullString `json:"section"`
+	Shape        string         `json:"shape"` // square, round, rectangle
+	PosX         int            `json:"pos_x"`
+	PosY         int            `json:"pos_y"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    sql.NullTime   `json:"deleted_at"`
@@ -36,9 +54,18 @@ type Staff struct {
 	ID        int          `json:"id"`
 	Name      string       `json:"name"`
 	Role      string       `json:"role"`
-	PinCode   string       `json:"pin_code"`
+	PinCode   string       `json:"-"` // bcrypt hash, tidak pernah dikirim ke client
 	IsActive  bool         `json:"is_active"`
 	CreatedAt time.Time    `json:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at"`
 	DeletedAt sql.NullTime `json:"deleted_at"`
 }
+
+// Role staff
+const (
+	RoleWaiter     = "waiter"
+	RoleCashier    = "cashier"
+	RoleChef       = "chef"
+	RoleManager    = "manager"
+	RoleSupervisor = "supervisor"
+)
@@ -5,25 +5,95 @@ import (
 	"time"
 )
 
+// Status reservasi. Hanya confirmed dan seated yang memakai meja sehingga dicek bentrok jadwalnya.
+const (
+	ReservationConfirmed = "confirmed"
+	ReservationWaiting   = "waiting"
+	ReservationCanceled  = "canceled"
+	ReservationNoShow    = "no_show"
+	ReservationSeated    = "seated"
+)
+
+func ReservationHoldsTable(status string) bool {
+	return status == ReservationConfirmed || status == ReservationSeated
+}
+
+// ReservationSeatable menandai reservasi yang tamunya masih bisa didudukkan, termasuk tamu no_show yang terlambat
+func ReservationSeatable(status string) bool {
+	return status == ReservationConfirmed || status == ReservationWaiting || status == ReservationNoShow
+}
+
 // Reservations
 type Reservation struct {
 	ID              int            `json:"id"`
 	CustomerID      int            `json:"customer_id"`
+	OutletID        int            `json:"outlet_id"` // outlet meja reservasi
 	ReservationTime time.Time      `json:"reservation_time"`
 	Pax             int            `json:"pax"`
-	TableID         int            `json:"table_id"`
+	TableID         int            `json:"table_id"`  // meja utama
+	TableIDs        []int          `json:"table_ids"` // semua meja termasuk meja utama, lebih dari satu jika digabung
+	VisitType       sql.NullString `json:"visit_type"`
+	DurationMinutes int            `json:"duration_minutes"`
 	Status          string         `json:"status"`
 	SpecialRequest  sql.NullString `json:"special_request"`
+	SeatedAt        sql.NullTime   `json:"seated_at"`
+	OrderID         sql.NullInt64  `json:"order_id"` // order yang dibuka saat tamu didudukkan
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 }
 
+// End adalah waktu meja reservasi kembali bebas
+func (r *Reservation) End() time.Time {
+	return r.ReservationTime.Add(time.Duration(r.DurationMinutes) * time.Minute)
+}
+
 type ReservationWithDetails struct {
 	ID              int            `json:"id"`
 	CustomerName    string         `json:"customer_name"`
 	ReservationTime time.Time      `json:"reservation_time"`
 	Pax             int            `json:"pax"`
-	TableNumber     string         `json:"table_number"`
+	TableNumber     string         `json:"table_number"` // meja gabungan dipisah "+"
+	DurationMinutes int            `json:"duration_minutes"`
 	Status          string         `json:"status"`
 	SpecialRequest  sql.NullString `json:"special_request"`
 }
+
+// DiningPeriod adalah jam reservasi dan lama makan satu periode kunjungan di outlet
+type DiningPeriod struct {
+	VisitType       string `json:"visit_type" binding:"required,oneof=breakfast lunch dinner event"`
+	FirstSeating    string `json:"first_seating" binding:"required"` // HH:MM, slot pertama
+	LastSeating     string `json:"last_seating" binding:"required"`  // HH:MM, slot terakhir
+	DurationMinutes int    `json:"duration_minutes" binding:"required,gt=0"`
+}
+
+// ReservableTable adalah meja outlet yang bisa dipesan beserta meja bersebelahan yang bisa digabung
+type ReservableTable struct {
+	ID          int
+	TableNumber string
+	Capacity    int
+	Status      string // status meja saat ini
+	Adjacent    []int
+}
+
+// TableBooking adalah pemakaian satu meja oleh reservasi confirmed/seated
+type TableBooking struct {
+	ReservationID int
+	TableID       int
+	Start         time.Time
+	End           time.Time
+}
+
+// TableSuggestion adalah meja (atau gabungan meja bersebelahan) yang disarankan untuk rombongan
+type TableSuggestion struct {
+	TableIDs     []int    `json:"table_ids"`
+	TableNumbers []string `json:"table_numbers"`
+	Capacity     int      `json:"capacity"` // total kursi
+}
+
+// AvailabilitySlot adalah jam reservasi yang masih punya meja kosong untuk jumlah tamu yang dicari
+type AvailabilitySlot struct {
+	Time            time.Time       `json:"time"`
+	VisitType       string          `json:"visit_type"`
+	DurationMinutes int             `json:"duration_minutes"`
+	Tables          TableSuggestion `json:"tables"`
+}
@@ -1,16 +1,21 @@
 package models
 
-import "time"
+import (
+	"pos-restaurant/money"
+	"time"
+)
 
 // Sales Analysis Daily
 type SalesAnalysisDaily struct {
-	ID               int       `json:"id"`
-	OutletID         int       `json:"outlet_id"`
-	AnalysisDate     time.Time `json:"analysis_date"`
-	TotalSales       float64   `json:"total_sales"`
-	TotalCovers      int       `json:"total_covers"`
-	AvgSpendPerCover float64   `json:"avg_spend_per_cover"`
-	DiscountAmount   float64   `json:"discount_amount"`
-	VoidAmount       float64   `json:"void_amount"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               int          `json:"id"`
+	OutletID         int          `json:"outlet_id"`
+	AnalysisDate     time.Time    `json:"analysis_date"`
+	TotalSales       money.Amount `json:"total_sales"` // Subtotal bill terbayar, sebelum diskon, pajak dan service charge
+	TotalCovers      int          `json:"total_covers"`
+	AvgSpendPerCover money.Amount `json:"avg_spend_per_cover"`
+	DiscountAmount   money.Amount `json:"discount_amount"`
+	VoidAmount       money.Amount `json:"void_amount"` // Nilai item yang di-void, dasar yang sama dengan total_sales
+	RefundAmount     money.Amount `json:"refund_amount"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"` // Terakhir dihitung ulang
 }
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Status antrian walk-in
+const (
+	WaitlistWaiting  = "waiting"
+	WaitlistSeated   = "seated"
+	WaitlistLeft     = "left"     // tamu pergi sebelum dipanggil
+	WaitlistCanceled = "canceled" // dibatalkan tamu / host
+)
+
+// Waitlist Entries
+type WaitlistEntry struct {
+	ID            int            `json:"id"`
+	OutletID      int            `json:"outlet_id"`
+	CustomerID    sql.NullInt64  `json:"customer_id"`
+	GuestName     string         `json:"guest_name"`
+	Phone         sql.NullString `json:"phone"`
+	Pax           int            `json:"pax"`
+	Status        string         `json:"status"`
+	QuotedMinutes int            `json:"quoted_minutes"` // perkiraan tunggu saat mendaftar
+	Notes         sql.NullString `json:"notes"`
+	TableIDs      []int          `json:"table_ids"`
+	VisitID       sql.NullInt64  `json:"visit_id"`
+	OrderID       sql.NullInt64  `json:"order_id"`
+	CreatedBy     sql.NullInt64  `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	SeatedAt      sql.NullTime   `json:"seated_at"`
+
+	// Hanya untuk antrian yang masih waiting
+	Position         int `json:"position,omitempty"`          // urutan di antrian outlet, mulai 1
+	WaitedMinutes    int `json:"waited_minutes"`              // sudah menunggu sejak mendaftar
+	EstimatedMinutes int `json:"estimated_minutes,omitempty"` // perkiraan sisa tunggu saat ini
+}
+
+// WaitQuote adalah perkiraan lama tunggu untuk rombongan baru
+type WaitQuote struct {
+	OutletID      int `json:"outlet_id"`
+	Pax           int `json:"pax"`
+	PartiesAhead  int `json:"parties_ahead"`  // antrian di depan yang butuh meja sejenis
+	QuotedMinutes int `json:"quoted_minutes"` // dibulatkan ke atas per 5 menit
+	TurnMinutes   int `json:"turn_minutes"`   // rata-rata lama meja dipakai yang dipakai untuk perkiraan
+}
+
+// TableTurn adalah kondisi satu meja untuk memperkirakan kapan meja kosong
+type TableTurn struct {
+	TableID         int
+	Capacity        int
+	OccupiedMinutes sql.NullInt64 // sejak order aktif pertama dibuka, NULL jika tidak ada order aktif
+	BookedMinutes   sql.NullInt64 // sisa waktu sampai reservasi yang sedang/akan memakai meja selesai
+}
+
+// Seating adalah rombongan yang didudukkan di meja: tamu walk-in dari antrian atau tamu reservasi
+type Seating struct {
+	OutletID      int
+	CustomerID    int
+	Pax           int
+	TableIDs      []int // meja pertama menjadi meja order
+	WaiterID      int
+	VisitType     string
+	ReservationID sql.NullInt64
+}
+
+// SeatResult adalah meja, kunjungan dan order yang dibuka saat rombongan duduk
+type SeatResult struct {
+	TableIDs    []int  `json:"table_ids"`
+	CustomerID  int    `json:"customer_id"`
+	VisitID     int    `json:"visit_id"`
+	OrderID     int    `json:"order_id"`
+	OrderNumber string `json:"order_number"`
+}
@@ -0,0 +1,330 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Amount adalah nominal uang dalam sen (1/100), sesuai kolom DECIMAL(x,2) di database.
+// Semua penjumlahan dilakukan dalam integer sehingga tidak ada selisih pembulatan float.
+type Amount int64
+
+// Scale adalah jumlah sen dalam satu satuan mata uang
+const Scale = 100
+
+var ErrInvalidAmount = errors.New("nominal uang tidak valid")
+
+// Currency menentukan presisi pembulatan untuk mata uang tertentu
+type Currency struct {
+	Code     string
+	Decimals int // digit desimal yang dipakai, maksimal 2 (presisi kolom database)
+}
+
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", Decimals: 2},
+	"USD": {Code: "USD", Decimals: 2},
+	"SGD": {Code: "SGD", Decimals: 2},
+	"MYR": {Code: "MYR", Decimals: 2},
+	"JPY": {Code: "JPY", Decimals: 0},
+	"KRW": {Code: "KRW", Decimals: 0},
+	"VND": {Code: "VND", Decimals: 0},
+}
+
+// DefaultCurrency dipakai bila outlet tidak menyebut mata uang
+var DefaultCurrency = currencies["IDR"]
+
+// LookupCurrency mengambil aturan mata uang berdasarkan kode ISO 4217
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// New membuat Amount dari satuan utuh dan sen, contoh New(12, 50) = 12.50
+func New(units, cents int64) Amount {
+	return Amount(units*Scale + cents)
+}
+
+// FromFloat membulatkan float64 ke sen terdekat (half away from zero).
+// Hanya untuk nilai yang memang datang sebagai float, contoh query string.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * Scale))
+}
+
+// decimalPattern hanya menerima desimal polos, tanpa pecahan "1/3" maupun eksponen "1e3"
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Parse membaca nominal desimal polos secara eksak, contoh "15000.50".
+// Digit desimal melebihi presisi kolom (2 digit) ditolak, bukan dibulatkan.
+func Parse(s string) (Amount, error) {
+	return ParseIn(s, Currency{Decimals: 2})
+}
+
+// ParseIn seperti Parse, tetapi menolak digit desimal melebihi presisi mata uang c,
+// contoh "100.50" ditolak untuk JPY
+func ParseIn(s string, c Currency) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > c.Decimals {
+		return 0, fmt.Errorf("%w: %q melebihi %d digit desimal", ErrInvalidAmount, s, c.Decimals)
+	}
+	return parseRounded(s)
+}
+
+// parseRounded membaca desimal dari database secara eksak, digit setelah 2 desimal
+// (contoh hasil AVG) dibulatkan half away from zero
+func parseRounded(s string) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r.Mul(r, big.NewRat(Scale, 1))
+	return ratToAmount(r)
+}
+
+func ratToAmount(r *big.Rat) (Amount, error) {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	// half away from zero: (2*num + den) / (2*den)
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	q := num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("%w: nominal terlalu besar", ErrInvalidAmount)
+	}
+	if neg {
+		return Amount(-q.Int64()), nil
+	}
+	return Amount(q.Int64()), nil
+}
+
+// Cents mengembalikan nilai dalam sen
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Float64 hanya untuk tampilan atau perhitungan non-uang (rasio, grafik)
+func (a Amount) Float64() float64 {
+	return float64(a) / Scale
+}
+
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/Scale, v%Scale)
+}
+
+// MulDiv menghitung a * num / den dengan pembulatan half away from zero ke sen
+func (a Amount) MulDiv(num, den int64) Amount {
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num)), big.NewInt(den))
+	v, err := ratToAmount(r)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MulQty mengalikan harga satuan dengan qty DECIMAL(6,2)
+func (a Amount) MulQty(qty float64) Amount {
+	return a.MulDiv(int64(math.Round(qty*100)), 100)
+}
+
+// Percent menghitung pct% dari a, pct memakai presisi 2 desimal seperti kolom persentase di database
+func (a Amount) Percent(pct float64) Amount {
+	return a.MulDiv(BasisPoints(pct), 10000)
+}
+
+// BasisPoints mengubah persen (10.5) menjadi basis poin (1050) supaya bisa dihitung dalam integer
+func BasisPoints(pct float64) int64 {
+	return int64(math.Round(pct * 100))
+}
+
+// Round membulatkan ke presisi mata uang (half away from zero)
+func (a Amount) Round(c Currency) Amount {
+	return a.RoundTo(c.Unit(), "nearest")
+}
+
+// Unit adalah nominal terkecil mata uang dalam sen, contoh 1 untuk IDR dan 100 untuk JPY
+func (c Currency) Unit() Amount {
+	u := Amount(Scale)
+	for i := 0; i < c.Decimals && u > 1; i++ {
+		u /= 10
+	}
+	return u
+}
+
+// RoundTo membulatkan ke kelipatan unit (contoh 100 rupiah) dengan mode nearest, up atau down
+func (a Amount) RoundTo(unit Amount, mode string) Amount {
+	if unit <= 1 {
+		return a
+	}
+	q, rem := a/unit, a%unit
+	if rem == 0 {
+		return a
+	}
+	switch mode {
+	case "up":
+		if rem > 0 {
+			q++
+		}
+	case "down":
+		if rem < 0 {
+			q--
+		}
+	default:
+		if rem >= unit-rem && rem > 0 {
+			q++
+		} else if rem < 0 && -rem >= unit+rem {
+			q--
+		}
+	}
+	return q * unit
+}
+
+// Allocate membagi total ke beberapa bagian sesuai bobot dengan metode largest remainder,
+// sehingga jumlah seluruh bagian selalu tepat sama dengan total. Bobot nol semua = dibagi rata.
+func Allocate(total Amount, weights []Amount) []Amount {
+	shares := make([]Amount, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	var weightSum int64
+	for _, w := range weights {
+		weightSum += int64(w)
+	}
+	if weightSum == 0 {
+		weights = make([]Amount, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		weightSum = int64(len(weights))
+	}
+
+	sign := Amount(1)
+	if total < 0 {
+		sign, total = -1, -total
+	}
+
+	remainders := make([]*big.Int, len(weights))
+	var allocated Amount
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(
+			new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(int64(w))),
+			big.NewInt(weightSum), new(big.Int))
+		shares[i] = Amount(q.Int64())
+		remainders[i] = r
+		allocated += shares[i]
+	}
+
+	// Sisa sen diberikan satu per satu ke bagian dengan sisa pembagian terbesar
+	for left := total - allocated; left > 0; left-- {
+		best := 0
+		for i := range remainders {
+			if remainders[i].Cmp(remainders[best]) > 0 {
+				best = i
+			}
+		}
+		shares[best]++
+		remainders[best] = big.NewInt(-1)
+	}
+
+	for i := range shares {
+		shares[i] *= sign
+	}
+	return shares
+}
+
+// Value menyimpan Amount sebagai string desimal agar tidak melewati float di driver
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan membaca DECIMAL dari database secara eksak
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		return a.scanDecimal(string(v))
+	case string:
+		return a.scanDecimal(v)
+	case int64:
+		*a = Amount(v * Scale)
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("%w: tipe %T tidak didukung", ErrInvalidAmount, src)
+}
+
+func (a *Amount) scanDecimal(s string) error {
+	v, err := parseRounded(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func (a *Amount) parseInto(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON menulis Amount sebagai angka JSON dengan 2 desimal, contoh 15000.50
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON menerima angka atau string angka, dibaca dari teks aslinya tanpa melewati float
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if unq, err := strconv.Unquote(s); err == nil {
+		s = unq
+	}
+	return a.parseInto(s)
+}
+
+// NullAmount untuk kolom uang yang boleh NULL, seperti sql.NullFloat64
+type NullAmount struct {
+	Amount Amount
+	Valid  bool
+}
+
+func (n NullAmount) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Amount.Value()
+}
+
+func (n *NullAmount) Scan(src any) error {
+	if src == nil {
+		n.Amount, n.Valid = 0, false
+		return nil
+	}
+	n.Valid = true
+	return n.Amount.Scan(src)
+}
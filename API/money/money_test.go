@@ -0,0 +1,140 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"0", 0},
+		{"12", 1200},
+		{"12.5", 1250},
+		{"12.50", 1250},
+		{" 15000.75 ", 1500075},
+		{"-12.34", -1234},
+		{"-0.05", -5},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", "abc", "1,5", "12.3.4", "99999999999999999999999",
+		"1/3", "1e3", "+5", ".5", "5.", "0x10", "0.005", "-1.995",
+	} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidAmount", in, err)
+		}
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	jpy, _ := LookupCurrency("JPY")
+	if got, err := ParseIn("1500", jpy); err != nil || got != New(1500, 0) {
+		t.Errorf("ParseIn(1500, JPY) = %s, %v", got, err)
+	}
+	if _, err := ParseIn("1500.5", jpy); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("ParseIn(1500.5, JPY) error = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestScanRoundsExtraDigits(t *testing.T) {
+	var a Amount
+	if err := a.Scan([]byte("1.995")); err != nil || a != 200 {
+		t.Errorf("Scan(1.995) = %s, %v, want 2.00", a, err)
+	}
+}
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		unit Amount
+		mode string
+		want Amount
+	}{
+		{New(1049, 0), New(100, 0), "nearest", New(1000, 0)},
+		{New(1050, 0), New(100, 0), "nearest", New(1100, 0)},
+		{New(1001, 0), New(100, 0), "up", New(1100, 0)},
+		{New(1099, 0), New(100, 0), "down", New(1000, 0)},
+		{New(1000, 0), New(100, 0), "up", New(1000, 0)},
+		{-New(1049, 0), New(100, 0), "nearest", -New(1000, 0)},
+		{-New(1050, 0), New(100, 0), "nearest", -New(1100, 0)},
+		{-New(1001, 0), New(100, 0), "up", -New(1000, 0)},
+		{-New(1001, 0), New(100, 0), "down", -New(1100, 0)},
+		{New(12, 34), 1, "nearest", New(12, 34)},
+		{New(12, 34), 0, "up", New(12, 34)},
+		{New(12, 50), Scale, "nearest", New(13, 0)},
+	}
+	for _, tt := range tests {
+		if got := tt.a.RoundTo(tt.unit, tt.mode); got != tt.want {
+			t.Errorf("%s.RoundTo(%s, %s) = %s, want %s", tt.a, tt.unit, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   Amount
+		weights []Amount
+		want    []Amount
+	}{
+		{"rata", 100, []Amount{1, 1, 1}, []Amount{34, 33, 33}},
+		{"rata 7 bagian", 1000, []Amount{1, 1, 1, 1, 1, 1, 1}, []Amount{143, 143, 143, 143, 143, 143, 142}},
+		{"bobot", 1000, []Amount{1, 2, 3}, []Amount{167, 333, 500}},
+		{"sisa ke pecahan terbesar", 100, []Amount{1, 2}, []Amount{33, 67}},
+		{"bobot nol dibagi rata", 5, []Amount{0, 0}, []Amount{3, 2}},
+		{"negatif", -100, []Amount{1, 1, 1}, []Amount{-34, -33, -33}},
+		{"total nol", 0, []Amount{2, 5}, []Amount{0, 0}},
+		{"tanpa bagian", 100, nil, []Amount{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Allocate(tt.total, tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Allocate = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAllocateSumsToTotal(t *testing.T) {
+	totals := []Amount{1, 7, 99, 100, 12345, 9999999, -1, -12345}
+	weightSets := [][]Amount{
+		{1, 1},
+		{1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1},
+		{15000, 27500, 3333},
+		{1, 0, 2},
+		{0, 0, 0},
+	}
+	for _, total := range totals {
+		for _, weights := range weightSets {
+			var sum Amount
+			for _, share := range Allocate(total, weights) {
+				sum += share
+			}
+			if sum != total {
+				t.Errorf("sum(Allocate(%s, %v)) = %s", total, weights, sum)
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package pms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"pos-restaurant/money"
+	"sync"
+	"time"
+)
+
+// FileClient adalah PMS tiruan untuk pengujian lokal: daftar tamu dibaca dari file JSON
+// (dibaca ulang setiap lookup sehingga bisa diedit saat server jalan) dan setiap posting
+// ditulis ke jurnal JSON lines. Saldo folio = balance di file tamu + seluruh posting di jurnal.
+type FileClient struct {
+	mu           sync.Mutex
+	guestsFile   string
+	postingsFile string
+	folio        map[string]money.Amount // guest_id -> total posting
+	charges      map[string]filePosting  // posting charge, Amount = nominal yang belum di-reverse
+	seq          int
+}
+
+type filePosting struct {
+	ID          string       `json:"id"`
+	Type        string       `json:"type"` // charge | reversal
+	Room        string       `json:"room"`
+	GuestID     string       `json:"guest_id"`
+	Amount      money.Amount `json:"amount"`
+	Reference   string       `json:"reference"`
+	Description string       `json:"description,omitempty"`
+	ReversalOf  string       `json:"reversal_of,omitempty"`
+	PostedAt    time.Time    `json:"posted_at"`
+}
+
+func NewFileClient(guestsFile, postingsFile string) (*FileClient, error) {
+	if guestsFile == "" {
+		return nil, errors.New("file tamu PMS wajib diisi")
+	}
+	if postingsFile == "" {
+		postingsFile = guestsFile + ".postings.jsonl"
+	}
+	c := &FileClient{
+		guestsFile:   guestsFile,
+		postingsFile: postingsFile,
+		folio:        map[string]money.Amount{},
+		charges:      map[string]filePosting{},
+	}
+
+	// Pulihkan saldo folio dari jurnal yang sudah ada
+	f, err := os.Open(postingsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p filePosting
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("jurnal PMS rusak: %w", err)
+		}
+		c.apply(p)
+	}
+	return c, scanner.Err()
+}
+
+func (c *FileClient) apply(p filePosting) {
+	c.seq++
+	if p.Type == "reversal" {
+		charge := c.charges[p.ReversalOf]
+		charge.Amount -= p.Amount
+		c.charges[p.ReversalOf] = charge
+		c.folio[charge.GuestID] -= p.Amount
+		return
+	}
+	c.folio[p.GuestID] += p.Amount
+	c.charges[p.ID] = p
+}
+
+func (c *FileClient) LookupGuest(ctx context.Context, room string) (*Guest, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(room)
+}
+
+func (c *FileClient) lookup(room string) (*Guest, error) {
+	data, err := os.ReadFile(c.guestsFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	var guests []Guest
+	if err := json.Unmarshal(data, &guests); err != nil {
+		return nil, fmt.Errorf("%w: file tamu tidak valid: %v", ErrUnavailable, err)
+	}
+
+	room = NormalizeRoom(room)
+	for _, g := range guests {
+		if NormalizeRoom(g.Room) == room {
+			g.Balance += c.folio[g.GuestID]
+			return &g, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: kamar %s", ErrGuestNotFound, room)
+}
+
+func (c *FileClient) PostCharge(ctx context.Context, charge Charge) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	guest, err := c.lookup(charge.Room)
+	if err != nil {
+		return "", err
+	}
+	if charge.GuestID != "" && charge.GuestID != guest.GuestID {
+		return "", fmt.Errorf("%w: tamu kamar %s sudah berganti", ErrRejected, guest.Room)
+	}
+	if err := guest.Authorize(charge.Amount); err != nil {
+		return "", err
+	}
+
+	return c.write(filePosting{
+		Type:        "charge",
+		Room:        guest.Room,
+		GuestID:     guest.GuestID,
+		Amount:      charge.Amount,
+		Reference:   charge.Reference,
+		Description: charge.Description,
+	})
+}
+
+func (c *FileClient) ReverseCharge(ctx context.Context, reversal Reversal) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	charge, ok := c.charges[reversal.PostingID]
+	if !ok {
+		return "", fmt.Errorf("%w: posting %s tidak ditemukan", ErrRejected, reversal.PostingID)
+	}
+	if reversal.Amount <= 0 || reversal.Amount > charge.Amount {
+		return "", fmt.Errorf("%w: sisa posting %s yang bisa di-reverse %s", ErrRejected, reversal.PostingID, charge.Amount)
+	}
+
+	return c.write(filePosting{
+		Type:        "reversal",
+		Room:        charge.Room,
+		GuestID:     charge.GuestID,
+		Amount:      reversal.Amount,
+		Reference:   reversal.Reference,
+		Description: reversal.Reason,
+		ReversalOf:  reversal.PostingID,
+	})
+}
+
+func (c *FileClient) write(p filePosting) (string, error) {
+	p.ID = fmt.Sprintf("FILE-%06d", c.seq+1)
+	p.PostedAt = time.Now()
+	line, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(c.postingsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	c.apply(p)
+	return p.ID, nil
+}
@@ -0,0 +1,125 @@
+package pms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPClient berbicara dengan PMS (atau stub-nya) lewat REST JSON:
+//
+//	GET  {base}/rooms/{room}/guest              -> Guest
+//	POST {base}/charges                         Charge   -> {"posting_id": "..."}
+//	POST {base}/charges/{posting_id}/reversals  Reversal -> {"posting_id": "..."}
+//
+// Penolakan dikembalikan dengan status 404 (tamu tidak ada) atau 409/422 dengan body
+// {"code": "not_in_house|credit_limit|rejected", "error": "..."}.
+type HTTPClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func NewHTTPClient(baseURL, apiKey string) (*HTTPClient, error) {
+	if baseURL == "" {
+		return nil, errors.New("URL PMS wajib diisi")
+	}
+	return &HTTPClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (c *HTTPClient) LookupGuest(ctx context.Context, room string) (*Guest, error) {
+	var g Guest
+	if err := c.do(ctx, http.MethodGet, "/rooms/"+url.PathEscape(NormalizeRoom(room))+"/guest", nil, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (c *HTTPClient) PostCharge(ctx context.Context, charge Charge) (string, error) {
+	var res struct {
+		PostingID string `json:"posting_id"`
+	}
+	charge.Room = NormalizeRoom(charge.Room)
+	if err := c.do(ctx, http.MethodPost, "/charges", charge, &res); err != nil {
+		return "", err
+	}
+	return res.PostingID, nil
+}
+
+func (c *HTTPClient) ReverseCharge(ctx context.Context, reversal Reversal) (string, error) {
+	var res struct {
+		PostingID string `json:"posting_id"`
+	}
+	path := "/charges/" + url.PathEscape(reversal.PostingID) + "/reversals"
+	if err := c.do(ctx, http.MethodPost, path, reversal, &res); err != nil {
+		return "", err
+	}
+	return res.PostingID, nil
+}
+
+func (c *HTTPClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("%w: respons tidak valid: %v", ErrUnavailable, err)
+		}
+		return nil
+	}
+
+	var failure struct {
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrGuestNotFound, failure.Error)
+	case http.StatusConflict, http.StatusUnprocessableEntity:
+		switch failure.Code {
+		case "not_in_house":
+			return fmt.Errorf("%w: %s", ErrNotInHouse, failure.Error)
+		case "credit_limit":
+			return fmt.Errorf("%w: %s", ErrCreditLimit, failure.Error)
+		}
+		return fmt.Errorf("%w: %s", ErrRejected, failure.Error)
+	}
+	return fmt.Errorf("%w: status %d %s", ErrUnavailable, resp.StatusCode, failure.Error)
+}
@@ -0,0 +1,108 @@
+// Package pms adalah adapter ke Property Management System hotel untuk pembayaran room charge.
+// Implementasi dipilih lewat konfigurasi: file (stub lokal) atau http.
+package pms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pos-restaurant/money"
+	"strings"
+)
+
+var (
+	ErrGuestNotFound = errors.New("tamu tidak ditemukan di PMS")
+	ErrNotInHouse    = errors.New("tamu tidak sedang menginap (belum check-in / sudah check-out)")
+	ErrCreditLimit   = errors.New("room charge melebihi batas kredit kamar")
+	ErrRejected      = errors.New("posting ditolak PMS")
+	ErrUnavailable   = errors.New("PMS tidak bisa dihubungi")
+)
+
+// Client adalah kontrak yang harus dipenuhi setiap integrasi PMS
+type Client interface {
+	// LookupGuest mencari tamu yang terdaftar di kamar, ErrGuestNotFound jika kamar kosong
+	LookupGuest(ctx context.Context, room string) (*Guest, error)
+	// PostCharge membebankan tagihan ke folio tamu, mengembalikan nomor posting PMS
+	PostCharge(ctx context.Context, charge Charge) (string, error)
+	// ReverseCharge mengkredit balik sebagian/seluruh posting sebelumnya, mengembalikan nomor posting kredit
+	ReverseCharge(ctx context.Context, reversal Reversal) (string, error)
+}
+
+type Guest struct {
+	Room        string       `json:"room"`
+	GuestID     string       `json:"guest_id"`
+	Name        string       `json:"name"`
+	InHouse     bool         `json:"in_house"`
+	CreditLimit money.Amount `json:"credit_limit"` // 0 = tanpa batas
+	Balance     money.Amount `json:"balance"`      // saldo folio saat ini
+}
+
+// Authorize memastikan tamu masih menginap dan sisa batas kreditnya cukup untuk amount
+func (g *Guest) Authorize(amount money.Amount) error {
+	if !g.InHouse {
+		return fmt.Errorf("%w: kamar %s", ErrNotInHouse, g.Room)
+	}
+	if g.CreditLimit > 0 && g.Balance+amount > g.CreditLimit {
+		return fmt.Errorf("%w: sisa kredit %s", ErrCreditLimit, g.CreditLimit-g.Balance)
+	}
+	return nil
+}
+
+type Charge struct {
+	Room        string       `json:"room"`
+	GuestID     string       `json:"guest_id"`
+	Amount      money.Amount `json:"amount"`
+	Reference   string       `json:"reference"` // nomor bill POS
+	Description string       `json:"description"`
+}
+
+type Reversal struct {
+	PostingID string       `json:"posting_id"`
+	Room      string       `json:"room"`
+	GuestID   string       `json:"guest_id"`
+	Amount    money.Amount `json:"amount"`
+	Reference string       `json:"reference"`
+	Reason    string       `json:"reason"`
+}
+
+// IsRejection bernilai true untuk penolakan bisnis dari PMS (bukan gangguan koneksi)
+func IsRejection(err error) bool {
+	return errors.Is(err, ErrGuestNotFound) || errors.Is(err, ErrNotInHouse) ||
+		errors.Is(err, ErrCreditLimit) || errors.Is(err, ErrRejected)
+}
+
+// NormalizeRoom menyeragamkan nomor kamar, contoh " room-401 " menjadi "401"
+func NormalizeRoom(room string) string {
+	room = strings.ToUpper(strings.TrimSpace(room))
+	return strings.TrimSpace(strings.TrimPrefix(room, "ROOM-"))
+}
+
+// Config memilih implementasi PMS, Driver kosong = integrasi PMS tidak aktif
+type Config struct {
+	Driver       string // file | http
+	GuestsFile   string // driver file: daftar tamu (JSON array Guest)
+	PostingsFile string // driver file: jurnal posting (JSON lines)
+	BaseURL      string // driver http
+	APIKey       string
+}
+
+// New membuat Client sesuai konfigurasi, mengembalikan nil jika PMS tidak diaktifkan
+func New(cfg Config) (Client, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case "file":
+		c, err := NewFileClient(cfg.GuestsFile, cfg.PostingsFile)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case "http":
+		c, err := NewHTTPClient(cfg.BaseURL, cfg.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("driver PMS %q tidak dikenal", cfg.Driver)
+}
@@ -0,0 +1,171 @@
+package pricing
+
+import (
+	"pos-restaurant/money"
+	"sort"
+)
+
+// Mode pembulatan total tagihan
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// Line adalah satu baris tagihan: qty * harga jual, dengan tarif pajak yang berlaku untuk kategorinya
+type Line struct {
+	Amount  money.Amount
+	TaxRate float64 // persen
+}
+
+// Rules adalah aturan pajak & service outlet yang berlaku untuk satu order
+type Rules struct {
+	ServiceChargePct   float64
+	ServiceExempt      bool // order_type dibebaskan dari service charge (takeaway, delivery, ...)
+	PricesIncludeTax   bool
+	TaxOnServiceCharge bool
+	RoundingUnit       money.Amount
+	RoundingMode       string
+	Currency           money.Currency
+}
+
+// Breakdown adalah rincian tagihan. Total = Subtotal - Discount + ServiceCharge + TaxAmount - TaxIncluded + Rounding
+type Breakdown struct {
+	Subtotal      money.Amount `json:"subtotal"`
+	Discount      money.Amount `json:"discount_amount"`
+	ServiceCharge money.Amount `json:"service_charge"`
+	TaxAmount     money.Amount `json:"tax_amount"`
+	TaxIncluded   money.Amount `json:"tax_included"` // bagian TaxAmount yang sudah termasuk di harga menu
+	Rounding      money.Amount `json:"rounding_amount"`
+	Total         money.Amount `json:"total_amount"`
+}
+
+// Calculate menghitung tagihan dengan satu aturan untuk bill penuh maupun split:
+// diskon dibagi proporsional ke tiap baris, service charge dari DPP (harga sebelum pajak),
+// pajak dihitung per tarif, lalu total dibulatkan sesuai aturan outlet.
+// Semua nominal dihitung dalam sen dan dibulatkan ke presisi mata uang.
+func Calculate(lines []Line, discount money.Amount, rules Rules) Breakdown {
+	var b Breakdown
+	amounts := make([]money.Amount, len(lines))
+	for i, l := range lines {
+		amounts[i] = l.Amount
+		b.Subtotal += l.Amount
+	}
+	b.Discount = min(max(discount, 0), b.Subtotal)
+
+	// Kelompokkan nilai setelah diskon per tarif pajak (basis poin)
+	gross := map[int64]money.Amount{}
+	for i, share := range money.Allocate(b.Discount, amounts) {
+		gross[money.BasisPoints(lines[i].TaxRate)] += lines[i].Amount - share
+	}
+	rates := make([]int64, 0, len(gross))
+	for bp := range gross {
+		rates = append(rates, bp)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i] < rates[j] })
+
+	bases := make([]money.Amount, len(rates))
+	var totalBase money.Amount
+	for i, bp := range rates {
+		bases[i] = gross[bp]
+		if rules.PricesIncludeTax {
+			included := (gross[bp] - gross[bp].MulDiv(10000, 10000+bp)).Round(rules.Currency)
+			bases[i] = gross[bp] - included
+			b.TaxIncluded += included
+			b.TaxAmount += included
+		} else {
+			b.TaxAmount += bases[i].MulDiv(bp, 10000).Round(rules.Currency)
+		}
+		totalBase += bases[i]
+	}
+
+	if !rules.ServiceExempt {
+		b.ServiceCharge = totalBase.Percent(rules.ServiceChargePct).Round(rules.Currency)
+	}
+	if rules.TaxOnServiceCharge && b.ServiceCharge != 0 {
+		for i, svc := range money.Allocate(b.ServiceCharge, bases) {
+			b.TaxAmount += svc.MulDiv(rates[i], 10000).Round(rules.Currency)
+		}
+	}
+
+	total := b.Subtotal - b.Discount + b.ServiceCharge + b.TaxAmount - b.TaxIncluded
+	b.Total = total.RoundTo(rules.RoundingUnit, rules.RoundingMode)
+	b.Rounding = b.Total - total
+	return b
+}
+
+// Reconcile menyamakan jumlah rincian beberapa split dengan rincian gabungannya (whole). Karena pajak,
+// service dan pembulatan dihitung per bill, selisih sen dibebankan ke split dengan total terbesar
+// sehingga total seluruh split selalu tepat sama dengan tagihan gabungan.
+func Reconcile(whole Breakdown, parts []Breakdown) {
+	if len(parts) == 0 {
+		return
+	}
+	largest := 0
+	for i, p := range parts {
+		if p.Total > parts[largest].Total {
+			largest = i
+		}
+	}
+	for i, p := range parts {
+		if i == largest {
+			continue
+		}
+		whole.ServiceCharge -= p.ServiceCharge
+		whole.TaxAmount -= p.TaxAmount
+		whole.TaxIncluded -= p.TaxIncluded
+		whole.Rounding -= p.Rounding
+	}
+
+	b := &parts[largest]
+	b.ServiceCharge = whole.ServiceCharge
+	b.TaxAmount = whole.TaxAmount
+	b.TaxIncluded = whole.TaxIncluded
+	b.Rounding = whole.Rounding
+	b.Total = b.Subtotal - b.Discount + b.ServiceCharge + b.TaxAmount - b.TaxIncluded + b.Rounding
+}
+
+// Divide membagi rata satu tagihan ke n bagian. Total dibagi lebih dulu dalam kelipatan unit pembulatan outlet
+// (atau nominal terkecil mata uang), sehingga total antar bagian berselisih maksimal satu unit. Komponen lain
+// dialokasikan sebanding total tiap bagian dan sisanya masuk Rounding, jumlah seluruh bagian tetap sama persis
+// dengan tagihan asal.
+func Divide(whole Breakdown, n int, rules Rules) []Breakdown {
+	unit := max(rules.RoundingUnit, rules.Currency.Unit(), 1)
+	steps, rest := whole.Total/unit, whole.Total%unit
+
+	totals := money.Allocate(steps, equalWeights(n))
+	for i := range totals {
+		totals[i] *= unit
+	}
+	// Total yang bukan kelipatan unit (aturan pembulatan berubah setelah tagihan dihitung) ke bagian terkecil
+	totals[n-1] += rest
+
+	subtotal := money.Allocate(whole.Subtotal, totals)
+	discount := money.Allocate(whole.Discount, totals)
+	service := money.Allocate(whole.ServiceCharge, totals)
+	tax := money.Allocate(whole.TaxAmount, totals)
+	included := money.Allocate(whole.TaxIncluded, totals)
+
+	parts := make([]Breakdown, n)
+	for i := range parts {
+		b := Breakdown{
+			Subtotal:      subtotal[i],
+			Discount:      discount[i],
+			ServiceCharge: service[i],
+			TaxAmount:     tax[i],
+			TaxIncluded:   included[i],
+			Total:         totals[i],
+		}
+		b.Rounding = b.Total - (b.Subtotal - b.Discount + b.ServiceCharge + b.TaxAmount - b.TaxIncluded)
+		parts[i] = b
+	}
+	return parts
+}
+
+func equalWeights(n int) []money.Amount {
+	weights := make([]money.Amount, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
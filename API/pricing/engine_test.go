@@ -0,0 +1,216 @@
+package pricing
+
+import (
+	"pos-restaurant/money"
+	"testing"
+)
+
+// Baris dengan nominal ganjil dan tarif pajak campuran
+var oddLines = []Line{
+	{Amount: money.New(33333, 33), TaxRate: 11},
+	{Amount: money.New(1, 1), TaxRate: 11},
+	{Amount: money.New(12345, 67), TaxRate: 10},
+	{Amount: money.New(999, 99), TaxRate: 0},
+	{Amount: money.New(47777, 77), TaxRate: 11},
+	{Amount: money.New(5, 55), TaxRate: 10},
+	{Amount: money.New(28571, 43), TaxRate: 11},
+	{Amount: money.New(0, 7), TaxRate: 12.5},
+	{Amount: money.New(14285, 71), TaxRate: 0},
+}
+
+var ruleSets = map[string]Rules{
+	"pajak & service": {
+		ServiceChargePct: 10, TaxOnServiceCharge: true,
+		Currency: money.DefaultCurrency,
+	},
+	"harga termasuk pajak": {
+		ServiceChargePct: 7.5, PricesIncludeTax: true, TaxOnServiceCharge: true,
+		Currency: money.DefaultCurrency,
+	},
+	"pembulatan 100 ke atas": {
+		ServiceChargePct: 5, RoundingUnit: money.New(100, 0), RoundingMode: RoundUp,
+		Currency: money.DefaultCurrency,
+	},
+	"pembulatan 500 terdekat, tanpa service": {
+		ServiceExempt: true, RoundingUnit: money.New(500, 0), RoundingMode: RoundNearest,
+		Currency: money.DefaultCurrency,
+	},
+	"mata uang tanpa desimal": {
+		ServiceChargePct: 10, TaxOnServiceCharge: true,
+		Currency: money.Currency{Code: "JPY", Decimals: 0},
+	},
+}
+
+var discounts = []money.Amount{0, money.New(1000, 1), money.New(7, 77)}
+
+func sumBreakdowns(parts []Breakdown) Breakdown {
+	var sum Breakdown
+	for _, p := range parts {
+		sum.Subtotal += p.Subtotal
+		sum.Discount += p.Discount
+		sum.ServiceCharge += p.ServiceCharge
+		sum.TaxAmount += p.TaxAmount
+		sum.TaxIncluded += p.TaxIncluded
+		sum.Rounding += p.Rounding
+		sum.Total += p.Total
+	}
+	return sum
+}
+
+func assertSumsToWhole(t *testing.T, whole Breakdown, parts []Breakdown) {
+	t.Helper()
+	if sum := sumBreakdowns(parts); sum != whole {
+		t.Errorf("jumlah split %+v, want %+v", sum, whole)
+	}
+	for i, p := range parts {
+		if total := p.Subtotal - p.Discount + p.ServiceCharge + p.TaxAmount - p.TaxIncluded + p.Rounding; total != p.Total {
+			t.Errorf("split %d: total %s tidak sama dengan rinciannya %s", i, p.Total, total)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []Line
+		discount money.Amount
+		rules    Rules
+		want     Breakdown
+	}{
+		{
+			name:  "service dan pajak atas service",
+			lines: []Line{{Amount: money.New(100000, 0), TaxRate: 11}},
+			rules: Rules{ServiceChargePct: 10, TaxOnServiceCharge: true, Currency: money.DefaultCurrency},
+			want: Breakdown{
+				Subtotal: money.New(100000, 0), ServiceCharge: money.New(10000, 0),
+				TaxAmount: money.New(12100, 0), Total: money.New(122100, 0),
+			},
+		},
+		{
+			name:     "diskon melebihi subtotal",
+			lines:    []Line{{Amount: money.New(500, 0), TaxRate: 10}},
+			discount: money.New(900, 0),
+			rules:    Rules{ServiceChargePct: 10, Currency: money.DefaultCurrency},
+			want:     Breakdown{Subtotal: money.New(500, 0), Discount: money.New(500, 0)},
+		},
+		{
+			name:  "harga termasuk pajak",
+			lines: []Line{{Amount: money.New(111000, 0), TaxRate: 11}},
+			rules: Rules{PricesIncludeTax: true, Currency: money.DefaultCurrency},
+			want: Breakdown{
+				Subtotal: money.New(111000, 0), TaxAmount: money.New(11000, 0),
+				TaxIncluded: money.New(11000, 0), Total: money.New(111000, 0),
+			},
+		},
+		{
+			name:  "pembulatan ke bawah",
+			lines: []Line{{Amount: money.New(10049, 50), TaxRate: 0}},
+			rules: Rules{RoundingUnit: money.New(100, 0), RoundingMode: RoundDown, Currency: money.DefaultCurrency},
+			want: Breakdown{
+				Subtotal: money.New(10049, 50), Rounding: -money.New(49, 50), Total: money.New(10000, 0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(tt.lines, tt.discount, tt.rules); got != tt.want {
+				t.Errorf("Calculate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivideSumsToWhole(t *testing.T) {
+	for name, rules := range ruleSets {
+		t.Run(name, func(t *testing.T) {
+			for _, discount := range discounts {
+				whole := Calculate(oddLines, discount, rules)
+				for _, n := range []int{2, 3, 7} {
+					parts := Divide(whole, n, rules)
+					if len(parts) != n {
+						t.Fatalf("Divide(%d) menghasilkan %d bagian", n, len(parts))
+					}
+					assertSumsToWhole(t, whole, parts)
+
+					// Bagian rata: total tiap bagian kelipatan unit pembulatan dan selisihnya maksimal satu unit
+					unit := max(rules.RoundingUnit, rules.Currency.Unit())
+					lo, hi := parts[0].Total, parts[0].Total
+					for _, p := range parts {
+						if whole.Total%unit == 0 && p.Total%unit != 0 {
+							t.Errorf("Divide(%d) total %s bukan kelipatan %s", n, p.Total, unit)
+						}
+						lo, hi = min(lo, p.Total), max(hi, p.Total)
+					}
+					if hi-lo > unit {
+						t.Errorf("Divide(%d) total tidak rata %s..%s, unit %s", n, lo, hi, unit)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileSumsToWhole(t *testing.T) {
+	for name, rules := range ruleSets {
+		t.Run(name, func(t *testing.T) {
+			for _, discount := range discounts {
+				whole := Calculate(oddLines, discount, rules)
+				for _, n := range []int{2, 3, 7} {
+					// Split per item: baris dibagi bergiliran ke n bill, diskon dialokasikan sesuai subtotal tiap bill
+					groups := make([][]Line, n)
+					subtotals := make([]money.Amount, n)
+					for i, l := range oddLines {
+						groups[i%n] = append(groups[i%n], l)
+						subtotals[i%n] += l.Amount
+					}
+					shares := money.Allocate(whole.Discount, subtotals)
+
+					parts := make([]Breakdown, n)
+					for i := range parts {
+						parts[i] = Calculate(groups[i], shares[i], rules)
+					}
+					Reconcile(whole, parts)
+					assertSumsToWhole(t, whole, parts)
+				}
+			}
+		})
+	}
+}
+
+func TestDivideEven(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []Line
+		n     int
+		rules Rules
+		want  []money.Amount
+	}{
+		{
+			name:  "sisa sen ke bagian pertama",
+			lines: []Line{{Amount: money.New(100, 0)}},
+			n:     3,
+			rules: Rules{Currency: money.DefaultCurrency},
+			want:  []money.Amount{money.New(33, 34), money.New(33, 33), money.New(33, 33)},
+		},
+		{
+			name:  "kelipatan unit pembulatan",
+			lines: []Line{{Amount: money.New(100000, 0), TaxRate: 11}},
+			n:     3,
+			rules: Rules{ServiceChargePct: 10, TaxOnServiceCharge: true, RoundingUnit: money.New(500, 0),
+				RoundingMode: RoundNearest, Currency: money.DefaultCurrency},
+			want: []money.Amount{money.New(41000, 0), money.New(40500, 0), money.New(40500, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whole := Calculate(tt.lines, 0, tt.rules)
+			parts := Divide(whole, tt.n, tt.rules)
+			for i, p := range parts {
+				if p.Total != tt.want[i] {
+					t.Errorf("bagian %d total %s, want %s", i, p.Total, tt.want[i])
+				}
+			}
+			assertSumsToWhole(t, whole, parts)
+		})
+	}
+}
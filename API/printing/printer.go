@@ -0,0 +1,107 @@
+// Package printing mengirim byte stream (ESC/POS) ke printer fisik lewat spooler dengan retry.
+// Printer didaftarkan dengan nama lewat konfigurasi, contoh:
+//
+//	POS_PRINTERS="dapur=tcp://192.168.1.50:9100,bar=file:/dev/usb/lp0"
+package printing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrUnknownPrinter = errors.New("printer tidak dikonfigurasi")
+
+// Printer adalah tujuan cetak, Print harus mengirim seluruh data atau mengembalikan error
+type Printer interface {
+	Print(ctx context.Context, data []byte) error
+}
+
+// NetworkPrinter mengirim data mentah ke port RAW printer jaringan (umumnya 9100)
+type NetworkPrinter struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+func (p *NetworkPrinter) Print(ctx context.Context, data []byte) error {
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return fmt.Errorf("gagal terhubung ke printer %s: %w", p.Addr, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	if _, err := conn.Write(data); err != nil {
+		return fmt.Errorf("gagal mengirim ke printer %s: %w", p.Addr, err)
+	}
+	return nil
+}
+
+// FilePrinter menulis ke device printer lokal (/dev/usb/lp0) atau file untuk uji coba
+type FilePrinter struct {
+	Path string
+}
+
+func (p *FilePrinter) Print(ctx context.Context, data []byte) error {
+	f, err := os.OpenFile(p.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Parse membuat printer dari URL tcp://host:port atau file:/path
+func Parse(spec string) (Printer, error) {
+	switch {
+	case strings.HasPrefix(spec, "tcp://"):
+		addr := strings.TrimPrefix(spec, "tcp://")
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "9100")
+		}
+		return &NetworkPrinter{Addr: addr}, nil
+	case strings.HasPrefix(spec, "file:"):
+		path := strings.TrimPrefix(spec, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("path printer file kosong")
+		}
+		return &FilePrinter{Path: path}, nil
+	}
+	return nil, fmt.Errorf("tujuan printer %q tidak dikenal, gunakan tcp:// atau file:", spec)
+}
+
+// ParsePrinters membaca daftar "nama=tujuan" yang dipisah koma. String kosong berarti tanpa printer.
+func ParsePrinters(config string) (map[string]Printer, error) {
+	printers := map[string]Printer{}
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("konfigurasi printer %q harus berbentuk nama=tujuan", entry)
+		}
+		p, err := Parse(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, fmt.Errorf("printer %s: %w", name, err)
+		}
+		printers[name] = p
+	}
+	return printers, nil
+}
@@ -0,0 +1,102 @@
+package printing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Job adalah satu antrian cetak yang sudah diklaim spooler. Payload nil berarti tidak ada yang perlu dicetak.
+type Job struct {
+	ID      int
+	Printer string
+	Payload []byte
+	Attempt int // percobaan ke-n, dimulai dari 1
+}
+
+// Queue menyimpan job secara persisten sehingga tidak hilang saat server restart
+type Queue interface {
+	// Claim mengambil job yang sudah waktunya dicetak dan menandainya sedang diproses
+	Claim(ctx context.Context, limit int) ([]Job, error)
+	// Done menandai job berhasil dicetak
+	Done(ctx context.Context, jobID int) error
+	// Fail mencatat kegagalan dan menjadwalkan ulang job retryIn lagi; final = true berarti job tidak dicoba lagi
+	Fail(ctx context.Context, jobID int, reason string, retryIn time.Duration, final bool) error
+}
+
+// MaxAttempts adalah batas percobaan sebelum job ditandai failed dan perlu retry manual
+const MaxAttempts = 5
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya: 5 detik, 10, 20, ... maksimal 5 menit
+func Backoff(attempt int) time.Duration {
+	d := 5 * time.Second
+	for i := 1; i < attempt && d < 5*time.Minute; i++ {
+		d *= 2
+	}
+	return min(d, 5*time.Minute)
+}
+
+type Spooler struct {
+	queue    Queue
+	printers map[string]Printer
+}
+
+func NewSpooler(queue Queue, printers map[string]Printer) *Spooler {
+	return &Spooler{queue: queue, printers: printers}
+}
+
+// Start memproses antrian setiap interval sampai ctx selesai
+func (s *Spooler) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.RunOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce mengirim semua job yang siap ke printernya masing-masing
+func (s *Spooler) RunOnce(ctx context.Context) {
+	jobs, err := s.queue.Claim(ctx, 20)
+	if err != nil {
+		log.Printf("Gagal mengambil antrian cetak: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if err := s.print(ctx, job); err != nil {
+			final := job.Attempt >= MaxAttempts
+			if _, ok := s.printers[job.Printer]; !ok {
+				final = true // konfigurasi tidak berubah tanpa restart, retry tidak ada gunanya
+			}
+			log.Printf("Cetak job %d ke %s gagal (percobaan %d): %v", job.ID, job.Printer, job.Attempt, err)
+			if err := s.queue.Fail(ctx, job.ID, err.Error(), Backoff(job.Attempt), final); err != nil {
+				log.Printf("Gagal mencatat kegagalan job cetak %d: %v", job.ID, err)
+			}
+			continue
+		}
+		if err := s.queue.Done(ctx, job.ID); err != nil {
+			log.Printf("Gagal menandai job cetak %d selesai: %v", job.ID, err)
+		}
+	}
+}
+
+func (s *Spooler) print(ctx context.Context, job Job) error {
+	if job.Payload == nil {
+		return nil
+	}
+	p, ok := s.printers[job.Printer]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPrinter, job.Printer)
+	}
+	return p.Print(ctx, job.Payload)
+}
@@ -0,0 +1,105 @@
+package printing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{5, 80 * time.Second},
+		{7, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+type failure struct {
+	jobID   int
+	retryIn time.Duration
+	final   bool
+}
+
+// fakeQueue mencatat hasil setiap job tanpa database
+type fakeQueue struct {
+	jobs     []Job
+	done     []int
+	failures []failure
+}
+
+func (q *fakeQueue) Claim(ctx context.Context, limit int) ([]Job, error) {
+	jobs := q.jobs
+	q.jobs = nil
+	return jobs, nil
+}
+
+func (q *fakeQueue) Done(ctx context.Context, jobID int) error {
+	q.done = append(q.done, jobID)
+	return nil
+}
+
+func (q *fakeQueue) Fail(ctx context.Context, jobID int, reason string, retryIn time.Duration, final bool) error {
+	q.failures = append(q.failures, failure{jobID, retryIn, final})
+	return nil
+}
+
+type fakePrinter struct {
+	err     error
+	printed int
+}
+
+func (p *fakePrinter) Print(ctx context.Context, data []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.printed++
+	return nil
+}
+
+func TestSpoolerRunOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		job         Job
+		printerErr  error
+		wantDone    bool
+		wantFailure failure
+		wantPrinted int
+	}{
+		{"berhasil dicetak", Job{ID: 1, Printer: "dapur", Payload: []byte("x"), Attempt: 1}, nil, true, failure{}, 1},
+		{"tanpa payload langsung selesai", Job{ID: 2, Printer: "dapur", Attempt: 1}, nil, true, failure{}, 0},
+		{"gagal dijadwalkan ulang", Job{ID: 3, Printer: "dapur", Payload: []byte("x"), Attempt: 2},
+			errors.New("offline"), false, failure{3, 10 * time.Second, false}, 0},
+		{"percobaan terakhir menyerah", Job{ID: 4, Printer: "dapur", Payload: []byte("x"), Attempt: MaxAttempts},
+			errors.New("offline"), false, failure{4, Backoff(MaxAttempts), true}, 0},
+		{"printer tidak dikonfigurasi menyerah", Job{ID: 5, Printer: "bar", Payload: []byte("x"), Attempt: 1},
+			nil, false, failure{5, 5 * time.Second, true}, 0},
+	}
+	for _, tt := range tests {
+		printer := &fakePrinter{err: tt.printerErr}
+		queue := &fakeQueue{jobs: []Job{tt.job}}
+		NewSpooler(queue, map[string]Printer{"dapur": printer}).RunOnce(context.Background())
+
+		if gotDone := len(queue.done) == 1; gotDone != tt.wantDone {
+			t.Errorf("%s: done = %v, want %v", tt.name, queue.done, tt.wantDone)
+		}
+		if !tt.wantDone && (len(queue.failures) != 1 || queue.failures[0] != tt.wantFailure) {
+			t.Errorf("%s: failures = %+v, want %+v", tt.name, queue.failures, tt.wantFailure)
+		}
+		if printer.printed != tt.wantPrinted {
+			t.Errorf("%s: printed = %d, want %d", tt.name, printer.printed, tt.wantPrinted)
+		}
+	}
+}
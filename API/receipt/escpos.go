@@ -0,0 +1,62 @@
+package receipt
+
+import "bytes"
+
+// Perintah ESC/POS yang didukung hampir semua printer thermal (Epson TM, Xprinter, dll)
+var (
+	escInit    = []byte{0x1B, 0x40}             // ESC @: reset printer
+	escCodePC  = []byte{0x1B, 0x74, 0x00}       // ESC t 0: code page PC437
+	escFeedCut = []byte{0x1D, 0x56, 0x42, 0x04} // GS V 66 n: feed n baris lalu potong sebagian
+)
+
+// escMode membentuk ESC ! n: bit 3 = emphasized (bold), bit 4 = tinggi ganda.
+// Tinggi ganda tidak mengubah lebar karakter sehingga tata letak kolom tetap.
+func escMode(style int) []byte {
+	var n byte
+	if style&styleBold != 0 {
+		n |= 0x08
+	}
+	if style&styleLarge != 0 {
+		n |= 0x10
+	}
+	return []byte{0x1B, 0x21, n}
+}
+
+// ESCPOS merender struk menjadi byte stream yang bisa langsung dikirim ke printer thermal
+func ESCPOS(r *Receipt, width int) []byte {
+	return encodeESCPOS(layout(r, width))
+}
+
+func encodeESCPOS(lines []line) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(escCodePC)
+
+	current := 0
+	for _, l := range lines {
+		if l.style != current {
+			buf.Write(escMode(l.style))
+			current = l.style
+		}
+		buf.WriteString(asciiOnly(l.text))
+		buf.WriteByte('\n')
+	}
+	if current != 0 {
+		buf.Write(escMode(0))
+	}
+	buf.Write(escFeedCut)
+	return buf.Bytes()
+}
+
+// asciiOnly mengganti karakter di luar ASCII dengan '?' agar tidak tercetak sebagai sampah di code page printer
+func asciiOnly(s string) string {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < 0x20 || r > 0x7E {
+			out = append(out, '?')
+			continue
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
@@ -0,0 +1,61 @@
+package receipt
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Bill {{.Number}}</title>
+<style>
+@page { size: 80mm auto; margin: 4mm; }
+body { margin: 0; }
+.receipt { font-family: "Courier New", Courier, monospace; font-size: 12px; line-height: 1.3; white-space: pre; width: {{.Width}}ch; }
+.b { font-weight: bold; }
+.l { transform: scaleY(1.5); margin: .3em 0; } /* tinggi ganda seperti ESC/POS, lebar kolom tetap */
+</style>
+</head>
+<body>
+<div class="receipt">
+{{- range .Lines}}
+<div{{if .Class}} class="{{.Class}}"{{end}}>{{.Text}}</div>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+type htmlLine struct {
+	Text  string
+	Class string
+}
+
+// HTML merender struk sebagai halaman siap cetak dari browser dengan tata letak yang sama seperti ESC/POS
+func HTML(r *Receipt, width int) ([]byte, error) {
+	lines := layout(r, width)
+	data := struct {
+		Number string
+		Width  int
+		Lines  []htmlLine
+	}{Number: r.BillNumber, Width: clampWidth(width)}
+
+	for _, l := range lines {
+		hl := htmlLine{Text: l.text}
+		switch {
+		case l.style&styleBold != 0 && l.style&styleLarge != 0:
+			hl.Class = "b l"
+		case l.style&styleBold != 0:
+			hl.Class = "b"
+		}
+		data.Lines = append(data.Lines, hl)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
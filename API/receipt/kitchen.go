@@ -0,0 +1,108 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KitchenTicket adalah tiket pesanan untuk satu station dapur/bar
+type KitchenTicket struct {
+	TicketID    int
+	StationName string
+	OrderNumber string
+	OrderType   string
+	TableNumber string
+	HotelRoom   string
+	Waiter      string
+	Course      string
+	FiredAt     time.Time
+	Reprint     bool
+	Items       []KitchenItem
+}
+
+type KitchenItem struct {
+	Name      string
+	Qty       float64
+	SeatNo    int    // 0 = hidangan bersama
+	Combo     string // nama paket jika item adalah komponen paket
+	Modifiers []string
+	Excluded  []string
+	Notes     string
+}
+
+// KitchenESCPOS merender tiket dapur. Nama menu dicetak besar dan tebal agar mudah dibaca dari jarak jauh,
+// tanpa harga.
+func KitchenESCPOS(t *KitchenTicket, width int) []byte {
+	return encodeESCPOS(kitchenLayout(t, width))
+}
+
+func kitchenLayout(t *KitchenTicket, width int) []line {
+	width = clampWidth(width)
+	var out []line
+	add := func(text string, style int) {
+		for _, l := range wrap(text, width) {
+			out = append(out, line{text: l, style: style})
+		}
+	}
+	center := func(text string, style int) {
+		for _, l := range wrap(text, width) {
+			out = append(out, line{text: pad(l, width, true), style: style})
+		}
+	}
+	rule := func() { out = append(out, line{text: strings.Repeat("-", width)}) }
+
+	station := t.StationName
+	if station == "" {
+		station = "DAPUR"
+	}
+	center(strings.ToUpper(station), styleBold|styleLarge)
+	if t.Reprint {
+		center("*** CETAK ULANG ***", styleBold)
+	}
+	switch {
+	case t.TableNumber != "":
+		add("MEJA "+t.TableNumber, styleBold|styleLarge)
+	case t.HotelRoom != "":
+		add("KAMAR "+t.HotelRoom, styleBold|styleLarge)
+	}
+	add(fmt.Sprintf("Order : %s (%s)", shortNumber(t.OrderNumber), t.OrderType), 0)
+	if t.Course != "" {
+		add("COURSE: "+strings.ToUpper(t.Course), styleBold)
+	}
+	if t.Waiter != "" {
+		add("Waiter: "+t.Waiter, 0)
+	}
+	add("Jam   : "+t.FiredAt.Format("02-01-2006 15:04"), 0)
+	rule()
+
+	for _, it := range t.Items {
+		add(formatQty(it.Qty)+" x "+it.Name, styleBold|styleLarge)
+		if it.Combo != "" {
+			add("   ["+it.Combo+"]", 0)
+		}
+		if it.SeatNo > 0 {
+			add(fmt.Sprintf("   Kursi %d", it.SeatNo), 0)
+		}
+		for _, m := range it.Modifiers {
+			add("   + "+m, 0)
+		}
+		for _, e := range it.Excluded {
+			add("   - TANPA "+strings.ToUpper(e), styleBold)
+		}
+		if it.Notes != "" {
+			add("   * "+it.Notes, styleBold)
+		}
+	}
+	rule()
+	center(fmt.Sprintf("Tiket #%d", t.TicketID), 0)
+	return out
+}
+
+// shortNumber memendekkan nomor order UUID menjadi 8 karakter pertama
+func shortNumber(n string) string {
+	if len(n) > 8 {
+		return n[:8]
+	}
+	return n
+}
@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Ukuran dalam point PDF. Courier memiliki lebar karakter 0.6 x ukuran font sehingga kolom tetap rata.
+const (
+	pdfFontSize   = 9.0
+	pdfLeading    = 11.0
+	pdfLargeSize  = 13.0
+	pdfLargeLead  = 16.0
+	pdfMargin     = 14.0
+	pdfCharFactor = 0.6
+)
+
+// PDF merender struk menjadi dokumen satu halaman selebar kertas struk, panjang halaman mengikuti isi.
+// Hanya memakai font standar Courier sehingga tidak perlu menyematkan file font.
+func PDF(r *Receipt, width int) []byte {
+	width = clampWidth(width)
+	lines := layout(r, width)
+
+	height := 2 * pdfMargin
+	for _, l := range lines {
+		height += lineLead(l)
+	}
+	pageW := float64(width)*pdfFontSize*pdfCharFactor + 2*pdfMargin
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n%.2f %.2f Td\n", pdfMargin, height-pdfMargin)
+	current := -1
+	for _, l := range lines {
+		if l.style != current {
+			font := "F1"
+			if l.style&styleBold != 0 {
+				font = "F2"
+			}
+			if l.style&styleLarge != 0 {
+				// Font lebih besar dipersempit horizontal agar lebar kolom sama seperti tinggi ganda di ESC/POS
+				fmt.Fprintf(&content, "/%s %.1f Tf %.2f Tz\n", font, pdfLargeSize, 100*pdfFontSize/pdfLargeSize)
+			} else {
+				fmt.Fprintf(&content, "/%s %.1f Tf 100 Tz\n", font, pdfFontSize)
+			}
+			current = l.style
+		}
+		fmt.Fprintf(&content, "0 %.2f Td (%s) Tj\n", -lineLead(l), pdfEscape(asciiOnly(l.text)))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents 4 0 R "+
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> >>", pageW, height),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func lineLead(l line) float64 {
+	if l.style&styleLarge != 0 {
+		return pdfLargeLead
+	}
+	return pdfLeading
+}
+
+var pdfEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+
+func pdfEscape(s string) string {
+	return pdfEscaper.Replace(s)
+}
@@ -0,0 +1,361 @@
+// Package receipt menyusun struk tagihan dan tiket dapur lalu merendernya ke ESC/POS (printer thermal), HTML dan PDF.
+// Semua format struk memakai tata letak teks yang sama sehingga isinya selalu identik.
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"pos-restaurant/money"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Lebar kertas dalam karakter: 32 untuk kertas 58mm, 42 untuk 80mm (font A)
+const (
+	DefaultWidth = 42
+	MinWidth     = 32
+	MaxWidth     = 64
+)
+
+const (
+	FormatESCPOS = "escpos"
+	FormatHTML   = "html"
+	FormatPDF    = "pdf"
+)
+
+var ErrUnknownFormat = errors.New("format struk tidak dikenal")
+
+// PreviewBanner dicetak di awal dan akhir pratinjau agar tidak bisa dipakai sebagai struk asli
+const PreviewBanner = "*** PREVIEW / BUKAN STRUK ***"
+
+// Receipt adalah snapshot tagihan yang siap dicetak
+type Receipt struct {
+	OutletName     string
+	OutletLocation string
+	Currency       money.Currency
+
+	BillID        int
+	BillNumber    string
+	BillStatus    string
+	SplitFromBill string // nomor bill induk untuk bill hasil split, kosong jika bukan split
+	OrderNumber   string
+	OrderType     string
+	TableNumber   string
+	HotelRoom     string
+	Waiter        string
+	Cashier       string // staf yang mencetak
+	PrintedAt     time.Time
+	Copy          int  // 0 = cetakan pertama, >0 = cetak ulang ke-n
+	Preview       bool // pratinjau yang tidak dicatat, selalu ditandai PreviewBanner
+	Items         []Item
+	Subtotal      money.Amount
+	Discount      money.Amount
+	ServiceCharge money.Amount
+	Tax           money.Amount
+	TaxIncluded   money.Amount
+	Rounding      money.Amount
+	Total         money.Amount
+	Paid          money.Amount
+	BalanceDue    money.Amount
+	Payments      []Payment
+	Refunds       []Refund
+}
+
+// Item adalah satu baris pesanan, paket ditampilkan dengan komponennya
+type Item struct {
+	Name       string
+	Qty        float64
+	Amount     money.Amount // nominal baris (harga x qty), termasuk modifier
+	Modifiers  []string
+	Excluded   []string // bahan yang diminta dihilangkan
+	Notes      string
+	Components []Item // isi paket, Amount komponen tidak dicetak
+}
+
+type Payment struct {
+	Method    string
+	Amount    money.Amount
+	Tendered  money.Amount
+	Tip       money.Amount
+	Change    money.Amount
+	Reference string
+}
+
+type Refund struct {
+	Method string
+	Amount money.Amount
+}
+
+// Render merender struk ke format yang diminta beserta Content-Type-nya
+func Render(r *Receipt, format string, width int) ([]byte, string, error) {
+	switch format {
+	case FormatESCPOS:
+		return ESCPOS(r, width), "application/octet-stream", nil
+	case FormatHTML:
+		b, err := HTML(r, width)
+		return b, "text/html; charset=utf-8", err
+	case FormatPDF:
+		return PDF(r, width), "application/pdf", nil
+	}
+	return nil, "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// Gaya cetak per baris, dipetakan ke perintah ESC/POS atau font bold di HTML/PDF
+const (
+	styleBold = 1 << iota
+	styleLarge
+)
+
+type line struct {
+	text  string
+	style int
+}
+
+// layout menyusun struk menjadi baris-baris teks selebar width karakter
+func layout(r *Receipt, width int) []line {
+	width = clampWidth(width)
+	var out []line
+	add := func(text string, style int) {
+		for _, t := range wrap(text, width) {
+			out = append(out, line{text: t, style: style})
+		}
+	}
+	center := func(text string, style int) {
+		for _, t := range wrap(text, width) {
+			out = append(out, line{text: pad(t, width, true), style: style})
+		}
+	}
+	rule := func() { out = append(out, line{text: strings.Repeat("-", width)}) }
+	amount := func(label string, a money.Amount, style int) {
+		out = append(out, line{text: columns(label, r.format(a), width), style: style})
+	}
+	// field mencetak "Label   : nilai", nilai panjang (contoh UUID) dipindah ke baris berikutnya
+	field := func(label, value string) {
+		text := fmt.Sprintf("%-8s: %s", label, value)
+		if utf8.RuneCountInString(text) > width {
+			add(fmt.Sprintf("%-8s:", label), 0)
+			add(value, 0)
+			return
+		}
+		add(text, 0)
+	}
+
+	center(r.OutletName, styleBold|styleLarge)
+	if r.OutletLocation != "" {
+		center(r.OutletLocation, 0)
+	}
+	if r.Preview {
+		center(PreviewBanner, styleBold)
+	}
+	if r.Copy > 0 {
+		center(fmt.Sprintf("*** CETAK ULANG #%d ***", r.Copy), styleBold)
+	}
+	switch r.BillStatus {
+	case "void":
+		center("*** BILL VOID ***", styleBold)
+	case "refunded":
+		center("*** REFUND ***", styleBold)
+	}
+	rule()
+
+	field("Bill", r.BillNumber)
+	if r.SplitFromBill != "" {
+		field("Split", "dari bill "+r.SplitFromBill)
+	}
+	field("Order", r.OrderNumber)
+	if r.TableNumber != "" {
+		field("Meja", r.TableNumber)
+	}
+	if r.HotelRoom != "" {
+		field("Kamar", r.HotelRoom)
+	}
+	field("Tipe", r.OrderType)
+	if r.Waiter != "" {
+		field("Waiter", r.Waiter)
+	}
+	if r.Cashier != "" {
+		field("Dicetak", r.Cashier)
+	}
+	field("Tanggal", r.PrintedAt.Format("02-01-2006 15:04"))
+	rule()
+
+	if len(r.Items) == 0 && r.SplitFromBill != "" {
+		center("Rincian item ada di bill "+r.SplitFromBill, 0)
+	}
+	for _, it := range r.Items {
+		// Nama item panjang dilipat, nominal tetap di baris pertama
+		value := r.format(it.Amount)
+		name := wrap(formatQty(it.Qty)+" x "+it.Name, width-utf8.RuneCountInString(value)-1)
+		out = append(out, line{text: columns(name[0], value, width)})
+		for _, rest := range name[1:] {
+			add("    "+rest, 0)
+		}
+		for _, c := range it.Components {
+			add("   "+formatQty(c.Qty)+" "+c.Name, 0)
+			itemDetails(add, c, "     ")
+		}
+		itemDetails(add, it, "   ")
+	}
+	rule()
+
+	amount("Subtotal", r.Subtotal, 0)
+	if r.Discount != 0 {
+		amount("Diskon", -r.Discount, 0)
+	}
+	if r.ServiceCharge != 0 {
+		amount("Service charge", r.ServiceCharge, 0)
+	}
+	if r.Tax != 0 {
+		if r.TaxIncluded == r.Tax {
+			amount("Pajak (termasuk harga)", r.Tax, 0)
+		} else {
+			amount("Pajak", r.Tax, 0)
+		}
+	}
+	if r.Rounding != 0 {
+		amount("Pembulatan", r.Rounding, 0)
+	}
+	amount("TOTAL "+r.Currency.Code, r.Total, styleBold|styleLarge)
+
+	if len(r.Payments) > 0 || len(r.Refunds) > 0 {
+		rule()
+	}
+	for _, p := range r.Payments {
+		label := paymentLabel(p.Method)
+		if p.Tendered > p.Amount {
+			amount(label, p.Tendered, 0)
+		} else {
+			amount(label, p.Amount, 0)
+		}
+		if p.Reference != "" {
+			add("   Ref: "+p.Reference, 0)
+		}
+		if p.Tip != 0 {
+			amount("   Tip", p.Tip, 0)
+		}
+		if p.Change != 0 {
+			amount("   Kembalian", p.Change, styleBold)
+		}
+	}
+	for _, rf := range r.Refunds {
+		amount("Refund "+paymentLabel(rf.Method), -rf.Amount, 0)
+	}
+	if len(r.Payments) > 0 {
+		amount("Dibayar", r.Paid, 0)
+	}
+	if r.BalanceDue > 0 {
+		amount("SISA TAGIHAN", r.BalanceDue, styleBold)
+	}
+
+	rule()
+	center("Terima kasih", 0)
+	if r.Preview {
+		center(PreviewBanner, styleBold)
+	}
+	return out
+}
+
+func itemDetails(add func(string, int), it Item, indent string) {
+	for _, m := range it.Modifiers {
+		add(indent+"+ "+m, 0)
+	}
+	for _, e := range it.Excluded {
+		add(indent+"- tanpa "+e, 0)
+	}
+	if it.Notes != "" {
+		add(indent+"* "+it.Notes, 0)
+	}
+}
+
+func paymentLabel(method string) string {
+	switch method {
+	case "cash":
+		return "Tunai"
+	case "credit_card":
+		return "Kartu kredit"
+	case "debit_card":
+		return "Kartu debit"
+	case "room_charge":
+		return "Room charge"
+	case "voucher":
+		return "Voucher"
+	}
+	return method
+}
+
+// format menampilkan nominal dengan pemisah ribuan sesuai presisi mata uang, contoh 125,000 atau 12.50
+func (r *Receipt) format(a money.Amount) string {
+	sign := ""
+	v := a.Cents()
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	units := fmt.Sprint(v / money.Scale)
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	if r.Currency.Decimals == 0 {
+		return sign + units
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, units, v%money.Scale)
+}
+
+func formatQty(q float64) string {
+	if q == float64(int64(q)) {
+		return fmt.Sprint(int64(q))
+	}
+	return strings.TrimRight(fmt.Sprintf("%.2f", q), "0")
+}
+
+func clampWidth(width int) int {
+	if width <= 0 {
+		return DefaultWidth
+	}
+	return min(max(width, MinWidth), MaxWidth)
+}
+
+// columns menaruh label di kiri dan nilai di kanan; label yang terlalu panjang dipotong
+func columns(label, value string, width int) string {
+	room := width - utf8.RuneCountInString(value) - 1
+	if n := utf8.RuneCountInString(label); n > room {
+		label = string([]rune(label)[:max(room, 0)])
+	}
+	return label + strings.Repeat(" ", width-utf8.RuneCountInString(label)-utf8.RuneCountInString(value)) + value
+}
+
+func pad(text string, width int, center bool) string {
+	n := utf8.RuneCountInString(text)
+	if n >= width {
+		return text
+	}
+	if center {
+		return strings.Repeat(" ", (width-n)/2) + text
+	}
+	return text
+}
+
+// wrap memecah teks menjadi beberapa baris selebar width, memotong di spasi bila memungkinkan
+func wrap(text string, width int) []string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return []string{text}
+	}
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	if len(runes) > 0 {
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
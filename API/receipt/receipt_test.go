@@ -0,0 +1,272 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"pos-restaurant/money"
+)
+
+func sampleReceipt() *Receipt {
+	return &Receipt{
+		OutletName:     "Resto Senja",
+		OutletLocation: "Jl. Pantai Indah No. 1 (Lobby)",
+		Currency:       money.DefaultCurrency,
+		BillNumber:     "0f8fad5b-d9cb-469f-a165-70867728950e",
+		OrderNumber:    "ORD-20260301-0001",
+		OrderType:      "dine_in",
+		TableNumber:    "A3",
+		Waiter:         "Dewi",
+		PrintedAt:      time.Date(2026, 3, 1, 19, 45, 0, 0, time.UTC),
+		Items: []Item{
+			{Name: "Nasi Goreng Kampung Spesial Telur Mata Sapi Ekstra Pedas", Qty: 2, Amount: money.New(90000, 0),
+				Modifiers: []string{"Level 5"}, Excluded: []string{"bawang"}, Notes: "tanpa kerupuk"},
+			{Name: "Paket Hemat", Qty: 1, Amount: money.New(55000, 0), Components: []Item{
+				{Name: "Ayam Bakar", Qty: 1},
+				{Name: "Es Teh Manis", Qty: 1, Modifiers: []string{"less sugar"}},
+			}},
+			{Name: "Crème brûlée", Qty: 0.5, Amount: money.New(17500, 0)},
+		},
+		Subtotal:      money.New(162500, 0),
+		Discount:      money.New(12500, 0),
+		ServiceCharge: money.New(15000, 0),
+		Tax:           money.New(18150, 0),
+		Rounding:      -money.New(150, 0),
+		Total:         money.New(183000, 0),
+		Paid:          money.New(183000, 0),
+		Payments: []Payment{
+			{Method: "cash", Amount: money.New(183000, 0), Tendered: money.New(200000, 0), Tip: money.New(5000, 0), Change: money.New(12000, 0)},
+		},
+	}
+}
+
+func layoutText(lines []line) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+func TestLayoutFitsWidth(t *testing.T) {
+	tests := []struct {
+		width int
+		want  int
+	}{
+		{0, DefaultWidth},
+		{MinWidth, MinWidth},
+		{10, MinWidth},
+		{DefaultWidth, DefaultWidth},
+		{200, MaxWidth},
+	}
+	for _, tt := range tests {
+		lines := layout(sampleReceipt(), tt.width)
+		for _, l := range lines {
+			if n := utf8.RuneCountInString(l.text); n > tt.want {
+				t.Errorf("width %d: baris %q selebar %d, maksimal %d", tt.width, l.text, n, tt.want)
+			}
+		}
+		if rule := strings.Repeat("-", tt.want); !strings.Contains(layoutText(lines), rule) {
+			t.Errorf("width %d: garis pemisah tidak selebar %d", tt.width, tt.want)
+		}
+	}
+}
+
+func TestLayoutContent(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Receipt)
+		want   []string
+		absent []string
+	}{
+		{
+			name: "struk lunas",
+			want: []string{
+				"Diskon                          -12,500.00",
+				"TOTAL IDR                       183,000.00",
+				"Tunai                           200,000.00",
+				"   Kembalian                     12,000.00",
+				"   + Level 5", "   - tanpa bawang", "   * tanpa kerupuk",
+				"   1 Es Teh Manis", "     + less sugar",
+				"0.5 x Cr",
+			},
+			absent: []string{"CETAK ULANG", "SISA TAGIHAN"},
+		},
+		{
+			name:   "cetak ulang bill void",
+			modify: func(r *Receipt) { r.Copy, r.BillStatus = 2, "void" },
+			want:   []string{"*** CETAK ULANG #2 ***", "*** BILL VOID ***"},
+		},
+		{
+			name: "sisa tagihan dan refund",
+			modify: func(r *Receipt) {
+				r.Payments = nil
+				r.Paid = 0
+				r.BalanceDue = money.New(183000, 0)
+				r.Refunds = []Refund{{Method: "debit_card", Amount: money.New(10000, 0)}}
+			},
+			want:   []string{"SISA TAGIHAN                    183,000.00", "Refund Kartu debit              -10,000.00"},
+			absent: []string{"Dibayar"},
+		},
+		{
+			name:   "mata uang tanpa desimal",
+			modify: func(r *Receipt) { r.Currency, _ = money.LookupCurrency("JPY") },
+			want:   []string{"TOTAL JPY                          183,000"},
+		},
+		{
+			name:   "bill split tanpa item",
+			modify: func(r *Receipt) { r.Items, r.SplitFromBill = nil, "B-001" },
+			want:   []string{"Split   : dari bill B-001", "Rincian item ada di bill B-001"},
+		},
+	}
+	for _, tt := range tests {
+		r := sampleReceipt()
+		if tt.modify != nil {
+			tt.modify(r)
+		}
+		text := layoutText(layout(r, DefaultWidth))
+		for _, s := range tt.want {
+			if !strings.Contains(text, s) {
+				t.Errorf("%s: struk tidak berisi %q\n%s", tt.name, s, text)
+			}
+		}
+		for _, s := range tt.absent {
+			if strings.Contains(text, s) {
+				t.Errorf("%s: struk tidak boleh berisi %q", tt.name, s)
+			}
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"pendek", 10, []string{"pendek"}},
+		{"nasi goreng kampung", 12, []string{"nasi goreng", "kampung"}},
+		{"abcdefghijklmnop", 6, []string{"abcdef", "ghijkl", "mnop"}},
+	}
+	for _, tt := range tests {
+		got := wrap(tt.text, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		label, value string
+		width        int
+		want         string
+	}{
+		{"Subtotal", "1,000.00", 20, "Subtotal    1,000.00"},
+		{"Label yang sangat panjang", "9.00", 12, "Label y 9.00"},
+	}
+	for _, tt := range tests {
+		if got := columns(tt.label, tt.value, tt.width); got != tt.want {
+			t.Errorf("columns(%q, %q, %d) = %q, want %q", tt.label, tt.value, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestESCPOS(t *testing.T) {
+	out := ESCPOS(sampleReceipt(), MinWidth)
+	if !bytes.HasPrefix(out, append(append([]byte{}, escInit...), escCodePC...)) {
+		t.Errorf("ESC/POS tidak diawali reset dan code page: % x", out[:8])
+	}
+	if !bytes.HasSuffix(out, escFeedCut) {
+		t.Errorf("ESC/POS tidak diakhiri feed & cut")
+	}
+	if !bytes.Contains(out, escMode(styleBold|styleLarge)) || !bytes.Contains(out, escMode(0)) {
+		t.Errorf("ESC/POS tidak mengganti gaya cetak untuk judul")
+	}
+	for _, b := range out {
+		if b >= 0x80 {
+			t.Fatalf("ESC/POS berisi byte non-ASCII 0x%x", b)
+		}
+	}
+	if !bytes.Contains(out, []byte("Cr?me br?l?e")) {
+		t.Errorf("karakter non-ASCII tidak diganti '?'")
+	}
+}
+
+func TestPDF(t *testing.T) {
+	out := PDF(sampleReceipt(), DefaultWidth)
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("header/trailer PDF tidak valid")
+	}
+	if !bytes.Contains(out, []byte(`(Lobby\)`)) {
+		t.Errorf("tanda kurung tidak di-escape")
+	}
+
+	// Setiap offset di tabel xref harus menunjuk tepat ke awal objeknya
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("startxref tidak ditemukan")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d tidak menunjuk tabel xref", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 6 {
+		t.Fatalf("xref berisi %d objek, want 6", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("offset objek %d (%d) tidak menunjuk %q", i+1, off, want)
+		}
+	}
+
+	// Panjang stream sesuai isi
+	loc := regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindSubmatchIndex(out)
+	length, _ := strconv.Atoi(string(out[loc[2]:loc[3]]))
+	if !bytes.HasPrefix(out[loc[1]+length:], []byte("endstream")) {
+		t.Errorf("/Length %d tidak sesuai panjang stream", length)
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, format := range []string{FormatESCPOS, FormatHTML, FormatPDF} {
+		if b, contentType, err := Render(sampleReceipt(), format, 0); err != nil || len(b) == 0 || contentType == "" {
+			t.Errorf("Render(%s) = %d byte, %q, %v", format, len(b), contentType, err)
+		}
+	}
+	if _, _, err := Render(sampleReceipt(), "txt", 0); err == nil {
+		t.Errorf("Render(txt) tanpa error")
+	}
+}
+
+func TestPreviewMarker(t *testing.T) {
+	for _, format := range []string{FormatESCPOS, FormatHTML, FormatPDF} {
+		for _, width := range []int{MinWidth, DefaultWidth, MaxWidth} {
+			r := sampleReceipt()
+			r.Preview = true
+			r.Copy = 2
+			b, _, err := Render(r, format, width)
+			if err != nil {
+				t.Fatalf("Render(%s, %d): %v", format, width, err)
+			}
+			// Penanda harus muncul utuh di awal dan akhir struk
+			if n := bytes.Count(b, []byte(PreviewBanner)); n != 2 {
+				t.Errorf("preview %s lebar %d memuat %d penanda, want 2", format, width, n)
+			}
+
+			r.Preview = false
+			b, _, _ = Render(r, format, width)
+			if bytes.Contains(b, []byte("BUKAN STRUK")) {
+				t.Errorf("cetakan %s lebar %d memuat penanda preview", format, width)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"pos-restaurant/money"
+	"pos-restaurant/receipt"
+
+	"github.com/lib/pq"
+)
+
+// LoadReceipt mengambil seluruh data yang dicetak di struk: outlet, order, item beserta modifier dan
+// bahan yang dihilangkan, rincian tagihan, pembayaran dan refund. Bill split tidak menyimpan item per
+// bagian sehingga struknya hanya memuat total dan merujuk ke bill induk.
+func (r *BillRepository) LoadReceipt(ctx context.Context, billID, staffID int) (*receipt.Receipt, error) {
+	var (
+		rc           receipt.Receipt
+		orderID      int
+		currencyCode string
+		location     sql.NullString
+		splitFrom    sql.NullString
+		tableNumber  sql.NullString
+		hotelRoom    sql.NullString
+		waiter       sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT b.id, b.bill_number, b.status, pb.bill_number,
+			b.subtotal, COALESCE(b.discount_amount, 0), b.service_charge, b.tax_amount, b.tax_included,
+			b.rounding_amount, b.total_amount, COALESCE(b.paid_amount, 0), COALESCE(b.balance_due, 0),
+			o.id, o.order_number, o.order_type, o.hotel_room, t.table_number, w.name,
+			ot.name, ot.location, ot.currency
+		FROM bills b
+		JOIN orders o ON o.id = b.order_id
+		JOIN outlets ot ON ot.id = o.outlet_id
+		LEFT JOIN bills pb ON pb.id = b.original_bill_id
+		LEFT JOIN tables t ON t.id = o.table_id
+		LEFT JOIN staff w ON w.id = o.waiter_id
+		WHERE b.id = $1
+	`, billID).Scan(&rc.BillID, &rc.BillNumber, &rc.BillStatus, &splitFrom,
+		&rc.Subtotal, &rc.Discount, &rc.ServiceCharge, &rc.Tax, &rc.TaxIncluded,
+		&rc.Rounding, &rc.Total, &rc.Paid, &rc.BalanceDue,
+		&orderID, &rc.OrderNumber, &rc.OrderType, &hotelRoom, &tableNumber, &waiter,
+		&rc.OutletName, &location, &currencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("gagal ambil bill %d untuk struk: %w", billID, err)
+	}
+	rc.OutletLocation = location.String
+	rc.SplitFromBill = splitFrom.String
+	rc.TableNumber = tableNumber.String
+	rc.HotelRoom = hotelRoom.String
+	rc.Waiter = waiter.String
+	currency, ok := money.LookupCurrency(currencyCode)
+	if !ok {
+		currency = money.DefaultCurrency
+	}
+	rc.Currency = currency
+
+	if staffID > 0 {
+		err := r.db.QueryRowContext(ctx, `SELECT name FROM staff WHERE id = $1`, staffID).Scan(&rc.Cashier)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+
+	if !splitFrom.Valid {
+		if rc.Items, err = loadReceiptItems(ctx, r.db, orderID); err != nil {
+			return nil, err
+		}
+	}
+	if rc.Payments, err = loadReceiptPayments(ctx, r.db, billID); err != nil {
+		return nil, err
+	}
+	if rc.Refunds, err = loadReceiptRefunds(ctx, r.db, billID); err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+// RecordPrint mencatat satu kali cetak struk dan mengembalikan nomor salinannya (0 = cetakan pertama)
+func (r *BillRepository) RecordPrint(ctx context.Context, billID int, format string, staffID int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Kunci bill agar dua cetak bersamaan tidak mendapat nomor salinan yang sama
+	var id int
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM bills WHERE id = $1 FOR UPDATE`, billID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("bill %d: %w", billID, err)
+	}
+
+	var copyNo int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO bill_prints (bill_id, format, copy_no, printed_by)
+		VALUES ($1, $2, (SELECT COUNT(*) FROM bill_prints WHERE bill_id = $1), $3)
+		RETURNING copy_no
+	`, billID, format, sql.NullInt64{Int64: int64(staffID), Valid: staffID > 0}).Scan(&copyNo)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return copyNo, nil
+}
+
+// CountPrints mengembalikan berapa kali struk bill sudah dicetak
+func (r *BillRepository) CountPrints(ctx context.Context, billID int) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bill_prints WHERE bill_id = $1`, billID).Scan(&n)
+	return n, err
+}
+
+// loadReceiptItems mengambil item aktif order; komponen paket dikelompokkan di bawah baris paketnya
+// dan nominal paket adalah jumlah nominal komponennya agar cocok dengan subtotal bill.
+func loadReceiptItems(ctx context.Context, q queryer, orderID int) ([]receipt.Item, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT mi.name, oi.qty, COALESCE(oi.override_price, oi.unit_price) + oi.modifiers_price, oi.notes,
+			oi.order_combo_id, oc.name, oc.qty,
+			ARRAY(SELECT m.name FROM order_item_modifiers m WHERE m.order_item_id = oi.id ORDER BY m.id),
+			ARRAY(SELECT i.name FROM order_item_ingredient_excluded e
+				JOIN ingredients i ON i.id = e.ingredient_id
+				WHERE e.order_item_id = oi.id ORDER BY e.id)
+		FROM order_items oi
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN order_combos oc ON oc.id = oi.order_combo_id
+		WHERE oi.order_id = $1 AND oi.voided_at IS NULL
+		ORDER BY oi.id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []receipt.Item
+	combos := map[int64]int{} // order_combo_id -> indeks baris paket di items
+	for rows.Next() {
+		var (
+			it        receipt.Item
+			price     money.Amount
+			notes     sql.NullString
+			comboID   sql.NullInt64
+			comboName sql.NullString
+			comboQty  sql.NullFloat64
+			modifiers pq.StringArray
+			excluded  pq.StringArray
+		)
+		err := rows.Scan(&it.Name, &it.Qty, &price, &notes, &comboID, &comboName, &comboQty, &modifiers, &excluded)
+		if err != nil {
+			return nil, err
+		}
+		it.Amount = price.MulQty(it.Qty)
+		it.Notes = notes.String
+		it.Modifiers = modifiers
+		it.Excluded = excluded
+
+		if !comboID.Valid {
+			items = append(items, it)
+			continue
+		}
+		idx, ok := combos[comboID.Int64]
+		if !ok {
+			idx = len(items)
+			combos[comboID.Int64] = idx
+			items = append(items, receipt.Item{Name: comboName.String, Qty: comboQty.Float64})
+		}
+		items[idx].Amount += it.Amount
+		items[idx].Components = append(items[idx].Components, it)
+	}
+	return items, rows.Err()
+}
+
+func loadReceiptPayments(ctx context.Context, q queryer, billID int) ([]receipt.Payment, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT payment_method, amount, tendered_amount, tip_amount, change_amount, reference_number
+		FROM bill_payments
+		WHERE bill_id = $1
+		ORDER BY payment_time, id
+	`, billID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []receipt.Payment
+	for rows.Next() {
+		var (
+			p   receipt.Payment
+			ref sql.NullString
+		)
+		if err := rows.Scan(&p.Method, &p.Amount, &p.Tendered, &p.Tip, &p.Change, &ref); err != nil {
+			return nil, err
+		}
+		p.Reference = ref.String
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func loadReceiptRefunds(ctx context.Context, q queryer, billID int) ([]receipt.Refund, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT refund_method, amount
+		FROM bill_refunds
+		WHERE bill_id = $1
+		ORDER BY refunded_at, id
+	`, billID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []receipt.Refund
+	for rows.Next() {
+		var f receipt.Refund
+		if err := rows.Scan(&f.Method, &f.Amount); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, f)
+	}
+	return refunds, rows.Err()
+}
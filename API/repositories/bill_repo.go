@@ -3,79 +3,58 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/pms"
+	"pos-restaurant/pricing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidSplit    = errors.New("split bill tidak valid")
+	ErrBillNotPayable  = errors.New("bill tidak bisa dibayar")
+	ErrOverpayment     = errors.New("nominal pembayaran tidak valid")
+	ErrInvalidRefund   = errors.New("refund tidak valid")
+	ErrBillNotVoidable = errors.New("bill tidak bisa di-void")
+	// ErrOrderNotBillable dipakai jika order sudah selesai/void/merged atau sudah punya bill aktif
+	ErrOrderNotBillable = errors.New("order tidak bisa ditagih")
 )
 
 type BillRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	pms pms.Client // nil = room charge hanya dicatat, tanpa posting ke PMS
 }
 
-func NewBillRepository(db *sql.DB) *BillRepository {
-	return &BillRepository{db: db}
+func NewBillRepository(db *sql.DB, pmsClient pms.Client) *BillRepository {
+	return &BillRepository{db: db, pms: pmsClient}
 }
 
-func (r *BillRepository) Create(ctx context.Context, orderID int, discount float64) (int, error) {
+// Create membuat satu bill untuk seluruh item order. Order harus masih open/transferred dan belum punya
+// bill aktif, bill lama perlu di-void atau di-split lebih dulu.
+func (r *BillRepository) Create(ctx context.Context, orderID int, discount money.Amount) (int, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback()
 
-	// 1. Ambil item dan outlet dari order
-	var outletID int
-	rows, err := tx.QueryContext(ctx, `
-		SELECT oi.qty, oi.unit_price, o.outlet_id
-		FROM order_items oi
-		JOIN orders o ON oi.order_id = o.id
-		WHERE o.id = $1
-	`, orderID)
-	if err != nil {
+	if err := lockBillableOrder(ctx, tx, orderID, 0); err != nil {
 		return 0, err
 	}
-	defer rows.Close()
-
-	var subtotal float64
-	for rows.Next() {
-		var qty, unitPrice float64
-		if err := rows.Scan(&qty, &unitPrice, &outletID); err != nil {
-			return 0, err
-		}
-		subtotal += qty * unitPrice
-	}
 
-	// 2. Ambil tax & service dari outlet
-	var taxPct, servicePct float64
-	err = tx.QueryRowContext(ctx, `
-		SELECT tax_percentage, service_charge_percentage
-		FROM outlets
-		WHERE id = $1
-	`, outletID).Scan(&taxPct, &servicePct)
+	b, err := priceOrder(ctx, tx, orderID, discount)
 	if err != nil {
 		return 0, err
 	}
 
-	serviceCharge := subtotal * servicePct / 100
-	taxAmount := (subtotal + serviceCharge) * taxPct / 100
-	total := subtotal + serviceCharge + taxAmount - discount
-	if total < 0 {
-		total = 0
-	}
-
-	// 3. Insert ke Bill
-	var billID int
-	billNumber := uuid.New().String()
-	err = tx.QueryRowContext(ctx, `
-		INSERT INTO bills (
-			bill_number, order_id, status, subtotal,
-			tax_amount, service_charge, discount_amount,
-			total_amount
-		) VALUES (
-			$1, $2, 'open', $3, $4, $5, $6, $7)
-		RETURNING id
-	`, billNumber, orderID, subtotal, taxAmount, serviceCharge, discount, total).Scan(&billID)
+	billID, err := insertBill(ctx, tx, orderID, sql.NullInt64{}, b)
 	if err != nil {
 		return 0, err
 	}
@@ -87,6 +66,13 @@ func (r *BillRepository) Create(ctx context.Context, orderID int, discount float
 	return billID, nil
 }
 
+// Preview menghitung tagihan order tanpa menyimpan bill
+func (r *BillRepository) Preview(ctx context.Context, orderID int, discount money.Amount) (pricing.Breakdown, error) {
+	return priceOrder(ctx, r.db, orderID, discount)
+}
+
+// CreateSplit memecah tagihan order sesuai mode split. Semua item aktif order harus habis terbagi,
+// setiap bagian dihitung dengan aturan pajak/service yang sama lalu diselaraskan dengan tagihan gabungannya.
 func (r *BillRepository) CreateSplit(ctx context.Context, req models.SplitBillRequest) ([]int, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -94,67 +80,103 @@ func (r *BillRepository) CreateSplit(ctx context.Context, req models.SplitBillRe
 	}
 	defer tx.Rollback()
 
-	// 🔒 Validasi item tidak duplikat antar split
-	usedItems := make(map[int]bool)
-	for _, split := range req.Splits {
-		for _, itemID := range split.ItemIDs {
-			if usedItems[itemID] {
-				return nil, fmt.Errorf("item ID %d digunakan lebih dari satu kali", itemID)
-			}
-			usedItems[itemID] = true
+	// Bill asal harus milik order ini, masih open dan belum ada pembayaran, sehingga tidak ada
+	// pembayaran yang tertinggal di bill yang di-split. Diskonnya ikut dibagi ke bill split.
+	var originalDiscount money.Amount
+	if req.OriginalBillID > 0 {
+		var (
+			orderID int
+			status  string
+			paid    money.Amount
+		)
+		err := tx.QueryRowContext(ctx, `
+			SELECT order_id, status, paid_amount + refunded_amount, discount_amount
+			FROM bills
+			WHERE id = $1
+			FOR UPDATE
+		`, req.OriginalBillID).Scan(&orderID, &status, &paid, &originalDiscount)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: bill %d tidak ditemukan", ErrInvalidSplit, req.OriginalBillID)
+		}
+		if err != nil {
+			return nil, err
 		}
+		switch {
+		case orderID != req.OriginalOrderID:
+			return nil, fmt.Errorf("%w: bill %d bukan milik order %d", ErrInvalidSplit, req.OriginalBillID, req.OriginalOrderID)
+		case status != "open":
+			return nil, fmt.Errorf("%w: bill %d berstatus %s", ErrInvalidSplit, req.OriginalBillID, status)
+		case paid != 0:
+			return nil, fmt.Errorf("%w: bill %d sudah ada pembayaran", ErrInvalidSplit, req.OriginalBillID)
+		}
+	}
+	if err := lockBillableOrder(ctx, tx, req.OriginalOrderID, req.OriginalBillID); err != nil {
+		return nil, err
 	}
 
-	var billIDs []int
+	rules, items, err := loadBillItems(ctx, tx, req.OriginalOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: order %d tidak punya item aktif", ErrInvalidSplit, req.OriginalOrderID)
+	}
+
+	var parts []pricing.Breakdown
+	if req.Mode == models.SplitEvenly {
+		if req.Guests < 2 {
+			return nil, fmt.Errorf("%w: jumlah tamu minimal 2", ErrInvalidSplit)
+		}
+		lines := make([]pricing.Line, len(items))
+		for i, item := range items {
+			lines[i] = item.line
+		}
+		discount := req.DiscountAmount
+		if discount == 0 {
+			discount = originalDiscount
+		}
+		parts = pricing.Divide(pricing.Calculate(lines, discount, rules), req.Guests, rules)
+	} else {
+		splitLines, err := buildSplitLines(req, items)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, split := range req.Splits {
-		var subtotal float64
-		for _, itemID := range split.ItemIDs {
-			var price float64
-			err := tx.QueryRowContext(ctx, `SELECT unit_price FROM order_items WHERE id = $1`, itemID).Scan(&price)
-			if err != nil {
-				return nil, fmt.Errorf("gagal ambil harga untuk item %d: %w", itemID, err)
+		// Tanpa diskon per split, diskon bill asal dibagi sesuai subtotal tiap split
+		discounts := make([]money.Amount, len(splitLines))
+		subtotals := make([]money.Amount, len(splitLines))
+		var requested money.Amount
+		for i, lines := range splitLines {
+			discounts[i] = req.Splits[i].DiscountAmount
+			requested += discounts[i]
+			for _, l := range lines {
+				subtotals[i] += l.Amount
 			}
-			subtotal += price
+		}
+		if requested == 0 && originalDiscount != 0 {
+			discounts = money.Allocate(originalDiscount, subtotals)
 		}
 
-		var outletID int
-		err = tx.QueryRowContext(ctx, `
-			SELECT o.outlet_id FROM orders o WHERE o.id = $1
-		`, req.OriginalOrderID).Scan(&outletID)
-		if err != nil {
-			return nil, fmt.Errorf("gagal ambil outlet_id dari order %d: %w", req.OriginalOrderID, err)
+		var (
+			allLines []pricing.Line
+			discount money.Amount
+		)
+		for i, lines := range splitLines {
+			b := pricing.Calculate(lines, discounts[i], rules)
+			parts = append(parts, b)
+			allLines = append(allLines, lines...)
+			discount += b.Discount
 		}
 
-		var taxPercent, svcPercent float64
-		err = tx.QueryRowContext(ctx, `SELECT tax_percentage, service_charge_percentage FROM outlets WHERE id = $1`, outletID).
-			Scan(&taxPercent, &svcPercent)
-		if err != nil {
-			return nil, fmt.Errorf("gagal ambil tax/outlet: %w", err)
-		}
-
-		taxAmount := subtotal * taxPercent / 100
-		svcAmount := subtotal * svcPercent / 100
-		total := subtotal + taxAmount + svcAmount - split.DiscountAmount
-
-		var billID int
-		billNumber := uuid.New().String()
-		err = tx.QueryRowContext(ctx, `
-			INSERT INTO bills (
-				bill_number, order_id, original_bill_id, status,
-				subtotal, tax_amount, service_charge, discount_amount,
-				total_amount
-			) VALUES (
-				$1, $2, $3, 'open', $4,
-				$5, $6, $7, $8
-			) RETURNING id
-		`,
-			billNumber,
-			req.OriginalOrderID,
-			sql.NullInt64{Int64: int64(req.OriginalBillID), Valid: req.OriginalBillID > 0},
-			subtotal, taxAmount, svcAmount,
-			split.DiscountAmount, total,
-		).Scan(&billID)
+		// Selisih pembulatan pajak/service antar split diselaraskan dengan tagihan gabungannya
+		pricing.Reconcile(pricing.Calculate(allLines, discount, rules), parts)
+	}
+
+	var billIDs []int
+	originalBillID := sql.NullInt64{Int64: int64(req.OriginalBillID), Valid: req.OriginalBillID > 0}
+
+	for _, b := range parts {
+		billID, err := insertBill(ctx, tx, req.OriginalOrderID, originalBillID, b)
 		if err != nil {
 			return nil, fmt.Errorf("gagal membuat bill: %w", err)
 		}
@@ -162,7 +184,7 @@ func (r *BillRepository) CreateSplit(ctx context.Context, req models.SplitBillRe
 		billIDs = append(billIDs, billID)
 	}
 
-	// ✅ Tambahan: update status bill utama menjadi 'split'
+	// Bill asal digantikan bill split
 	if req.OriginalBillID > 0 {
 		_, err = tx.ExecContext(ctx, `
 			UPDATE bills SET status = 'split', updated_at = NOW()
@@ -180,13 +202,458 @@ func (r *BillRepository) CreateSplit(ctx context.Context, req models.SplitBillRe
 	return billIDs, nil
 }
 
+// lockBillableOrder mengunci order lalu memastikan order masih bisa ditagih: belum settled/void/merged dan
+// belum punya bill aktif selain exceptBillID (bill yang sedang di-split), sehingga order tidak tertagih dua kali.
+func lockBillableOrder(ctx context.Context, tx *sql.Tx, orderID, exceptBillID int) error {
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&status)
+	if err != nil {
+		return err
+	}
+	if !models.IsLiveOrder(status) {
+		return fmt.Errorf("%w: order %d berstatus %s", ErrOrderNotBillable, orderID, status)
+	}
+
+	var active int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bills WHERE order_id = $1 AND id <> $2 AND status NOT IN ('void', 'split')
+	`, orderID, exceptBillID).Scan(&active)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return fmt.Errorf("%w: order %d sudah punya bill aktif", ErrOrderNotBillable, orderID)
+	}
+	return nil
+}
+
+// Merge menggabungkan beberapa bill open yang belum dibayar dari order berbeda menjadi satu bill.
+// Order bill pertama menjadi order tujuan, order lain di-merge ke sana (lihat mergeOrders),
+// lalu bill gabungan dihitung ulang dari seluruh item dengan diskon = jumlah diskon bill asal.
+func (r *BillRepository) Merge(ctx context.Context, billIDs []int, staffID int, reason string) (*models.MergeResult, error) {
+	if len(billIDs) < 2 {
+		return nil, fmt.Errorf("%w: minimal 2 bill", ErrInvalidMerge)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, order_id, status, paid_amount + refunded_amount, discount_amount
+		FROM bills
+		WHERE id = ANY($1)
+		FOR UPDATE
+	`, pq.Array(billIDs))
+	if err != nil {
+		return nil, err
+	}
+	type lockedBill struct {
+		orderID  int
+		status   string
+		paid     money.Amount
+		discount money.Amount
+	}
+	locked := map[int]lockedBill{}
+	for rows.Next() {
+		var (
+			id int
+			b  lockedBill
+		)
+		if err := rows.Scan(&id, &b.orderID, &b.status, &b.paid, &b.discount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		locked[id] = b
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		targetID    int
+		sourceIDs   []int
+		targetBills []int
+		discount    money.Amount
+	)
+	seenBill, seenOrder := map[int]bool{}, map[int]bool{}
+	for _, id := range billIDs {
+		b, ok := locked[id]
+		if !ok {
+			return nil, fmt.Errorf("bill %d: %w", id, sql.ErrNoRows)
+		}
+		if seenBill[id] {
+			return nil, fmt.Errorf("%w: bill %d disebut lebih dari sekali", ErrInvalidMerge, id)
+		}
+		seenBill[id] = true
+		if b.status != "open" || b.paid != 0 {
+			return nil, fmt.Errorf("%w: hanya bill open yang belum dibayar yang bisa digabung (bill %d)", ErrInvalidMerge, id)
+		}
+		discount += b.discount
+
+		if targetID == 0 {
+			targetID = b.orderID
+		}
+		if b.orderID == targetID {
+			targetBills = append(targetBills, id)
+		} else if !seenOrder[b.orderID] {
+			sourceIDs = append(sourceIDs, b.orderID)
+		}
+		seenOrder[b.orderID] = true
+	}
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: semua bill berasal dari order yang sama", ErrInvalidMerge)
+	}
+
+	result, err := mergeOrders(ctx, tx, targetID, sourceIDs, staffID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bills SET status = 'void', updated_at = NOW() WHERE id = ANY($1)
+	`, pq.Array(targetBills))
+	if err != nil {
+		return nil, err
+	}
+	result.VoidedBillIDs = append(targetBills, result.VoidedBillIDs...)
+
+	// Bill gabungan mencakup semua item order tujuan, jadi tidak boleh ada bill aktif lain yang tertinggal
+	var active int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bills WHERE order_id = $1 AND status NOT IN ('void', 'split')
+	`, targetID).Scan(&active)
+	if err != nil {
+		return nil, err
+	}
+	if active > 0 {
+		return nil, fmt.Errorf("%w: order %d masih punya bill aktif lain, sertakan dalam merge", ErrInvalidMerge, targetID)
+	}
+
+	b, err := priceOrder(ctx, tx, targetID, discount)
+	if err != nil {
+		return nil, err
+	}
+	if result.BillID, err = insertBill(ctx, tx, targetID, sql.NullInt64{}, b); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE order_merges SET bill_id = $1 WHERE target_order_id = $2 AND source_order_id = ANY($3)
+	`, result.BillID, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildSplitLines menyusun baris tagihan tiap split untuk mode items, quantity dan seat, sekaligus
+// memastikan setiap item (atau qty-nya) terbagi tepat satu kali. Nilai item yang dibagi dialokasikan per sen.
+func buildSplitLines(req models.SplitBillRequest, items []billItem) ([][]pricing.Line, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = models.SplitByItems
+	}
+	if len(req.Splits) < 2 {
+		return nil, fmt.Errorf("%w: split minimal 2 bagian", ErrInvalidSplit)
+	}
+	splits := make([][]pricing.Line, len(req.Splits))
+
+	byID := make(map[int]billItem, len(items))
+	for _, item := range items {
+		byID[item.id] = item
+	}
+
+	switch mode {
+	case models.SplitByItems:
+		used := map[int]bool{}
+		for i, split := range req.Splits {
+			for _, itemID := range split.ItemIDs {
+				item, ok := byID[itemID]
+				if !ok {
+					return nil, fmt.Errorf("%w: item %d tidak ditemukan atau sudah di-void", ErrInvalidSplit, itemID)
+				}
+				if used[itemID] {
+					return nil, fmt.Errorf("%w: item ID %d digunakan lebih dari satu kali", ErrInvalidSplit, itemID)
+				}
+				used[itemID] = true
+				splits[i] = append(splits[i], item.line)
+			}
+		}
+		for _, item := range items {
+			if !used[item.id] {
+				return nil, fmt.Errorf("%w: item %d belum masuk ke split manapun", ErrInvalidSplit, item.id)
+			}
+		}
+
+	case models.SplitByQuantity:
+		// qty dalam seperseratus, sama dengan presisi kolom qty
+		portions := map[int][]money.Amount{}
+		for _, item := range items {
+			portions[item.id] = make([]money.Amount, len(req.Splits))
+		}
+		for i, split := range req.Splits {
+			for _, part := range split.Items {
+				if _, ok := byID[part.OrderItemID]; !ok {
+					return nil, fmt.Errorf("%w: item %d tidak ditemukan atau sudah di-void", ErrInvalidSplit, part.OrderItemID)
+				}
+				if part.Qty <= 0 {
+					return nil, fmt.Errorf("%w: qty item %d harus lebih dari 0", ErrInvalidSplit, part.OrderItemID)
+				}
+				portions[part.OrderItemID][i] += money.Amount(math.Round(part.Qty * 100))
+			}
+		}
+		for _, item := range items {
+			var assigned money.Amount
+			for _, p := range portions[item.id] {
+				assigned += p
+			}
+			if assigned != money.Amount(math.Round(item.qty*100)) {
+				return nil, fmt.Errorf("%w: qty item %d terbagi %.2f dari %.2f", ErrInvalidSplit, item.id, float64(assigned)/100, item.qty)
+			}
+			for i, amount := range money.Allocate(item.line.Amount, portions[item.id]) {
+				if portions[item.id][i] > 0 {
+					splits[i] = append(splits[i], pricing.Line{Amount: amount, TaxRate: item.line.TaxRate})
+				}
+			}
+		}
+
+	case models.SplitBySeat:
+		seatSplit := map[int]int{}
+		for i, split := range req.Splits {
+			if len(split.Seats) == 0 {
+				return nil, fmt.Errorf("%w: setiap split harus berisi minimal satu kursi", ErrInvalidSplit)
+			}
+			for _, seat := range split.Seats {
+				if _, dup := seatSplit[seat]; dup {
+					return nil, fmt.Errorf("%w: kursi %d ada di lebih dari satu split", ErrInvalidSplit, seat)
+				}
+				seatSplit[seat] = i
+			}
+		}
+		for _, item := range items {
+			if !item.seatNo.Valid {
+				// Hidangan bersama dibagi rata ke semua split
+				for i, amount := range money.Allocate(item.line.Amount, equalWeights(len(req.Splits))) {
+					splits[i] = append(splits[i], pricing.Line{Amount: amount, TaxRate: item.line.TaxRate})
+				}
+				continue
+			}
+			i, ok := seatSplit[int(item.seatNo.Int64)]
+			if !ok {
+				return nil, fmt.Errorf("%w: kursi %d belum masuk ke split manapun", ErrInvalidSplit, item.seatNo.Int64)
+			}
+			splits[i] = append(splits[i], item.line)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: mode %q tidak dikenal", ErrInvalidSplit, mode)
+	}
+
+	for i, lines := range splits {
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("%w: split ke-%d tidak berisi item", ErrInvalidSplit, i+1)
+		}
+	}
+	return splits, nil
+}
+
+func equalWeights(n int) []money.Amount {
+	weights := make([]money.Amount, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+func insertBill(ctx context.Context, tx *sql.Tx, orderID int, originalBillID sql.NullInt64, b pricing.Breakdown) (int, error) {
+	var billID int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO bills (
+			bill_number, order_id, original_bill_id, status,
+			subtotal, tax_amount, service_charge, discount_amount,
+			tax_included, rounding_amount, total_amount
+		) VALUES (
+			$1, $2, $3, 'open', $4, $5, $6, $7, $8, $9, $10
+		) RETURNING id
+	`,
+		uuid.New().String(), orderID, originalBillID,
+		b.Subtotal, b.TaxAmount, b.ServiceCharge, b.Discount,
+		b.TaxIncluded, b.Rounding, b.Total,
+	).Scan(&billID)
+	return billID, err
+}
+
+// queryer dipenuhi *sql.DB maupun *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// billItem adalah item aktif order yang siap ditagihkan
+type billItem struct {
+	id     int
+	qty    float64
+	seatNo sql.NullInt64
+	line   pricing.Line
+}
+
+// loadBillItems memuat aturan pajak & service outlet untuk order beserta item aktifnya,
+// lengkap dengan tarif pajak kategori (atau tarif outlet bila kategori tidak diatur)
+func loadBillItems(ctx context.Context, q queryer, orderID int) (pricing.Rules, []billItem, error) {
+	var (
+		rules        pricing.Rules
+		orderTax     float64
+		currencyCode string
+	)
+	err := q.QueryRowContext(ctx, `
+		SELECT ot.service_charge_percentage, o.order_type = ANY(ot.service_charge_exempt_types),
+			ot.prices_include_tax, ot.tax_on_service_charge, ot.rounding_unit, ot.rounding_mode,
+			ot.tax_percentage, ot.currency
+		FROM orders o
+		JOIN outlets ot ON ot.id = o.outlet_id
+		WHERE o.id = $1
+	`, orderID).Scan(&rules.ServiceChargePct, &rules.ServiceExempt,
+		&rules.PricesIncludeTax, &rules.TaxOnServiceCharge, &rules.RoundingUnit, &rules.RoundingMode,
+		&orderTax, &currencyCode)
+	if err != nil {
+		return rules, nil, fmt.Errorf("gagal ambil aturan pajak order %d: %w", orderID, err)
+	}
+	currency, ok := money.LookupCurrency(currencyCode)
+	if !ok {
+		currency = money.DefaultCurrency
+	}
+	rules.Currency = currency
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT oi.id, oi.qty, oi.seat_no, COALESCE(oi.override_price, oi.unit_price) + oi.modifiers_price,
+			COALESCE(ct.tax_percentage, $2)
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN category_tax_rates ct ON ct.outlet_id = o.outlet_id AND ct.category_id = mi.category_id
+		WHERE oi.order_id = $1 AND oi.voided_at IS NULL
+		ORDER BY oi.id
+	`, orderID, orderTax)
+	if err != nil {
+		return rules, nil, err
+	}
+	defer rows.Close()
+
+	var items []billItem
+	for rows.Next() {
+		var (
+			item  billItem
+			price money.Amount
+		)
+		if err := rows.Scan(&item.id, &item.qty, &item.seatNo, &price, &item.line.TaxRate); err != nil {
+			return rules, nil, err
+		}
+		item.line.Amount = price.MulQty(item.qty)
+		items = append(items, item)
+	}
+	return rules, items, rows.Err()
+}
+
+// priceOrder menghitung tagihan untuk seluruh item aktif order lewat pricing.Calculate
+func priceOrder(ctx context.Context, q queryer, orderID int, discount money.Amount) (pricing.Breakdown, error) {
+	rules, items, err := loadBillItems(ctx, q, orderID)
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	lines := make([]pricing.Line, len(items))
+	for i, item := range items {
+		lines[i] = item.line
+	}
+	return pricing.Calculate(lines, discount, rules), nil
+}
+
+// priceOrders menghitung tagihan beberapa order sekaligus (tanpa diskon) dengan satu query aturan outlet
+// dan item aktif, untuk tampilan yang menampilkan banyak order seperti denah meja
+func priceOrders(ctx context.Context, q queryer, orderIDs []int) (map[int]pricing.Breakdown, error) {
+	totals := make(map[int]pricing.Breakdown, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return totals, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT o.id, ot.service_charge_percentage, o.order_type = ANY(ot.service_charge_exempt_types),
+			ot.prices_include_tax, ot.tax_on_service_charge, ot.rounding_unit, ot.rounding_mode, ot.currency,
+			oi.qty, COALESCE(oi.override_price, oi.unit_price) + oi.modifiers_price,
+			COALESCE(ct.tax_percentage, ot.tax_percentage)
+		FROM orders o
+		JOIN outlets ot ON ot.id = o.outlet_id
+		LEFT JOIN order_items oi ON oi.order_id = o.id AND oi.voided_at IS NULL
+		LEFT JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN category_tax_rates ct ON ct.outlet_id = o.outlet_id AND ct.category_id = mi.category_id
+		WHERE o.id = ANY($1)
+		ORDER BY o.id, oi.id
+	`, pq.Array(orderIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type orderLines struct {
+		rules pricing.Rules
+		lines []pricing.Line
+	}
+	orders := map[int]*orderLines{}
+	for rows.Next() {
+		var (
+			orderID      int
+			rules        pricing.Rules
+			currencyCode string
+			qty          sql.NullFloat64
+			price        money.NullAmount
+			taxRate      sql.NullFloat64
+		)
+		err := rows.Scan(&orderID, &rules.ServiceChargePct, &rules.ServiceExempt,
+			&rules.PricesIncludeTax, &rules.TaxOnServiceCharge, &rules.RoundingUnit, &rules.RoundingMode, &currencyCode,
+			&qty, &price, &taxRate)
+		if err != nil {
+			return nil, err
+		}
+		o, ok := orders[orderID]
+		if !ok {
+			currency, found := money.LookupCurrency(currencyCode)
+			if !found {
+				currency = money.DefaultCurrency
+			}
+			rules.Currency = currency
+			o = &orderLines{rules: rules}
+			orders[orderID] = o
+		}
+		if qty.Valid {
+			o.lines = append(o.lines, pricing.Line{Amount: price.Amount.MulQty(qty.Float64), TaxRate: taxRate.Float64})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, o := range orders {
+		totals[id] = pricing.Calculate(o.lines, 0, o.rules)
+	}
+	return totals, nil
+}
+
 // repositories/bill_repository.go
 func (r *BillRepository) List(ctx context.Context) ([]*models.Bill, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
 			id, bill_number, order_id, original_bill_id, status,
 			subtotal, tax_amount, service_charge, discount_amount,
-			total_amount, paid_amount, balance_due,
+			tax_included, rounding_amount,
+			total_amount, paid_amount, refunded_amount, balance_due, tip_amount,
 			created_at, updated_at
 		FROM bills
 		ORDER BY created_at DESC
@@ -204,7 +671,8 @@ func (r *BillRepository) List(ctx context.Context) ([]*models.Bill, error) {
 		err := rows.Scan(
 			&bill.ID, &bill.BillNumber, &bill.OrderID, &originalBillID, &bill.Status,
 			&bill.Subtotal, &bill.TaxAmount, &bill.ServiceCharge, &bill.DiscountAmount,
-			&bill.TotalAmount, &bill.PaidAmount, &bill.BalanceDue,
+			&bill.TaxIncluded, &bill.RoundingAmount,
+			&bill.TotalAmount, &bill.PaidAmount, &bill.RefundedAmount, &bill.BalanceDue, &bill.TipAmount,
 			&bill.CreatedAt, &bill.UpdatedAt,
 		)
 		if err != nil {
@@ -223,7 +691,8 @@ func (r *BillRepository) GetByID(ctx context.Context, id int) (*models.Bill, err
 		SELECT 
 			id, bill_number, order_id, original_bill_id, status,
 			subtotal, tax_amount, service_charge, discount_amount,
-			total_amount, paid_amount, balance_due,
+			tax_included, rounding_amount,
+			total_amount, paid_amount, refunded_amount, balance_due, tip_amount,
 			created_at, updated_at
 		FROM bills
 		WHERE id = $1
@@ -240,9 +709,13 @@ func (r *BillRepository) GetByID(ctx context.Context, id int) (*models.Bill, err
 		&bill.TaxAmount,
 		&bill.ServiceCharge,
 		&bill.DiscountAmount,
+		&bill.TaxIncluded,
+		&bill.RoundingAmount,
 		&bill.TotalAmount,
 		&bill.PaidAmount,
+		&bill.RefundedAmount,
 		&bill.BalanceDue,
+		&bill.TipAmount,
 		&bill.CreatedAt,
 		&bill.UpdatedAt,
 	)
@@ -257,62 +730,456 @@ func (r *BillRepository) GetByID(ctx context.Context, id int) (*models.Bill, err
 	return &bill, nil
 }
 
-func (r *BillRepository) SoftDelete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `
+// GetOutletID mengambil outlet bill lewat order-nya
+func (r *BillRepository) GetOutletID(ctx context.Context, billID int) (int, error) {
+	var outletID int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT o.outlet_id FROM bills b JOIN orders o ON o.id = b.order_id WHERE b.id = $1
+	`, billID).Scan(&outletID)
+	return outletID, err
+}
+
+// SoftDelete mem-void bill dan mengembalikan order-nya. Hanya bill open yang belum ada pembayaran maupun refund,
+// sehingga tidak ada pembayaran, posting room charge atau transaksi laci kas yang tertinggal di bill void.
+// Bill split ditolak: induknya sudah digantikan pecahan, dan pecahan yang di-void membuat itemnya tidak pernah
+// tertagih saat pecahan lain lunas.
+func (r *BillRepository) SoftDelete(ctx context.Context, id int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var (
+		orderID   int
+		status    string
+		paid      money.Amount
+		refunded  money.Amount
+		splitPart bool
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT order_id, status, COALESCE(paid_amount, 0), refunded_amount, original_bill_id IS NOT NULL
+		FROM bills
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&orderID, &status, &paid, &refunded, &splitPart)
+	if err != nil {
+		return 0, fmt.Errorf("bill %d: %w", id, err)
+	}
+	switch {
+	case status == "split" || splitPart:
+		return 0, fmt.Errorf("%w: bill %d adalah bagian dari split bill", ErrBillNotVoidable, id)
+	case status != "open":
+		return 0, fmt.Errorf("%w: bill %d berstatus %s", ErrBillNotVoidable, id, status)
+	case paid != 0 || refunded != 0:
+		return 0, fmt.Errorf("%w: bill %d sudah ada pembayaran", ErrBillNotVoidable, id)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE bills SET status = 'void', updated_at = NOW()
 		WHERE id = $1
 	`, id)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return orderID, tx.Commit()
 }
 
-func (r *BillRepository) Pay(ctx context.Context, payment *models.BillPayment) error {
+// Pay mencatat satu pembayaran dengan aturan tendering: hanya bill open/partial yang bisa dibayar,
+// cash boleh lebih dan selisihnya menjadi kembalian, metode lain maksimal sebesar sisa tagihan.
+// Tip dicatat terpisah dan tidak mengurangi tagihan. Input payment.Amount untuk cash adalah uang yang
+// diterima (termasuk tip), untuk metode lain adalah nominal yang dibebankan ke bill (tanpa tip).
+// Room charge divalidasi dan diposting ke PMS sebelum commit, posting dibatalkan lagi jika transaksi gagal.
+func (r *BillRepository) Pay(ctx context.Context, payment *models.BillPayment, staffID int) (*models.PaymentReceipt, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
-	// Insert payment
-	_, err = tx.ExecContext(ctx, `
+	receipt := &models.PaymentReceipt{
+		BillID:          payment.BillID,
+		PaymentMethod:   payment.PaymentMethod,
+		ReferenceNumber: payment.ReferenceNumber.String,
+	}
+	var (
+		status       string
+		balance      money.Amount
+		currencyCode string
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT b.bill_number, b.order_id, b.status, b.total_amount, b.balance_due, ot.currency
+		FROM bills b
+		JOIN orders o ON o.id = b.order_id
+		JOIN outlets ot ON ot.id = o.outlet_id
+		WHERE b.id = $1
+		FOR UPDATE OF b
+	`, payment.BillID).Scan(&receipt.BillNumber, &receipt.OrderID, &status, &receipt.BillTotal, &balance, &currencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("bill %d: %w", payment.BillID, err)
+	}
+	currency, ok := money.LookupCurrency(currencyCode)
+	if !ok {
+		currency = money.DefaultCurrency
+	}
+	if unit := currency.Unit(); payment.Amount%unit != 0 || payment.TipAmount%unit != 0 {
+		return nil, fmt.Errorf("%w: nominal melebihi presisi mata uang %s", ErrOverpayment, currency.Code)
+	}
+	if status != "open" && status != "partial" {
+		return nil, fmt.Errorf("%w: bill %d berstatus %s", ErrBillNotPayable, payment.BillID, status)
+	}
+	if balance <= 0 {
+		return nil, fmt.Errorf("%w: bill %d tidak punya sisa tagihan", ErrBillNotPayable, payment.BillID)
+	}
+
+	if err := tender(payment, balance); err != nil {
+		return nil, err
+	}
+	if payment.DrawerSessionID, err = drawerSessionFor(ctx, tx, payment.BillID, staffID, payment.PaymentMethod); err != nil {
+		return nil, err
+	}
+
+	committed := false
+	if payment.PaymentMethod == models.PaymentRoomCharge && r.pms != nil {
+		guest, err := r.postRoomCharge(ctx, tx, payment, receipt.OrderID, receipt.BillNumber)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if !committed {
+				r.cancelRoomCharge(payment, receipt.BillNumber)
+			}
+		}()
+		receipt.GuestName = guest.Name
+		receipt.PMSPostingID = payment.PMSPostingID.String
+		receipt.ReferenceNumber = payment.ReferenceNumber.String
+	}
+
+	err = tx.QueryRowContext(ctx, `
 		INSERT INTO bill_payments (
-			bill_id, payment_method, amount, reference_number,
-			room_charge_approved_by
-		) VALUES ($1, $2, $3, $4, $5)
+			bill_id, payment_method, amount, tendered_amount, tip_amount, change_amount,
+			reference_number, room_charge_approved_by, received_by, pms_guest_id, pms_posting_id, drawer_session_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, payment_time
 	`,
 		payment.BillID,
 		payment.PaymentMethod,
 		payment.Amount,
+		payment.TenderedAmount,
+		payment.TipAmount,
+		payment.ChangeAmount,
 		payment.ReferenceNumber,
 		payment.RoomChargeApprovedBy,
+		sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+		payment.PMSGuestID,
+		payment.PMSPostingID,
+		payment.DrawerSessionID,
+	).Scan(&payment.ID, &payment.PaymentTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// balance_due adalah kolom generated, ikut berubah bersama paid_amount
+	err = tx.QueryRowContext(ctx, `
+		UPDATE bills
+		SET paid_amount = paid_amount + $1,
+			tip_amount = tip_amount + $2,
+			status = CASE WHEN refunded_amount + paid_amount + $1 >= total_amount THEN 'paid' ELSE 'partial' END::status_bill,
+			updated_at = NOW()
+		WHERE id = $3
+		RETURNING status, paid_amount, balance_due
+	`, payment.Amount, payment.TipAmount, payment.BillID).Scan(&receipt.BillStatus, &receipt.PaidAmount, &receipt.BalanceDue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Order otomatis settled jika semua bill aktif sudah lunas
+	if receipt.OrderSettled, err = settleOrderIfPaid(ctx, tx, payment.BillID, staffID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	committed = true
+
+	receipt.PaymentID = payment.ID
+	receipt.TenderedAmount = payment.TenderedAmount
+	receipt.Amount = payment.Amount
+	receipt.TipAmount = payment.TipAmount
+	receipt.ChangeAmount = payment.ChangeAmount
+	receipt.PaymentTime = payment.PaymentTime
+	return receipt, nil
+}
+
+// LookupRoomGuest mencari tamu kamar di PMS supaya kasir bisa memastikan sebelum room charge
+func (r *BillRepository) LookupRoomGuest(ctx context.Context, room string) (*pms.Guest, error) {
+	if r.pms == nil {
+		return nil, fmt.Errorf("%w: integrasi PMS tidak aktif", pms.ErrUnavailable)
+	}
+	return r.pms.LookupGuest(ctx, room)
+}
+
+// postRoomCharge memvalidasi tamu di PMS (masih menginap, batas kredit cukup) lalu memposting
+// uang yang dibebankan (termasuk tip) ke folio kamar. Kamar diambil dari reference_number atau hotel_room order.
+func (r *BillRepository) postRoomCharge(ctx context.Context, tx *sql.Tx, payment *models.BillPayment, orderID int, billNumber string) (*pms.Guest, error) {
+	room := payment.ReferenceNumber.String
+	if room == "" {
+		var hotelRoom sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT hotel_room FROM orders WHERE id = $1`, orderID).Scan(&hotelRoom)
+		if err != nil {
+			return nil, err
+		}
+		room = hotelRoom.String
+	}
+	room = pms.NormalizeRoom(room)
+	if room == "" {
+		return nil, fmt.Errorf("%w: nomor kamar wajib diisi untuk room charge", pms.ErrRejected)
+	}
+
+	guest, err := r.pms.LookupGuest(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if err := guest.Authorize(payment.TenderedAmount); err != nil {
+		return nil, err
+	}
+
+	postingID, err := r.pms.PostCharge(ctx, pms.Charge{
+		Room:        room,
+		GuestID:     guest.GuestID,
+		Amount:      payment.TenderedAmount,
+		Reference:   billNumber,
+		Description: "Restaurant bill " + billNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ReferenceNumber = sql.NullString{String: room, Valid: true}
+	payment.PMSGuestID = sql.NullString{String: guest.GuestID, Valid: true}
+	payment.PMSPostingID = sql.NullString{String: postingID, Valid: true}
+	return guest, nil
+}
+
+// cancelRoomCharge membatalkan posting room charge yang transaksinya gagal di database
+func (r *BillRepository) cancelRoomCharge(payment *models.BillPayment, billNumber string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.pms.ReverseCharge(ctx, pms.Reversal{
+		PostingID: payment.PMSPostingID.String,
+		Room:      payment.ReferenceNumber.String,
+		GuestID:   payment.PMSGuestID.String,
+		Amount:    payment.TenderedAmount,
+		Reference: billNumber,
+		Reason:    "Transaksi POS gagal",
+	})
+	if err != nil {
+		log.Printf("PERLU TINDAKAN: posting PMS %s (bill %s) gagal dibatalkan: %v", payment.PMSPostingID.String, billNumber, err)
+	}
+}
+
+// tender menghitung nominal yang mengurangi tagihan, uang diterima dan kembalian terhadap sisa tagihan
+func tender(payment *models.BillPayment, balance money.Amount) error {
+	if payment.Amount <= 0 || payment.TipAmount < 0 {
+		return fmt.Errorf("%w: nominal harus lebih besar dari 0 dan tip tidak boleh negatif", ErrOverpayment)
+	}
+	if payment.PaymentMethod == models.PaymentCash {
+		payment.TenderedAmount = payment.Amount
+		cash := payment.TenderedAmount - payment.TipAmount
+		if cash <= 0 {
+			return fmt.Errorf("%w: uang diterima harus lebih besar dari tip", ErrOverpayment)
+		}
+		payment.Amount = min(cash, balance)
+		payment.ChangeAmount = cash - payment.Amount
+		return nil
+	}
+
+	if payment.Amount > balance {
+		return fmt.Errorf("%w: %s maksimal sebesar sisa tagihan %s", ErrOverpayment, payment.PaymentMethod, balance)
+	}
+	payment.TenderedAmount = payment.Amount + payment.TipAmount
+	payment.ChangeAmount = 0
+	return nil
+}
+
+// Refund mengembalikan sebagian atau seluruh sisa satu pembayaran (amount 0 = seluruh sisa) lewat metode asalnya.
+// Refund atas bill lunas mengurangi paid_amount dan menambah refunded_amount sehingga tagihan bersih bill ikut
+// berkurang, bill lunas yang seluruh pembayarannya dikembalikan menjadi refunded. Refund atas bill partial adalah
+// pembatalan pembayaran (is_reversal): hanya paid_amount yang berkurang sehingga sisa tagihan terbuka lagi. Refund room_charge yang
+// pembayarannya diposting ke PMS di-reverse di folio tamu sebelum commit.
+func (r *BillRepository) Refund(ctx context.Context, refund *models.BillRefund, staffID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		status       string
+		billNumber   string
+		paid         money.Amount
+		settled      money.Amount
+		pmsGuestID   sql.NullString
+		pmsPostingID sql.NullString
 	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT p.bill_id, b.bill_number, p.payment_method, p.reference_number, p.amount, b.status,
+			p.pms_guest_id, p.pms_posting_id
+		FROM bill_payments p
+		JOIN bills b ON b.id = p.bill_id
+		WHERE p.id = $1
+		FOR UPDATE OF b
+	`, refund.PaymentID).Scan(&refund.BillID, &billNumber, &refund.RefundMethod, &refund.ReferenceNumber, &paid, &status,
+		&pmsGuestID, &pmsPostingID)
 	if err != nil {
+		return fmt.Errorf("pembayaran %d: %w", refund.PaymentID, err)
+	}
+	if status != "paid" && status != "partial" {
+		return fmt.Errorf("%w: bill %d berstatus %s", ErrInvalidRefund, refund.BillID, status)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM bill_refunds WHERE payment_id = $1
+	`, refund.PaymentID).Scan(&settled)
+	if err != nil {
+		return err
+	}
+	refundable := paid - settled
+	if refund.Amount == 0 {
+		refund.Amount = refundable
+	}
+	if refundable <= 0 || refund.Amount > refundable {
+		return fmt.Errorf("%w: sisa pembayaran %d yang bisa di-refund %s", ErrInvalidRefund, refund.PaymentID, refundable)
+	}
+	if refund.DrawerSessionID, err = drawerSessionFor(ctx, tx, refund.BillID, staffID, refund.RefundMethod); err != nil {
 		return err
 	}
+	refund.IsReversal = status != "paid"
 
-	// Update bill paid_amount
-	_, err = tx.ExecContext(ctx, `
-		UPDATE bills
-		SET paid_amount = paid_amount + $1
-		WHERE id = $2
-	`, payment.Amount, payment.BillID)
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO bill_refunds (
+			bill_id, payment_id, amount, refund_method, reference_number, reason, approved_by, refunded_by,
+			drawer_session_id, is_reversal
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, refunded_at
+	`,
+		refund.BillID, refund.PaymentID, refund.Amount, refund.RefundMethod, refund.ReferenceNumber,
+		refund.Reason, refund.ApprovedBy, sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+		refund.DrawerSessionID, refund.IsReversal,
+	).Scan(&refund.ID, &refund.RefundedAt)
 	if err != nil {
 		return err
 	}
+	refund.RefundedBy = sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0}
 
-	// Cek apakah bill sudah lunas
-	_, err = tx.ExecContext(ctx, `
+	err = tx.QueryRowContext(ctx, `
 		UPDATE bills
-		SET 
+		SET paid_amount = paid_amount - $1,
+			refunded_amount = refunded_amount + CASE WHEN $3 THEN 0 ELSE $1 END,
 			status = CASE
-				WHEN paid_amount >= total_amount THEN 'paid'
-				WHEN paid_amount > 0 AND paid_amount < total_amount THEN 'partial'
-				ELSE status
-			END
-		WHERE id = $1
-	`, payment.BillID)
+				WHEN paid_amount - $1 > 0 THEN status
+				WHEN status = 'paid' THEN 'refunded'
+				ELSE 'open'
+			END::status_bill,
+			updated_at = NOW()
+		WHERE id = $2
+		RETURNING status
+	`, refund.Amount, refund.BillID, refund.IsReversal).Scan(&refund.BillStatus)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	if pmsPostingID.Valid {
+		if r.pms == nil {
+			return fmt.Errorf("%w: pembayaran diposting ke PMS tetapi integrasi PMS tidak aktif", pms.ErrUnavailable)
+		}
+		postingID, err := r.pms.ReverseCharge(ctx, pms.Reversal{
+			PostingID: pmsPostingID.String,
+			Room:      refund.ReferenceNumber.String,
+			GuestID:   pmsGuestID.String,
+			Amount:    refund.Amount,
+			Reference: billNumber,
+			Reason:    refund.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		refund.PMSPostingID = sql.NullString{String: postingID, Valid: true}
+		_, err = tx.ExecContext(ctx, `UPDATE bill_refunds SET pms_posting_id = $1 WHERE id = $2`, refund.PMSPostingID, refund.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if refund.PMSPostingID.Valid {
+			log.Printf("PERLU TINDAKAN: refund %d gagal disimpan tetapi posting kredit PMS %s sudah dibuat: %v",
+				refund.ID, refund.PMSPostingID.String, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// ListRefunds mengambil refund satu bill
+func (r *BillRepository) ListRefunds(ctx context.Context, billID int) ([]*models.BillRefund, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, bill_id, payment_id, amount, refund_method, reference_number, reason,
+			approved_by, refunded_by, pms_posting_id, drawer_session_id, is_reversal, refunded_at
+		FROM bill_refunds
+		WHERE bill_id = $1
+		ORDER BY refunded_at, id
+	`, billID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []*models.BillRefund{}
+	for rows.Next() {
+		var f models.BillRefund
+		err := rows.Scan(&f.ID, &f.BillID, &f.PaymentID, &f.Amount, &f.RefundMethod, &f.ReferenceNumber, &f.Reason,
+			&f.ApprovedBy, &f.RefundedBy, &f.PMSPostingID, &f.DrawerSessionID, &f.IsReversal, &f.RefundedAt)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, &f)
+	}
+	return refunds, rows.Err()
+}
+
+// settleOrderIfPaid menutup order bila semua bill aktifnya sudah lunas, mengembalikan true jika order di-settle
+func settleOrderIfPaid(ctx context.Context, tx *sql.Tx, billID, staffID int) (bool, error) {
+	var orderID int
+	var orderStatus string
+	err := tx.QueryRowContext(ctx, `
+		SELECT o.id, o.status
+		FROM bills b
+		JOIN orders o ON o.id = b.order_id
+		WHERE b.id = $1
+		FOR UPDATE OF o
+	`, billID).Scan(&orderID, &orderStatus)
+	if err != nil {
+		return false, err
+	}
+
+	if !models.CanTransitionOrder(orderStatus, models.OrderSettled) {
+		return false, nil
+	}
+
+	var unpaid int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bills
+		WHERE order_id = $1 AND status NOT IN ('paid', 'refunded', 'void', 'split')
+	`, orderID).Scan(&unpaid)
+	if err != nil {
+		return false, err
+	}
+	if unpaid > 0 {
+		return false, nil
+	}
+
+	return true, setOrderStatus(ctx, tx, orderID, orderStatus, models.OrderSettled, staffID, "Semua bill lunas")
 }
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/pricing"
+)
+
+// Tiga item: dua porsi nasi (kursi 1), es teh (kursi 2) dan hidangan bersama tanpa kursi
+var splitItems = []billItem{
+	{id: 1, qty: 2, seatNo: sql.NullInt64{Int64: 1, Valid: true}, line: pricing.Line{Amount: money.New(50000, 0), TaxRate: 11}},
+	{id: 2, qty: 1, seatNo: sql.NullInt64{Int64: 2, Valid: true}, line: pricing.Line{Amount: money.New(10000, 0), TaxRate: 11}},
+	{id: 3, qty: 3, line: pricing.Line{Amount: money.New(100, 0), TaxRate: 10}},
+}
+
+func splitTotals(splits [][]pricing.Line) []money.Amount {
+	totals := make([]money.Amount, len(splits))
+	for i, lines := range splits {
+		for _, l := range lines {
+			totals[i] += l.Amount
+		}
+	}
+	return totals
+}
+
+func TestBuildSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.SplitBillRequest
+		want []money.Amount
+	}{
+		{
+			name: "items (mode default)",
+			req: models.SplitBillRequest{Splits: []models.SplitBillInput{
+				{ItemIDs: []int{1}},
+				{ItemIDs: []int{2, 3}},
+			}},
+			want: []money.Amount{money.New(50000, 0), money.New(10100, 0)},
+		},
+		{
+			name: "quantity membagi qty per sen",
+			req: models.SplitBillRequest{Mode: models.SplitByQuantity, Splits: []models.SplitBillInput{
+				{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 1.5}, {OrderItemID: 3, Qty: 1}}},
+				{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 0.5}, {OrderItemID: 2, Qty: 1}, {OrderItemID: 3, Qty: 2}}},
+			}},
+			want: []money.Amount{money.New(37500, 0) + money.New(33, 33), money.New(12500, 0) + money.New(10000, 0) + money.New(66, 67)},
+		},
+		{
+			name: "seat, hidangan bersama dibagi rata",
+			req: models.SplitBillRequest{Mode: models.SplitBySeat, Splits: []models.SplitBillInput{
+				{Seats: []int{1}},
+				{Seats: []int{2}},
+			}},
+			want: []money.Amount{money.New(50050, 0), money.New(10050, 0)},
+		},
+	}
+	for _, tt := range tests {
+		splits, err := buildSplitLines(tt.req, splitItems)
+		if err != nil {
+			t.Errorf("%s: error %v", tt.name, err)
+			continue
+		}
+		got := splitTotals(splits)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d split, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: split %d = %s, want %s", tt.name, i+1, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestBuildSplitLinesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.SplitBillRequest
+	}{
+		{"hanya satu bagian", models.SplitBillRequest{Splits: []models.SplitBillInput{{ItemIDs: []int{1, 2, 3}}}}},
+		{"item dipakai dua kali", models.SplitBillRequest{Splits: []models.SplitBillInput{
+			{ItemIDs: []int{1, 2}}, {ItemIDs: []int{2, 3}},
+		}}},
+		{"item tertinggal", models.SplitBillRequest{Splits: []models.SplitBillInput{
+			{ItemIDs: []int{1}}, {ItemIDs: []int{2}},
+		}}},
+		{"item tidak dikenal", models.SplitBillRequest{Splits: []models.SplitBillInput{
+			{ItemIDs: []int{1, 2}}, {ItemIDs: []int{3, 9}},
+		}}},
+		{"qty tidak habis", models.SplitBillRequest{Mode: models.SplitByQuantity, Splits: []models.SplitBillInput{
+			{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 1}, {OrderItemID: 2, Qty: 1}}},
+			{Items: []models.SplitItemQty{{OrderItemID: 3, Qty: 3}}},
+		}}},
+		{"qty negatif", models.SplitBillRequest{Mode: models.SplitByQuantity, Splits: []models.SplitBillInput{
+			{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 3}, {OrderItemID: 2, Qty: 1}}},
+			{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: -1}, {OrderItemID: 3, Qty: 3}}},
+		}}},
+		{"kursi di dua split", models.SplitBillRequest{Mode: models.SplitBySeat, Splits: []models.SplitBillInput{
+			{Seats: []int{1, 2}}, {Seats: []int{2}},
+		}}},
+		{"kursi belum dibagi", models.SplitBillRequest{Mode: models.SplitBySeat, Splits: []models.SplitBillInput{
+			{Seats: []int{1}}, {Seats: []int{3}},
+		}}},
+		{"split tanpa kursi", models.SplitBillRequest{Mode: models.SplitBySeat, Splits: []models.SplitBillInput{
+			{Seats: []int{1, 2}}, {},
+		}}},
+		{"mode tidak dikenal", models.SplitBillRequest{Mode: "random", Splits: []models.SplitBillInput{{}, {}}}},
+	}
+	for _, tt := range tests {
+		if _, err := buildSplitLines(tt.req, splitItems); !errors.Is(err, ErrInvalidSplit) {
+			t.Errorf("%s: error = %v, want ErrInvalidSplit", tt.name, err)
+		}
+	}
+}
+
+// Split qty pada baris ganjil tidak boleh kehilangan atau menambah sen
+func TestBuildSplitLinesQuantitySumsToItem(t *testing.T) {
+	items := []billItem{{id: 1, qty: 7, line: pricing.Line{Amount: money.New(1000, 1), TaxRate: 11}}}
+	req := models.SplitBillRequest{Mode: models.SplitByQuantity, Splits: []models.SplitBillInput{
+		{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 2}}},
+		{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 2}}},
+		{Items: []models.SplitItemQty{{OrderItemID: 1, Qty: 3}}},
+	}}
+	splits, err := buildSplitLines(req, items)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	var sum money.Amount
+	for _, a := range splitTotals(splits) {
+		sum += a
+	}
+	if sum != items[0].line.Amount {
+		t.Errorf("jumlah split %s, want %s", sum, items[0].line.Amount)
+	}
+}
+
+func TestTender(t *testing.T) {
+	tests := []struct {
+		name                     string
+		method                   string
+		amount, tip, balance     money.Amount
+		wantAmount, wantTendered money.Amount
+		wantChange               money.Amount
+	}{
+		{"cash pas", models.PaymentCash, money.New(100000, 0), 0, money.New(100000, 0), money.New(100000, 0), money.New(100000, 0), 0},
+		{"cash lebih jadi kembalian", models.PaymentCash, money.New(150000, 0), 0, money.New(112500, 0), money.New(112500, 0), money.New(150000, 0), money.New(37500, 0)},
+		{"cash kurang jadi partial", models.PaymentCash, money.New(50000, 0), 0, money.New(112500, 0), money.New(50000, 0), money.New(50000, 0), 0},
+		{"cash dengan tip", models.PaymentCash, money.New(120000, 0), money.New(5000, 0), money.New(112500, 0), money.New(112500, 0), money.New(120000, 0), money.New(2500, 0)},
+		{"kartu sebagian dengan tip", models.PaymentCreditCard, money.New(60000, 0), money.New(10000, 0), money.New(112500, 0), money.New(60000, 0), money.New(70000, 0), 0},
+		{"kartu tepat sisa tagihan", models.PaymentDebitCard, money.New(112500, 0), 0, money.New(112500, 0), money.New(112500, 0), money.New(112500, 0), 0},
+	}
+	for _, tt := range tests {
+		p := &models.BillPayment{PaymentMethod: tt.method, Amount: tt.amount, TipAmount: tt.tip}
+		if err := tender(p, tt.balance); err != nil {
+			t.Errorf("%s: error %v", tt.name, err)
+			continue
+		}
+		if p.Amount != tt.wantAmount || p.TenderedAmount != tt.wantTendered || p.ChangeAmount != tt.wantChange {
+			t.Errorf("%s: amount/tendered/change = %s/%s/%s, want %s/%s/%s", tt.name,
+				p.Amount, p.TenderedAmount, p.ChangeAmount, tt.wantAmount, tt.wantTendered, tt.wantChange)
+		}
+	}
+}
+
+func TestTenderInvalid(t *testing.T) {
+	tests := []struct {
+		name                 string
+		method               string
+		amount, tip, balance money.Amount
+	}{
+		{"nominal nol", models.PaymentCash, 0, 0, money.New(1000, 0)},
+		{"nominal negatif", models.PaymentCreditCard, -money.New(1000, 0), 0, money.New(1000, 0)},
+		{"tip negatif", models.PaymentCreditCard, money.New(1000, 0), -1, money.New(1000, 0)},
+		{"cash habis untuk tip", models.PaymentCash, money.New(5000, 0), money.New(5000, 0), money.New(1000, 0)},
+		{"kartu melebihi sisa tagihan", models.PaymentCreditCard, money.New(1000, 1), 0, money.New(1000, 0)},
+		{"room charge melebihi sisa tagihan", models.PaymentRoomCharge, money.New(2000, 0), 0, money.New(1000, 0)},
+	}
+	for _, tt := range tests {
+		p := &models.BillPayment{PaymentMethod: tt.method, Amount: tt.amount, TipAmount: tt.tip}
+		if err := tender(p, tt.balance); !errors.Is(err, ErrOverpayment) {
+			t.Errorf("%s: error = %v, want ErrOverpayment", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"sort"
+	"time"
+)
+
+var (
+	ErrDrawerAlreadyOpen = errors.New("kasir masih punya sesi laci kas yang terbuka")
+	ErrNoDrawerSession   = errors.New("tidak ada sesi laci kas terbuka")
+	ErrDrawerClosed      = errors.New("sesi laci kas sudah ditutup")
+)
+
+type CashDrawerRepository struct {
+	db *sql.DB
+}
+
+func NewCashDrawerRepository(db *sql.DB) *CashDrawerRepository {
+	return &CashDrawerRepository{db: db}
+}
+
+// Open membuka sesi laci kas baru dengan modal awal, satu kasir hanya boleh punya satu sesi terbuka
+func (r *CashDrawerRepository) Open(ctx context.Context, outletID, cashierID int, openingFloat money.Amount) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO cash_drawer_sessions (outlet_id, cashier_id, opening_float)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (cashier_id) WHERE status = 'open' DO NOTHING
+		RETURNING id
+	`, outletID, cashierID, openingFloat).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: staff %d", ErrDrawerAlreadyOpen, cashierID)
+	}
+	return id, err
+}
+
+const drawerSessionColumns = `
+	s.id, s.outlet_id, s.cashier_id, st.name, s.opening_float, s.status,
+	s.opened_at, s.closed_at, s.closed_by, s.notes
+`
+
+func scanDrawerSession(row interface{ Scan(...any) error }) (*models.CashDrawerSession, error) {
+	var s models.CashDrawerSession
+	err := row.Scan(&s.ID, &s.OutletID, &s.CashierID, &s.CashierName, &s.OpeningFloat, &s.Status,
+		&s.OpenedAt, &s.ClosedAt, &s.ClosedBy, &s.Notes)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *CashDrawerRepository) GetByID(ctx context.Context, id int) (*models.CashDrawerSession, error) {
+	return scanDrawerSession(r.db.QueryRowContext(ctx, `
+		SELECT `+drawerSessionColumns+`
+		FROM cash_drawer_sessions s
+		JOIN staff st ON st.id = s.cashier_id
+		WHERE s.id = $1
+	`, id))
+}
+
+// Current mengambil sesi kasir yang masih terbuka, sql.ErrNoRows jika belum buka laci
+func (r *CashDrawerRepository) Current(ctx context.Context, cashierID int) (*models.CashDrawerSession, error) {
+	return scanDrawerSession(r.db.QueryRowContext(ctx, `
+		SELECT `+drawerSessionColumns+`
+		FROM cash_drawer_sessions s
+		JOIN staff st ON st.id = s.cashier_id
+		WHERE s.cashier_id = $1 AND s.status = 'open'
+	`, cashierID))
+}
+
+// List mengambil sesi per outlet/status, closedFrom-closedTo (jika diisi) membatasi waktu tutup sesi [from, to)
+func (r *CashDrawerRepository) List(ctx context.Context, outletID int, status string, closedFrom, closedTo time.Time) ([]*models.CashDrawerSession, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+drawerSessionColumns+`
+		FROM cash_drawer_sessions s
+		JOIN staff st ON st.id = s.cashier_id
+		WHERE ($1 = 0 OR s.outlet_id = $1)
+			AND ($2 = '' OR s.status = $2)
+			AND ($3::timestamp IS NULL OR s.closed_at >= $3)
+			AND ($4::timestamp IS NULL OR s.closed_at < $4)
+		ORDER BY s.opened_at DESC
+		LIMIT 200
+	`, outletID, status,
+		sql.NullTime{Time: closedFrom, Valid: !closedFrom.IsZero()},
+		sql.NullTime{Time: closedTo, Valid: !closedTo.IsZero()})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*models.CashDrawerSession{}
+	for rows.Next() {
+		s, err := scanDrawerSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// MethodTotals merangkum pembayaran dan refund sesi per metode. Expected = penjualan + tip - refund,
+// ditambah modal awal untuk cash. Cash selalu ada walau belum ada transaksi karena modal awal ada di laci.
+func (r *CashDrawerRepository) MethodTotals(ctx context.Context, session *models.CashDrawerSession) ([]models.DrawerMethodTotal, error) {
+	return drawerMethodTotals(ctx, r.db, session)
+}
+
+func drawerMethodTotals(ctx context.Context, q queryer, session *models.CashDrawerSession) ([]models.DrawerMethodTotal, error) {
+	rows, err := q.QueryContext(ctx, `
+		WITH p AS (
+			SELECT payment_method AS method, COUNT(*) AS n, SUM(amount) AS sales, SUM(tip_amount) AS tips
+			FROM bill_payments
+			WHERE drawer_session_id = $1
+			GROUP BY payment_method
+		), f AS (
+			SELECT refund_method AS method, SUM(amount) AS refunds
+			FROM bill_refunds
+			WHERE drawer_session_id = $1
+			GROUP BY refund_method
+		)
+		SELECT COALESCE(p.method, f.method), COALESCE(p.n, 0),
+			COALESCE(p.sales, 0), COALESCE(p.tips, 0), COALESCE(f.refunds, 0)
+		FROM p
+		FULL JOIN f ON f.method = p.method
+	`, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.DrawerMethodTotal{}
+	hasCash := false
+	for rows.Next() {
+		var t models.DrawerMethodTotal
+		if err := rows.Scan(&t.PaymentMethod, &t.Payments, &t.Sales, &t.Tips, &t.Refunds); err != nil {
+			return nil, err
+		}
+		t.Expected = t.Sales + t.Tips - t.Refunds
+		if t.PaymentMethod == models.PaymentCash {
+			t.Expected += session.OpeningFloat
+			hasCash = true
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !hasCash {
+		totals = append(totals, models.DrawerMethodTotal{PaymentMethod: models.PaymentCash, Expected: session.OpeningFloat})
+	}
+
+	// Cash di urutan pertama, sisanya alfabetis
+	sort.Slice(totals, func(i, j int) bool {
+		if (totals[i].PaymentMethod == models.PaymentCash) != (totals[j].PaymentMethod == models.PaymentCash) {
+			return totals[i].PaymentMethod == models.PaymentCash
+		}
+		return totals[i].PaymentMethod < totals[j].PaymentMethod
+	})
+	return totals, nil
+}
+
+// Counts mengambil hasil hitung buta sesi yang sudah ditutup
+func (r *CashDrawerRepository) Counts(ctx context.Context, sessionID int) (map[string]money.Amount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT payment_method, counted_amount FROM cash_drawer_counts WHERE session_id = $1
+	`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]money.Amount{}
+	for rows.Next() {
+		var (
+			method string
+			amount money.Amount
+		)
+		if err := rows.Scan(&method, &amount); err != nil {
+			return nil, err
+		}
+		counts[method] = amount
+	}
+	return counts, rows.Err()
+}
+
+// Close menutup sesi dengan hasil hitung buta. Metode yang bisa dihitung tetapi tidak diisi kasir dicatat 0
+// sehingga selisihnya terlihat di laporan Z. Sesi dikunci agar tidak ada pembayaran yang masuk saat ditutup.
+func (r *CashDrawerRepository) Close(ctx context.Context, sessionID int, counts []models.DrawerCount, closedBy int, notes string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	session, err := scanDrawerSession(tx.QueryRowContext(ctx, `
+		SELECT `+drawerSessionColumns+`
+		FROM cash_drawer_sessions s
+		JOIN staff st ON st.id = s.cashier_id
+		WHERE s.id = $1
+		FOR UPDATE OF s
+	`, sessionID))
+	if err != nil {
+		return fmt.Errorf("sesi laci kas %d: %w", sessionID, err)
+	}
+	if session.Status != models.DrawerOpen {
+		return fmt.Errorf("%w: sesi %d", ErrDrawerClosed, sessionID)
+	}
+
+	counted := map[string]money.Amount{}
+	for _, c := range counts {
+		counted[c.PaymentMethod] += c.Amount
+	}
+	totals, err := drawerMethodTotals(ctx, tx, session)
+	if err != nil {
+		return err
+	}
+	for _, t := range totals {
+		if _, ok := counted[t.PaymentMethod]; !ok && models.DrawerCountable(t.PaymentMethod) {
+			counted[t.PaymentMethod] = 0
+		}
+	}
+
+	for method, amount := range counted {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO cash_drawer_counts (session_id, payment_method, counted_amount) VALUES ($1, $2, $3)
+		`, sessionID, method, amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE cash_drawer_sessions
+		SET status = 'closed', closed_at = NOW(), closed_by = $2, notes = $3
+		WHERE id = $1
+	`, sessionID, sql.NullInt64{Int64: int64(closedBy), Valid: closedBy != 0}, sql.NullString{String: notes, Valid: notes != ""})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// drawerSessionFor mencari sesi laci kas terbuka milik staf di outlet bill. Pembayaran dan refund cash wajib
+// lewat laci; metode lain tetap dicatat ke sesi jika staf sedang membuka laci agar ikut di laporan shift.
+// Sesi dikunci FOR SHARE sehingga tidak bisa ditutup sebelum transaksi pembayaran selesai.
+func drawerSessionFor(ctx context.Context, tx *sql.Tx, billID, staffID int, method string) (sql.NullInt64, error) {
+	var id sql.NullInt64
+	err := tx.QueryRowContext(ctx, `
+		SELECT s.id
+		FROM cash_drawer_sessions s
+		JOIN orders o ON o.outlet_id = s.outlet_id
+		JOIN bills b ON b.order_id = o.id
+		WHERE b.id = $1 AND s.cashier_id = $2 AND s.status = 'open'
+		FOR SHARE OF s
+	`, billID, staffID).Scan(&id)
+	if err == sql.ErrNoRows {
+		if method == models.PaymentCash {
+			return id, fmt.Errorf("%w: buka laci kas di outlet bill %d sebelum menerima atau mengembalikan cash", ErrNoDrawerSession, billID)
+		}
+		return id, nil
+	}
+	return id, err
+}
@@ -34,7 +34,7 @@ func (r *MenuCategoryRepository) Create(ctx context.Context, item *models.MenuCa
 }
 
 func (r *MenuCategoryRepository) List(ctx context.Context) ([]*models.MenuCategory, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, name FROM menu_categories WHERE deleted_at IS NULL ORDER BY name`)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, station_id FROM menu_categories WHERE deleted_at IS NULL ORDER BY name`)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (r *MenuCategoryRepository) List(ctx context.Context) ([]*models.MenuCatego
 	var categories []*models.MenuCategory
 	for rows.Next() {
 		var c models.MenuCategory
-		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.StationID); err != nil {
 			return nil, err
 		}
 		categories = append(categories, &c)
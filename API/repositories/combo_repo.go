@@ -0,0 +1,349 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrComboUnavailable   = errors.New("paket tidak aktif atau sudah dihapus")
+	ErrInvalidComboChoice = errors.New("pilihan paket tidak valid")
+)
+
+type ComboRepository struct {
+	db *sql.DB
+}
+
+func NewComboRepository(db *sql.DB) *ComboRepository {
+	return &ComboRepository{db: db}
+}
+
+func (r *ComboRepository) Create(ctx context.Context, combo *models.Combo) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO combos (sku, name, description, price, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, combo.SKU, combo.Name, combo.Description, combo.Price, combo.IsActive).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertComboSlots(ctx, tx, id, combo.Slots); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// Update mengganti data paket beserta seluruh slot-nya. Aman karena order hanya mereferensikan combo, bukan slot.
+func (r *ComboRepository) Update(ctx context.Context, combo *models.Combo) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE combos SET
+			sku = $1, name = $2, description = $3, price = $4, is_active = $5, updated_at = NOW()
+		WHERE id = $6 AND deleted_at IS NULL
+	`, combo.SKU, combo.Name, combo.Description, combo.Price, combo.IsActive, combo.ID)
+	if err := requireAffected(res, err); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM combo_slots WHERE combo_id = $1`, combo.ID); err != nil {
+		return err
+	}
+	if err := insertComboSlots(ctx, tx, combo.ID, combo.Slots); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ComboRepository) SoftDelete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE combos SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+	return requireAffected(res, err)
+}
+
+func (r *ComboRepository) List(ctx context.Context, activeOnly bool) ([]*models.Combo, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, sku, name, description, price, is_active, created_at, updated_at
+		FROM combos
+		WHERE deleted_at IS NULL AND (NOT $1 OR is_active = TRUE)
+		ORDER BY name
+	`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	combos := []*models.Combo{}
+	byID := map[int]*models.Combo{}
+	var ids []int
+	for rows.Next() {
+		var c models.Combo
+		err := rows.Scan(&c.ID, &c.SKU, &c.Name, &c.Description, &c.Price, &c.IsActive, &c.CreatedAt, &c.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		c.Slots = []models.ComboSlot{}
+		combos = append(combos, &c)
+		byID[c.ID] = &c
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadSlots(ctx, ids, byID); err != nil {
+		return nil, err
+	}
+	return combos, nil
+}
+
+func (r *ComboRepository) GetByID(ctx context.Context, id int) (*models.Combo, error) {
+	var c models.Combo
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, sku, name, description, price, is_active, created_at, updated_at
+		FROM combos
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&c.ID, &c.SKU, &c.Name, &c.Description, &c.Price, &c.IsActive, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Slots = []models.ComboSlot{}
+	if err := r.loadSlots(ctx, []int{c.ID}, map[int]*models.Combo{c.ID: &c}); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ComboRepository) loadSlots(ctx context.Context, comboIDs []int, byID map[int]*models.Combo) error {
+	if len(comboIDs) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.combo_id, s.id, s.name,
+			o.id, o.menu_item_id, mi.name, o.price_delta, o.is_default
+		FROM combo_slots s
+		LEFT JOIN combo_slot_options o ON o.slot_id = s.id
+		LEFT JOIN menu_items mi ON mi.id = o.menu_item_id
+		WHERE s.combo_id = ANY($1)
+		ORDER BY s.combo_id, s.sort_order, s.id, o.id
+	`, pq.Array(comboIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			comboID    int
+			slot       models.ComboSlot
+			optID      sql.NullInt64
+			menuItemID sql.NullInt64
+			menuName   sql.NullString
+			priceDelta money.Amount
+			isDefault  sql.NullBool
+		)
+		err := rows.Scan(&comboID, &slot.ID, &slot.Name,
+			&optID, &menuItemID, &menuName, &priceDelta, &isDefault)
+		if err != nil {
+			return err
+		}
+
+		combo := byID[comboID]
+		if n := len(combo.Slots); n == 0 || combo.Slots[n-1].ID != slot.ID {
+			slot.ComboID = comboID
+			slot.Options = []models.ComboSlotOption{}
+			combo.Slots = append(combo.Slots, slot)
+		}
+		if optID.Valid {
+			last := &combo.Slots[len(combo.Slots)-1]
+			last.Options = append(last.Options, models.ComboSlotOption{
+				ID:         int(optID.Int64),
+				SlotID:     last.ID,
+				MenuItemID: int(menuItemID.Int64),
+				MenuName:   menuName.String,
+				PriceDelta: priceDelta,
+				IsDefault:  isDefault.Bool,
+			})
+		}
+	}
+	return rows.Err()
+}
+
+func insertComboSlots(ctx context.Context, tx *sql.Tx, comboID int, slots []models.ComboSlot) error {
+	for i, slot := range slots {
+		var slotID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO combo_slots (combo_id, name, sort_order) VALUES ($1, $2, $3) RETURNING id
+		`, comboID, slot.Name, i).Scan(&slotID)
+		if err != nil {
+			return err
+		}
+
+		for _, opt := range slot.Options {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO combo_slot_options (slot_id, menu_item_id, price_delta, is_default)
+				VALUES ($1, $2, $3, $4)
+			`, slotID, opt.MenuItemID, opt.PriceDelta, opt.IsDefault)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// comboComponent adalah satu menu hasil pilihan slot paket
+type comboComponent struct {
+	selection  models.ComboSelection
+	listPrice  money.Amount // menu_items.price, dipakai sebagai bobot alokasi
+	priceDelta money.Amount
+}
+
+// resolveCombo memvalidasi paket dan pilihan tiap slot, lalu mengembalikan nama, harga paket (termasuk upcharge)
+// dan komponennya. Slot tanpa pilihan memakai option default atau satu-satunya option.
+func resolveCombo(ctx context.Context, tx *sql.Tx, comboID int, selections []models.ComboSelection) (string, money.Amount, []comboComponent, error) {
+	var (
+		name  string
+		price money.Amount
+	)
+	err := tx.QueryRowContext(ctx, `
+		SELECT name, price FROM combos WHERE id = $1 AND is_active = TRUE AND deleted_at IS NULL
+	`, comboID).Scan(&name, &price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, nil, fmt.Errorf("%w: paket %d", ErrComboUnavailable, comboID)
+	}
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.id, s.name, o.menu_item_id, o.price_delta, o.is_default, mi.price,
+			(mi.is_active = TRUE AND mi.deleted_at IS NULL)
+		FROM combo_slots s
+		JOIN combo_slot_options o ON o.slot_id = s.id
+		JOIN menu_items mi ON mi.id = o.menu_item_id
+		WHERE s.combo_id = $1
+		ORDER BY s.sort_order, s.id, o.id
+	`, comboID)
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	type slotOption struct {
+		menuItemID int
+		priceDelta money.Amount
+		isDefault  bool
+		listPrice  money.Amount
+		available  bool
+	}
+	type slotInfo struct {
+		id      int
+		name    string
+		options []slotOption
+	}
+	var slots []*slotInfo
+	for rows.Next() {
+		var (
+			slotID   int
+			slotName string
+			opt      slotOption
+		)
+		err := rows.Scan(&slotID, &slotName, &opt.menuItemID, &opt.priceDelta, &opt.isDefault, &opt.listPrice, &opt.available)
+		if err != nil {
+			rows.Close()
+			return "", 0, nil, err
+		}
+		if len(slots) == 0 || slots[len(slots)-1].id != slotID {
+			slots = append(slots, &slotInfo{id: slotID, name: slotName})
+		}
+		last := slots[len(slots)-1]
+		last.options = append(last.options, opt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", 0, nil, err
+	}
+	if len(slots) == 0 {
+		return "", 0, nil, fmt.Errorf("%w: paket %d belum punya isi", ErrComboUnavailable, comboID)
+	}
+
+	chosen := map[int]models.ComboSelection{}
+	for _, sel := range selections {
+		if _, dup := chosen[sel.SlotID]; dup {
+			return "", 0, nil, fmt.Errorf("%w: slot %d dipilih lebih dari sekali", ErrInvalidComboChoice, sel.SlotID)
+		}
+		chosen[sel.SlotID] = sel
+	}
+
+	var components []comboComponent
+	for _, slot := range slots {
+		sel, ok := chosen[slot.id]
+		delete(chosen, slot.id)
+
+		var picked *slotOption
+		for i := range slot.options {
+			opt := &slot.options[i]
+			if ok && opt.menuItemID == sel.MenuItemID || !ok && (opt.isDefault || len(slot.options) == 1) {
+				picked = opt
+				break
+			}
+		}
+		if picked == nil {
+			return "", 0, nil, fmt.Errorf("%w: pilihan untuk slot %s tidak tersedia", ErrInvalidComboChoice, slot.name)
+		}
+		if !picked.available {
+			return "", 0, nil, fmt.Errorf("%w: menu %d di slot %s", ErrMenuUnavailable, picked.menuItemID, slot.name)
+		}
+
+		sel.SlotID = slot.id
+		sel.MenuItemID = picked.menuItemID
+		components = append(components, comboComponent{
+			selection:  sel,
+			listPrice:  picked.listPrice,
+			priceDelta: picked.priceDelta,
+		})
+		price += picked.priceDelta
+	}
+	if len(chosen) > 0 {
+		return "", 0, nil, fmt.Errorf("%w: ada slot yang bukan milik paket ini", ErrInvalidComboChoice)
+	}
+	if price < 0 {
+		return "", 0, nil, fmt.Errorf("%w: harga paket %s setelah upcharge menjadi negatif", ErrInvalidComboChoice, name)
+	}
+
+	return name, price, components, nil
+}
+
+// allocateComboPrice membagi harga paket ke komponen secara proporsional terhadap harga normal + upcharge.
+// Pembagian dalam sen sehingga jumlah alokasinya tepat sama dengan harga paket. Komponen yang harga + upcharge-nya
+// negatif diberi bobot nol agar tidak ada komponen yang alokasinya negatif.
+func allocateComboPrice(total money.Amount, components []comboComponent) []money.Amount {
+	weights := make([]money.Amount, len(components))
+	for i, c := range components {
+		weights[i] = max(c.listPrice+c.priceDelta, 0)
+	}
+	return money.Allocate(total, weights)
+}
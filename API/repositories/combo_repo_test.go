@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"slices"
+	"testing"
+
+	"pos-restaurant/money"
+)
+
+func TestAllocateComboPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      money.Amount
+		components []comboComponent
+		want       []money.Amount
+	}{
+		{
+			name:  "proporsional terhadap harga normal",
+			total: money.New(60000, 0),
+			components: []comboComponent{
+				{listPrice: money.New(50000, 0)},
+				{listPrice: money.New(25000, 0)},
+			},
+			want: []money.Amount{money.New(40000, 0), money.New(20000, 0)},
+		},
+		{
+			name:  "upcharge ikut menjadi bobot",
+			total: money.New(70000, 0),
+			components: []comboComponent{
+				{listPrice: money.New(50000, 0)},
+				{listPrice: money.New(15000, 0), priceDelta: money.New(5000, 0)},
+			},
+			want: []money.Amount{money.New(50000, 0), money.New(20000, 0)},
+		},
+		{
+			name:  "sisa sen dibagikan tanpa selisih",
+			total: money.New(100, 0),
+			components: []comboComponent{
+				{listPrice: money.New(10, 0)},
+				{listPrice: money.New(10, 0)},
+				{listPrice: money.New(10, 0)},
+			},
+			want: []money.Amount{money.New(33, 34), money.New(33, 33), money.New(33, 33)},
+		},
+		{
+			name:  "komponen dengan harga negatif diberi bobot nol",
+			total: money.New(30000, 0),
+			components: []comboComponent{
+				{listPrice: money.New(30000, 0)},
+				{listPrice: money.New(5000, 0), priceDelta: -money.New(8000, 0)},
+			},
+			want: []money.Amount{money.New(30000, 0), 0},
+		},
+		{
+			name:  "semua bobot nol dibagi rata",
+			total: money.New(10, 0),
+			components: []comboComponent{
+				{},
+				{},
+			},
+			want: []money.Amount{money.New(5, 0), money.New(5, 0)},
+		},
+	}
+	for _, tt := range tests {
+		got := allocateComboPrice(tt.total, tt.components)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: allocateComboPrice = %v, want %v", tt.name, got, tt.want)
+		}
+		var sum money.Amount
+		for _, a := range got {
+			sum += a
+		}
+		if sum != tt.total {
+			t.Errorf("%s: jumlah alokasi %s, want %s", tt.name, sum, tt.total)
+		}
+	}
+}
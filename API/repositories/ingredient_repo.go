@@ -14,18 +14,41 @@ func NewIngredientRepository(db *sql.DB) *IngredientRepository {
 	return &IngredientRepository{db: db}
 }
 
-func (r *IngredientRepository) Create(ctx context.Context, ing *models.Ingredient) (int, error) {
+func (r *IngredientRepository) Create(ctx context.Context, ing *models.Ingredient, staffID int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Stok awal dimasukkan lewat ledger, bukan langsung ke kolom qty
 	var id int
-	err := r.db.QueryRowContext(ctx, `
+	err = tx.QueryRowContext(ctx, `
 		INSERT INTO ingredients (name, qty, unit, is_allergen, is_active, description)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, 0, $2, $3, $4, $5)
 		RETURNING id`,
-		ing.Name, ing.Qty, ing.Unit, ing.IsAllergen, ing.IsActive, ing.Description,
+		ing.Name, ing.Unit, ing.IsAllergen, ing.IsActive, ing.Description,
 	).Scan(&id)
-
 	if err != nil {
 		return 0, err
 	}
+
+	if ing.Qty != 0 {
+		err = applyStockMovement(ctx, tx, &models.StockMovement{
+			IngredientID: id,
+			MovementType: models.StockAdjustment,
+			QtyChange:    ing.Qty,
+			StaffID:      sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+			Notes:        sql.NullString{String: "Stok awal", Valid: true},
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 	return id, nil
 }
 
@@ -81,19 +104,49 @@ func (r *IngredientRepository) GetByID(ctx context.Context, id int) (*models.Ing
 	return &ingredient, nil
 }
 
-func (r *IngredientRepository) Update(ctx context.Context, ing *models.Ingredient) error {
-	_, err := r.db.ExecContext(ctx, `
+func (r *IngredientRepository) Update(ctx context.Context, ing *models.Ingredient, staffID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var currentQty float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT qty FROM ingredients WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, ing.ID).Scan(&currentQty)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE ingredients SET 
 			name = $1,
-			qty = $2,
-			unit = $3,
-			is_allergen = $4,
-			is_active = $5,
-			description = $6
-		WHERE id = $7 AND deleted_at IS NULL
-	`, ing.Name, ing.Qty, ing.Unit, ing.IsAllergen, ing.IsActive, ing.Description, ing.ID)
+			unit = $2,
+			is_allergen = $3,
+			is_active = $4,
+			description = $5
+		WHERE id = $6 AND deleted_at IS NULL
+	`, ing.Name, ing.Unit, ing.IsAllergen, ing.IsActive, ing.Description, ing.ID)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Perubahan qty dicatat sebagai manual adjustment
+	if delta := ing.Qty - currentQty; delta != 0 {
+		err = applyStockMovement(ctx, tx, &models.StockMovement{
+			IngredientID: ing.ID,
+			MovementType: models.StockAdjustment,
+			QtyChange:    delta,
+			StaffID:      sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+			Notes:        sql.NullString{String: "Update data ingredient", Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *IngredientRepository) SoftDelete(ctx context.Context, id int) error {
@@ -0,0 +1,530 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+	"pos-restaurant/receipt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAlreadyServed  = errors.New("item sudah served")
+	ErrNoPrinter      = errors.New("station tiket tidak punya printer")
+	ErrJobNotFailed   = errors.New("job cetak tidak dalam status failed")
+	ErrPrinterInvalid = errors.New("printer station tidak valid")
+	ErrTicketHeld     = errors.New("tiket masih ditahan, fire terlebih dahulu")
+	ErrItemVoided     = errors.New("item atau order sudah di-void")
+)
+
+type KitchenRepository struct {
+	db *sql.DB
+}
+
+func NewKitchenRepository(db *sql.DB) *KitchenRepository {
+	return &KitchenRepository{db: db}
+}
+
+func (r *KitchenRepository) CreateStation(ctx context.Context, station *models.KitchenStation) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO kitchen_stations (name, printer, printer_width)
+		VALUES ($1, NULLIF($2, ''), COALESCE(NULLIF($3, 0), 42))
+		RETURNING id
+	`, station.Name, station.Printer, station.PrinterWidth).Scan(&id)
+	return id, err
+}
+
+func (r *KitchenRepository) ListStations(ctx context.Context) ([]*models.KitchenStation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, COALESCE(printer, ''), printer_width, created_at
+		FROM kitchen_stations WHERE deleted_at IS NULL ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []*models.KitchenStation
+	for rows.Next() {
+		var s models.KitchenStation
+		if err := rows.Scan(&s.ID, &s.Name, &s.Printer, &s.PrinterWidth, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		stations = append(stations, &s)
+	}
+	return stations, rows.Err()
+}
+
+// AssignCategories mengarahkan tiket dari kategori menu tertentu ke station ini
+func (r *KitchenRepository) AssignCategories(ctx context.Context, stationID int, categoryIDs []int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE menu_categories SET station_id = $1
+		WHERE id = ANY($2) AND deleted_at IS NULL
+	`, stationID, pq.Array(categoryIDs))
+	return err
+}
+
+// Queue mengambil item yang sudah di-fire dan belum served. Tiket yang paling lama di-fire didahulukan, dan dalam satu tiket
+// item dengan preparation_time terlama dimulai lebih dulu agar selesai bersamaan.
+func (r *KitchenRepository) Queue(ctx context.Context, stationID, outletID int) ([]*models.KitchenQueueItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			kti.id, kt.id, o.id, o.order_number, COALESCE(t.table_number, ''),
+			kt.station_id, COALESCE(ks.name, ''),
+			oi.id, mi.id, mi.name, oi.qty, COALESCE(oi.notes, ''),
+			ARRAY(
+				SELECT i.name
+				FROM order_item_ingredient_excluded e
+				JOIN ingredients i ON i.id = e.ingredient_id
+				WHERE e.order_item_id = oi.id
+				ORDER BY i.name
+			),
+			mi.preparation_time, kti.status, COALESCE(kt.course, ''), kt.created_at, kt.fired_at, kti.started_at, kti.ready_at
+		FROM kitchen_ticket_items kti
+		JOIN kitchen_tickets kt ON kt.id = kti.ticket_id
+		JOIN orders o ON o.id = kt.order_id
+		JOIN order_items oi ON oi.id = kti.order_item_id
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN "tables" t ON t.id = o.table_id
+		LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+		WHERE kti.status <> 'served'
+			AND kt.fired_at IS NOT NULL
+			AND oi.voided_at IS NULL
+			AND o.status <> 'void'
+			AND ($1 = 0 OR kt.station_id = $1)
+			AND ($2 = 0 OR o.outlet_id = $2)
+		ORDER BY kt.fired_at, COALESCE(mi.preparation_time, 0) DESC, kti.id
+	`, stationID, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queue []*models.KitchenQueueItem
+	for rows.Next() {
+		var q models.KitchenQueueItem
+		err := rows.Scan(
+			&q.TicketItemID, &q.TicketID, &q.OrderID, &q.OrderNumber, &q.TableNumber,
+			&q.StationID, &q.StationName,
+			&q.OrderItemID, &q.MenuItemID, &q.MenuName, &q.Qty, &q.Notes,
+			pq.Array(&q.ExcludedIngredients),
+			&q.PreparationTime, &q.Status, &q.Course, &q.OrderedAt, &q.FiredAt, &q.StartedAt, &q.ReadyAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if q.ExcludedIngredients == nil {
+			q.ExcludedIngredients = []string{}
+		}
+		queue = append(queue, &q)
+	}
+	return queue, rows.Err()
+}
+
+// Bump memajukan status item: queued -> cooking -> ready -> served.
+// outlet_id order ikut dikembalikan untuk keperluan event.
+func (r *KitchenRepository) Bump(ctx context.Context, ticketItemID int) (status string, outletID int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback()
+
+	// Item yang tidak tampil di antrian (tiket ditahan, item/order void) tidak boleh dimajukan lewat ID lama
+	var held, voided bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT kti.status, o.outlet_id, kt.fired_at IS NULL, oi.voided_at IS NOT NULL OR o.status = 'void'
+		FROM kitchen_ticket_items kti
+		JOIN kitchen_tickets kt ON kt.id = kti.ticket_id
+		JOIN order_items oi ON oi.id = kti.order_item_id
+		JOIN orders o ON o.id = kt.order_id
+		WHERE kti.id = $1
+		FOR UPDATE OF kti
+	`, ticketItemID).Scan(&status, &outletID, &held, &voided)
+	if err != nil {
+		return "", 0, err
+	}
+	switch {
+	case voided:
+		return "", 0, ErrItemVoided
+	case held:
+		return "", 0, ErrTicketHeld
+	}
+
+	next, ok := models.NextKitchenStatus(status)
+	if !ok {
+		return "", 0, ErrAlreadyServed
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE kitchen_ticket_items SET
+			status = $1,
+			started_at = CASE WHEN $1 = 'cooking' THEN NOW() ELSE started_at END,
+			ready_at = CASE WHEN $1 = 'ready' THEN NOW() ELSE ready_at END,
+			served_at = CASE WHEN $1 = 'served' THEN NOW() ELSE served_at END
+		WHERE id = $2
+	`, next, ticketItemID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if next == "served" {
+		if err := markCourseServed(ctx, tx, ticketItemID); err != nil {
+			return "", 0, err
+		}
+	}
+
+	return next, outletID, tx.Commit()
+}
+
+// SetStationPrinter mengatur printer tiket station, printer kosong = station hanya memakai KDS
+func (r *KitchenRepository) SetStationPrinter(ctx context.Context, stationID int, printer string, width int) error {
+	if width == 0 {
+		width = receipt.DefaultWidth
+	}
+	if width < receipt.MinWidth || width > receipt.MaxWidth {
+		return fmt.Errorf("%w: lebar kertas harus %d-%d karakter", ErrPrinterInvalid, receipt.MinWidth, receipt.MaxWidth)
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE kitchen_stations SET printer = NULLIF($2, ''), printer_width = $3
+		WHERE id = $1 AND deleted_at IS NULL
+	`, stationID, printer, width)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("station %d: %w", stationID, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// ClaimPrintJobs mengambil job yang sudah waktunya dicetak dan menandainya printing.
+// Job printing yang tertinggal lebih dari 2 menit (server mati saat mencetak) diambil ulang.
+func (r *KitchenRepository) ClaimPrintJobs(ctx context.Context, limit int) ([]*models.KitchenPrintJob, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE kitchen_print_jobs SET status = 'printing', attempts = attempts + 1, claimed_at = NOW()
+		WHERE id IN (
+			SELECT id FROM kitchen_print_jobs
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+				OR (status = 'printing' AND claimed_at < NOW() - INTERVAL '2 minutes')
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, ticket_id, printer, reprint, status, attempts, next_attempt_at, created_at
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.KitchenPrintJob
+	for rows.Next() {
+		var j models.KitchenPrintJob
+		err := rows.Scan(&j.ID, &j.TicketID, &j.Printer, &j.Reprint, &j.Status, &j.Attempts, &j.NextAttemptAt, &j.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &j)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].ID < jobs[b].ID })
+	return jobs, rows.Err()
+}
+
+func (r *KitchenRepository) CompletePrintJob(ctx context.Context, jobID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE kitchen_print_jobs SET status = 'printed', printed_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`, jobID)
+	return err
+}
+
+// FailPrintJob mencatat kegagalan cetak. Job dijadwalkan ulang retryIn setelah NOW() database (jam yang sama
+// dengan klaim job), atau menjadi failed jika final. outlet_id dikembalikan untuk event notifikasi ke POS.
+func (r *KitchenRepository) FailPrintJob(ctx context.Context, jobID int, reason string, retryIn time.Duration, final bool) (int, error) {
+	var outletID int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE kitchen_print_jobs j SET
+			status = CASE WHEN $4 THEN 'failed' ELSE 'pending' END,
+			last_error = $2,
+			next_attempt_at = NOW() + make_interval(secs => $3)
+		FROM kitchen_tickets kt
+		JOIN orders o ON o.id = kt.order_id
+		WHERE j.id = $1 AND kt.id = j.ticket_id
+		RETURNING o.outlet_id
+	`, jobID, reason, retryIn.Seconds(), final).Scan(&outletID)
+	return outletID, err
+}
+
+// ListPrintJobs menampilkan 200 job cetak terbaru, status kosong = semua status
+func (r *KitchenRepository) ListPrintJobs(ctx context.Context, status string, outletID int) ([]*models.KitchenPrintJob, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT j.id, j.ticket_id, kt.order_id, COALESCE(ks.name, ''), j.printer, j.reprint, j.status,
+			j.attempts, j.last_error, j.next_attempt_at, j.printed_at, j.created_at
+		FROM kitchen_print_jobs j
+		JOIN kitchen_tickets kt ON kt.id = j.ticket_id
+		JOIN orders o ON o.id = kt.order_id
+		LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+		WHERE ($1 = '' OR j.status = $1)
+			AND ($2 = 0 OR o.outlet_id = $2)
+		ORDER BY j.id DESC
+		LIMIT 200
+	`, status, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*models.KitchenPrintJob{}
+	for rows.Next() {
+		var j models.KitchenPrintJob
+		err := rows.Scan(&j.ID, &j.TicketID, &j.OrderID, &j.StationName, &j.Printer, &j.Reprint, &j.Status,
+			&j.Attempts, &j.LastError, &j.NextAttemptAt, &j.PrintedAt, &j.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &j)
+	}
+	return jobs, rows.Err()
+}
+
+// RetryPrintJob mengantrikan ulang job yang sudah failed, misalnya setelah kertas printer diganti
+func (r *KitchenRepository) RetryPrintJob(ctx context.Context, jobID int) error {
+	var status string
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM kitchen_print_jobs WHERE id = $1`, jobID).Scan(&status)
+	if err != nil {
+		return fmt.Errorf("job cetak %d: %w", jobID, err)
+	}
+	if status != models.PrintFailed {
+		return fmt.Errorf("%w: status %s", ErrJobNotFailed, status)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE kitchen_print_jobs SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = 'failed'
+	`, jobID)
+	return err
+}
+
+// ReprintTicket mengantrikan cetak ulang tiket, ditandai CETAK ULANG di kertas
+func (r *KitchenRepository) ReprintTicket(ctx context.Context, ticketID int) error {
+	var fired sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT fired_at FROM kitchen_tickets WHERE id = $1`, ticketID).Scan(&fired)
+	if err != nil {
+		return fmt.Errorf("tiket %d: %w", ticketID, err)
+	}
+	if !fired.Valid {
+		return fmt.Errorf("%w: tiket %d", ErrTicketHeld, ticketID)
+	}
+
+	ok, err := enqueueTicketPrint(ctx, r.db, ticketID, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: tiket %d", ErrNoPrinter, ticketID)
+	}
+	return nil
+}
+
+// LoadTicketPrint mengambil isi tiket dapur untuk dicetak beserta lebar kertas printer station-nya.
+// Item yang sudah di-void tidak ikut dicetak.
+func (r *KitchenRepository) LoadTicketPrint(ctx context.Context, ticketID int) (*receipt.KitchenTicket, int, error) {
+	var (
+		t       receipt.KitchenTicket
+		width   int
+		station sql.NullString
+		table   sql.NullString
+		room    sql.NullString
+		waiter  sql.NullString
+		fired   sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT kt.id, ks.name, COALESCE(ks.printer_width, 42), o.order_number, o.order_type,
+			t.table_number, o.hotel_room, w.name, COALESCE(kt.course, ''), kt.fired_at
+		FROM kitchen_tickets kt
+		JOIN orders o ON o.id = kt.order_id
+		LEFT JOIN kitchen_stations ks ON ks.id = kt.station_id
+		LEFT JOIN tables t ON t.id = o.table_id
+		LEFT JOIN staff w ON w.id = o.waiter_id
+		WHERE kt.id = $1
+	`, ticketID).Scan(&t.TicketID, &station, &width, &t.OrderNumber, &t.OrderType, &table, &room, &waiter, &t.Course, &fired)
+	if err != nil {
+		return nil, 0, fmt.Errorf("tiket %d: %w", ticketID, err)
+	}
+	t.StationName = station.String
+	t.TableNumber = table.String
+	t.HotelRoom = room.String
+	t.Waiter = waiter.String
+	t.FiredAt = fired.Time
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT mi.name, oi.qty, COALESCE(oi.seat_no, 0), COALESCE(oc.name, ''), COALESCE(oi.notes, ''),
+			ARRAY(SELECT m.name FROM order_item_modifiers m WHERE m.order_item_id = oi.id ORDER BY m.id),
+			ARRAY(SELECT i.name FROM order_item_ingredient_excluded e
+				JOIN ingredients i ON i.id = e.ingredient_id
+				WHERE e.order_item_id = oi.id ORDER BY i.name)
+		FROM kitchen_ticket_items kti
+		JOIN order_items oi ON oi.id = kti.order_item_id
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN order_combos oc ON oc.id = oi.order_combo_id
+		WHERE kti.ticket_id = $1 AND oi.voided_at IS NULL
+		ORDER BY kti.id
+	`, ticketID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var it receipt.KitchenItem
+		err := rows.Scan(&it.Name, &it.Qty, &it.SeatNo, &it.Combo, &it.Notes,
+			pq.Array(&it.Modifiers), pq.Array(&it.Excluded))
+		if err != nil {
+			return nil, 0, err
+		}
+		t.Items = append(t.Items, it)
+	}
+	return &t, width, rows.Err()
+}
+
+// createKitchenTickets membuat tiket dapur untuk item yang baru masuk, dipisah per station sesuai kategori menu
+// dan per course agar tiap course bisa di-fire sendiri.
+// Tiket yang tidak ditahan langsung masuk antrian KDS dan antrian cetak station-nya (jika station punya printer),
+// dalam transaksi yang sama dengan order sehingga tiket kertas tidak hilang bila server mati setelah commit.
+func createKitchenTickets(ctx context.Context, tx *sql.Tx, orderID int, orderItemIDs []int, held bool) error {
+	if len(orderItemIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT oi.id, mc.station_id, COALESCE(oi.course, '')
+		FROM order_items oi
+		JOIN menu_items mi ON mi.id = oi.menu_item_id
+		LEFT JOIN menu_categories mc ON mc.id = mi.category_id
+		WHERE oi.id = ANY($1)
+		ORDER BY oi.id
+	`, pq.Array(orderItemIDs))
+	if err != nil {
+		return err
+	}
+
+	// Station 0 dipakai untuk item tanpa station, course kosong untuk item tanpa course
+	type ticketKey struct {
+		stationID int64
+		course    string
+	}
+	var ticketOrder []ticketKey
+	itemsByTicket := map[ticketKey][]int{}
+	for rows.Next() {
+		var itemID int
+		var stationID sql.NullInt64
+		var course string
+		if err := rows.Scan(&itemID, &stationID, &course); err != nil {
+			rows.Close()
+			return err
+		}
+		key := ticketKey{stationID: stationID.Int64, course: course}
+		if _, ok := itemsByTicket[key]; !ok {
+			ticketOrder = append(ticketOrder, key)
+		}
+		itemsByTicket[key] = append(itemsByTicket[key], itemID)
+	}
+	rows.Close()
+
+	for _, key := range ticketOrder {
+		var ticketID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO kitchen_tickets (order_id, station_id, course, fired_at)
+			VALUES ($1, $2, NULLIF($3, ''), CASE WHEN $4 THEN NULL ELSE NOW() END)
+			RETURNING id
+		`, orderID, sql.NullInt64{Int64: key.stationID, Valid: key.stationID != 0}, key.course, held).Scan(&ticketID)
+		if err != nil {
+			return err
+		}
+
+		for _, itemID := range itemsByTicket[key] {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO kitchen_ticket_items (ticket_id, order_item_id) VALUES ($1, $2)
+			`, ticketID, itemID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !held {
+			if _, err := enqueueTicketPrint(ctx, tx, ticketID, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fireKitchenTickets melepas tiket yang ditahan ke antrian dapur dan antrian cetak.
+// ticketIDs kosong berarti semua tiket order yang masih ditahan, course membatasi ke tiket course tersebut.
+// Course dari tiket yang di-fire ikut ditandai fired.
+func fireKitchenTickets(ctx context.Context, tx *sql.Tx, orderID int, course string, ticketIDs []int, staffID int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE kitchen_tickets SET fired_at = NOW(), fired_by = $3
+		WHERE order_id = $1 AND fired_at IS NULL
+			AND (COALESCE(cardinality($2::int[]), 0) = 0 OR id = ANY($2))
+			AND ($4 = '' OR course = $4)
+		RETURNING id
+	`, orderID, pq.Array(ticketIDs), sql.NullInt64{Int64: int64(staffID), Valid: staffID > 0}, course)
+	if err != nil {
+		return nil, err
+	}
+	var fired []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		fired = append(fired, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(fired) > 0 {
+		if err := markCoursesFired(ctx, tx, orderID, fired, staffID); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range fired {
+		if _, err := enqueueTicketPrint(ctx, tx, id, false); err != nil {
+			return nil, err
+		}
+	}
+	return fired, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// enqueueTicketPrint memasukkan tiket ke antrian cetak printer station-nya, false jika station tidak punya printer
+func enqueueTicketPrint(ctx context.Context, q execer, ticketID int, reprint bool) (bool, error) {
+	res, err := q.ExecContext(ctx, `
+		INSERT INTO kitchen_print_jobs (ticket_id, printer, reprint)
+		SELECT kt.id, ks.printer, $2
+		FROM kitchen_tickets kt
+		JOIN kitchen_stations ks ON ks.id = kt.station_id
+		WHERE kt.id = $1 AND ks.printer IS NOT NULL
+	`, ticketID, reprint)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
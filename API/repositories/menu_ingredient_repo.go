@@ -140,5 +140,10 @@ func (r *MenuItemRepository) GetMenuWithIngredients(ctx context.Context, id int)
 	}
 
 	menu.Ingredients = ingredients
+
+	menu.ModifierGroups, err = listModifierGroups(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
 	return menu, nil
 }
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidModifier = errors.New("pilihan modifier tidak valid")
+
+type ModifierRepository struct {
+	db *sql.DB
+}
+
+func NewModifierRepository(db *sql.DB) *ModifierRepository {
+	return &ModifierRepository{db: db}
+}
+
+func (r *ModifierRepository) CreateGroup(ctx context.Context, g *models.ModifierGroup) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO modifier_groups (menu_item_id, name, min_select, max_select)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, g.MenuItemID, g.Name, g.MinSelect, g.MaxSelect).Scan(&id)
+	return id, err
+}
+
+func (r *ModifierRepository) UpdateGroup(ctx context.Context, g *models.ModifierGroup) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE modifier_groups SET
+			name = $1, min_select = $2, max_select = $3, updated_at = NOW()
+		WHERE id = $4 AND deleted_at IS NULL
+	`, g.Name, g.MinSelect, g.MaxSelect, g.ID)
+	return requireAffected(res, err)
+}
+
+func (r *ModifierRepository) SoftDeleteGroup(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE modifier_groups SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+	return requireAffected(res, err)
+}
+
+func (r *ModifierRepository) CreateOption(ctx context.Context, o *models.ModifierOption) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO modifier_options (group_id, name, price_delta, ingredient_id, ingredient_qty, is_active)
+		SELECT id, $2, $3, $4, $5, $6 FROM modifier_groups WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id
+	`, o.GroupID, o.Name, o.PriceDelta, o.IngredientID, o.IngredientQty, o.IsActive).Scan(&id)
+	return id, err
+}
+
+func (r *ModifierRepository) UpdateOption(ctx context.Context, o *models.ModifierOption) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE modifier_options SET
+			name = $1, price_delta = $2, ingredient_id = $3, ingredient_qty = $4,
+			is_active = $5, updated_at = NOW()
+		WHERE id = $6 AND deleted_at IS NULL
+	`, o.Name, o.PriceDelta, o.IngredientID, o.IngredientQty, o.IsActive, o.ID)
+	return requireAffected(res, err)
+}
+
+// Option di-soft delete karena masih direferensikan order_item_modifiers
+func (r *ModifierRepository) SoftDeleteOption(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE modifier_options SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+	return requireAffected(res, err)
+}
+
+func (r *ModifierRepository) ListByMenuItem(ctx context.Context, menuItemID int) ([]models.ModifierGroup, error) {
+	return listModifierGroups(ctx, r.db, menuItemID)
+}
+
+func requireAffected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// listModifierGroups mengambil group beserta option yang belum dihapus, dipakai juga oleh detail menu
+func listModifierGroups(ctx context.Context, db *sql.DB, menuItemID int) ([]models.ModifierGroup, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT g.id, g.menu_item_id, g.name, g.min_select, g.max_select,
+			o.id, o.name, o.price_delta, o.ingredient_id, o.ingredient_qty, o.is_active
+		FROM modifier_groups g
+		LEFT JOIN modifier_options o ON o.group_id = g.id AND o.deleted_at IS NULL
+		WHERE g.menu_item_id = $1 AND g.deleted_at IS NULL
+		ORDER BY g.id, o.id
+	`, menuItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.ModifierGroup{}
+	for rows.Next() {
+		var (
+			g          models.ModifierGroup
+			optID      sql.NullInt64
+			optName    sql.NullString
+			priceDelta money.Amount // NULL (group tanpa option) terbaca 0
+			ingQty     sql.NullFloat64
+			isActive   sql.NullBool
+			o          models.ModifierOption
+		)
+		err := rows.Scan(
+			&g.ID, &g.MenuItemID, &g.Name, &g.MinSelect, &g.MaxSelect,
+			&optID, &optName, &priceDelta, &o.IngredientID, &ingQty, &isActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].ID != g.ID {
+			g.Options = []models.ModifierOption{}
+			groups = append(groups, g)
+		}
+		if optID.Valid {
+			o.ID = int(optID.Int64)
+			o.GroupID = g.ID
+			o.Name = optName.String
+			o.PriceDelta = priceDelta
+			o.IngredientQty = ingQty.Float64
+			o.IsActive = isActive.Bool
+			last := &groups[len(groups)-1]
+			last.Options = append(last.Options, o)
+		}
+	}
+	return groups, rows.Err()
+}
+
+// resolveModifiers memvalidasi pilihan modifier untuk satu menu item (option aktif, milik menu tsb,
+// jumlah pilihan per group sesuai min/max) lalu mengembalikan option terpilih.
+func resolveModifiers(ctx context.Context, tx *sql.Tx, menuItemID int, optionIDs []int) ([]models.ModifierOption, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT g.id, g.name, g.min_select, g.max_select,
+			o.id, o.name, o.price_delta, o.ingredient_id, o.ingredient_qty
+		FROM modifier_groups g
+		LEFT JOIN modifier_options o ON o.group_id = g.id
+			AND o.deleted_at IS NULL AND o.is_active = TRUE
+			AND o.id = ANY($2)
+		WHERE g.menu_item_id = $1 AND g.deleted_at IS NULL
+	`, menuItemID, pq.Array(optionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type groupRule struct {
+		name     string
+		min, max int
+		selected int
+	}
+	rules := map[int]*groupRule{}
+	var selected []models.ModifierOption
+
+	for rows.Next() {
+		var (
+			groupID    int
+			rule       groupRule
+			optID      sql.NullInt64
+			optName    sql.NullString
+			priceDelta money.Amount
+			ingQty     sql.NullFloat64
+			o          models.ModifierOption
+		)
+		err := rows.Scan(&groupID, &rule.name, &rule.min, &rule.max,
+			&optID, &optName, &priceDelta, &o.IngredientID, &ingQty)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := rules[groupID]; !ok {
+			rules[groupID] = &rule
+		}
+		if optID.Valid {
+			rules[groupID].selected++
+			o.ID = int(optID.Int64)
+			o.GroupID = groupID
+			o.Name = optName.String
+			o.PriceDelta = priceDelta
+			o.IngredientQty = ingQty.Float64
+			o.IsActive = true
+			selected = append(selected, o)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Option yang tidak ketemu berarti bukan milik menu ini, tidak aktif, sudah dihapus, atau dobel
+	if len(selected) != len(optionIDs) {
+		return nil, fmt.Errorf("%w: option tidak tersedia untuk menu %d", ErrInvalidModifier, menuItemID)
+	}
+	for _, rule := range rules {
+		if rule.selected < rule.min || rule.selected > rule.max {
+			return nil, fmt.Errorf("%w: %s harus dipilih %d-%d", ErrInvalidModifier, rule.name, rule.min, rule.max)
+		}
+	}
+	return selected, nil
+}
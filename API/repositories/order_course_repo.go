@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"pos-restaurant/models"
+
+	"github.com/lib/pq"
+)
+
+// holdLine menentukan apakah item baru ditahan di dapur. Item dine-in dengan course ditahan sampai course-nya
+// di-fire; order selain dine-in tidak mengenal penahanan course sehingga course langsung dianggap fired.
+// Baris order_courses dibuat saat item pertama course masuk.
+func holdLine(ctx context.Context, tx *sql.Tx, orderID int, orderType string, item *models.OrderItemInput, staffID int) (bool, error) {
+	if item.Course == "" {
+		return item.Hold, nil
+	}
+
+	autoFire := orderType != "dine_in"
+	var fired bool
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO order_courses (order_id, course, fired_at, fired_by)
+		VALUES ($1, $2, CASE WHEN $3 THEN NOW() END, CASE WHEN $3 THEN $4::int END)
+		ON CONFLICT (order_id, course) DO UPDATE SET served_at = NULL
+		RETURNING fired_at IS NOT NULL
+	`, orderID, item.Course, autoFire, sql.NullInt64{Int64: int64(staffID), Valid: staffID > 0}).Scan(&fired)
+	if err != nil {
+		return false, err
+	}
+	return item.Hold || !fired, nil
+}
+
+// fireCourse menandai course order sudah di-fire, ErrNothingToFire jika course tidak ada atau sudah di-fire
+func fireCourse(ctx context.Context, tx *sql.Tx, orderID int, course string, staffID int) error {
+	if course == "" || !models.ValidCourse(course) {
+		return fmt.Errorf("%w: %s", ErrInvalidCourse, course)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE order_courses SET fired_at = NOW(), fired_by = $3
+		WHERE order_id = $1 AND course = $2 AND fired_at IS NULL
+	`, orderID, course, sql.NullInt64{Int64: int64(staffID), Valid: staffID > 0})
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: course %s tidak ada di order %d atau sudah di-fire", ErrNothingToFire, course, orderID)
+	}
+	return nil
+}
+
+// markCoursesFired ikut menandai course sebagai fired ketika tiketnya dilepas satu per satu
+func markCoursesFired(ctx context.Context, tx *sql.Tx, orderID int, ticketIDs []int, staffID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE order_courses SET fired_at = NOW(), fired_by = $3
+		WHERE order_id = $1 AND fired_at IS NULL
+			AND course IN (SELECT course FROM kitchen_tickets WHERE id = ANY($2) AND course IS NOT NULL)
+	`, orderID, pq.Array(ticketIDs), sql.NullInt64{Int64: int64(staffID), Valid: staffID > 0})
+	return err
+}
+
+// markCourseServed mengisi served_at course bila semua item aktifnya di order sudah served
+func markCourseServed(ctx context.Context, tx *sql.Tx, ticketItemID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE order_courses oc SET served_at = NOW()
+		FROM kitchen_ticket_items kti
+		JOIN kitchen_tickets kt ON kt.id = kti.ticket_id
+		WHERE kti.id = $1
+			AND oc.order_id = kt.order_id AND oc.course = kt.course
+			AND oc.served_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1
+				FROM kitchen_ticket_items x
+				JOIN kitchen_tickets y ON y.id = x.ticket_id
+				JOIN order_items oi ON oi.id = x.order_item_id
+				WHERE y.order_id = kt.order_id AND y.course = kt.course
+					AND x.status <> 'served' AND oi.voided_at IS NULL
+			)
+	`, ticketItemID)
+	return err
+}
+
+// ListCourses menampilkan status setiap course order sesuai urutan penyajian
+func (r *OrderRepository) ListCourses(ctx context.Context, orderID int) ([]*models.OrderCourse, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT oc.order_id, oc.course, oc.fired_at, oc.fired_by, oc.served_at, oc.created_at,
+			(SELECT COUNT(*) FROM order_items oi
+				WHERE oi.order_id = oc.order_id AND oi.course = oc.course AND oi.voided_at IS NULL)
+		FROM order_courses oc
+		WHERE oc.order_id = $1
+		ORDER BY CASE oc.course WHEN 'appetizer' THEN 1 WHEN 'main' THEN 2 ELSE 3 END
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := []*models.OrderCourse{}
+	for rows.Next() {
+		var c models.OrderCourse
+		err := rows.Scan(&c.OrderID, &c.Course, &c.FiredAt, &c.FiredBy, &c.ServedAt, &c.CreatedAt, &c.Items)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case c.ServedAt.Valid:
+			c.Status = models.CourseServed
+		case c.FiredAt.Valid:
+			c.Status = models.CourseFired
+		default:
+			c.Status = models.CourseHeld
+		}
+		courses = append(courses, &c)
+	}
+	return courses, rows.Err()
+}
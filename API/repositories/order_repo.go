@@ -3,10 +3,14 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"math"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
 	"slices"
+
+	"github.com/lib/pq"
 )
 
 type OrderRepository struct {
@@ -17,14 +21,30 @@ func NewOrderRepository(db *sql.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
+var (
+	ErrNothingToVoid    = errors.New("order atau item tidak ditemukan / sudah void")
+	ErrOrderNotEditable = errors.New("order sudah ditutup (settled/void/merged)")
+	ErrOrderBilled      = errors.New("order sudah punya bill aktif, void bill lebih dulu untuk mengubah item")
+	ErrOrderHasPayments = errors.New("order punya bill yang sudah dibayar, refund pembayarannya lebih dulu")
+	ErrStatusConflict   = errors.New("status order sudah berubah, silakan muat ulang")
+	ErrOrderUnpaid      = errors.New("masih ada bill yang belum lunas")
+	ErrMenuUnavailable  = errors.New("menu tidak aktif atau sudah dihapus")
+	ErrInvalidMerge     = errors.New("merge order tidak valid")
+	ErrNothingToFire    = errors.New("tidak ada item yang ditahan")
+	ErrInvalidCourse    = errors.New("course tidak valid")
+)
+
 type IngredientUsage struct {
 	IngredientID int
 	UsedQty      float64
+	IsExtra      bool // Berasal dari modifier, bukan resep menu
 }
 
 func (r *OrderRepository) Create(ctx context.Context, req *models.OrderRequest) (int, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
-
+	if err != nil {
+		return 0, err
+	}
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
@@ -49,85 +69,37 @@ func (r *OrderRepository) Create(ctx context.Context, req *models.OrderRequest)
 		return 0, err
 	}
 
-	// Masukkan menu berdasarkan order
+	err = insertStatusHistory(ctx, tx, orderID, "", req.Status, req.WaiterID, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// Masukkan menu berdasarkan order (paket dipecah menjadi item komponen)
+	var orderItemIDs, heldItemIDs []int
 	for _, item := range req.Items {
-		log.Println("➡️ Inserting order item...")
-		var orderItemID int
-		err = tx.QueryRowContext(ctx, `
-			INSERT INTO order_items (
-				order_id, menu_item_id, qty, notes, unit_price
-			) VALUES ($1,$2,$3,$4,$5)
-			RETURNING id
-		`, orderID, item.MenuItemID, item.Qty, item.Notes, item.UnitPrice).Scan(&orderItemID)
+		var lineItemIDs []int
+		lineItemIDs, err = addOrderLine(ctx, tx, orderID, &item, req.WaiterID)
 		if err != nil {
 			return 0, err
 		}
-
-		// Simpan excluded ingredients
-		excludedMap := make(map[int]bool)
-		for _, ingID := range item.ExcludedIngredientIDs {
-			excludedMap[ingID] = true
-
-			_, err = tx.ExecContext(ctx, `
-				INSERT INTO order_item_ingredient_excluded (
-					order_item_id, ingredient_id
-				) VALUES ($1, $2)
-			`, orderItemID, ingID)
-			if err != nil {
-				return 0, err
-			}
-		}
-
-		// Ambil bahan dari menu item
-		rows, err := tx.QueryContext(ctx, `
-			SELECT ingredient_id, qty
-			FROM menu_ingredients
-			WHERE menu_item_id = $1
-		`, item.MenuItemID)
+		var held bool
+		held, err = holdLine(ctx, tx, orderID, req.OrderType, &item, req.WaiterID)
 		if err != nil {
 			return 0, err
 		}
-
-		var ingredients []IngredientUsage
-		for rows.Next() {
-			var ing IngredientUsage
-			if err := rows.Scan(&ing.IngredientID, &ing.UsedQty); err != nil {
-				rows.Close()
-				return 0, err
-			}
-			ingredients = append(ingredients, ing)
+		if held {
+			heldItemIDs = append(heldItemIDs, lineItemIDs...)
+		} else {
+			orderItemIDs = append(orderItemIDs, lineItemIDs...)
 		}
-		rows.Close()
-
-		// Proses setiap bahan
-		for _, ing := range ingredients {
-			if excludedMap[ing.IngredientID] {
-				continue
-			}
-
-			totalUsed := ing.UsedQty * float64(item.Qty)
-
-			var currentQty float64
-			err = tx.QueryRowContext(ctx, `
-			SELECT qty FROM ingredients WHERE id = $1
-		`, ing.IngredientID).Scan(&currentQty)
-			if err != nil {
-				return 0, err
-			}
-
-			if currentQty < totalUsed {
-				return 0, fmt.Errorf("stok bahan %d tidak cukup", ing.IngredientID)
-			}
+	}
 
-			_, err = tx.ExecContext(ctx, `
-			UPDATE ingredients
-			SET qty = qty - $1
-			WHERE id = $2
-		`, totalUsed, ing.IngredientID)
-			if err != nil {
-				return 0, err
-			}
-		}
+	// Kirim item ke dapur (KDS + printer station), item hold menunggu di-fire
+	if err = createKitchenTickets(ctx, tx, orderID, orderItemIDs, false); err != nil {
+		return 0, err
+	}
+	if err = createKitchenTickets(ctx, tx, orderID, heldItemIDs, true); err != nil {
+		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -143,9 +115,12 @@ func (r *OrderRepository) List(ctx context.Context) ([]*models.OrderRequest, err
 		o.id, o.order_number, o.table_id, o.customer_id, o.hotel_room,
 		o.waiter_id, o.outlet_id, o.status, o.order_type,
 		oi.id, oi.menu_item_id, oi.qty, oi.notes, oi.unit_price,
+		oi.override_price, oi.override_reason, oi.override_approved_by,
+		oi.modifiers_price, oi.order_combo_id, oi.seat_no, COALESCE(oi.course, ''),
+		(SELECT array_agg(m.modifier_option_id) FROM order_item_modifiers m WHERE m.order_item_id = oi.id),
 		ie.ingredient_id
 	FROM orders o
-	LEFT JOIN order_items oi ON o.id = oi.order_id
+	LEFT JOIN order_items oi ON o.id = oi.order_id AND oi.voided_at IS NULL
 	LEFT JOIN order_item_ingredient_excluded ie ON oi.id = ie.order_item_id
 	ORDER BY o.created_at DESC
 	`
@@ -163,15 +138,22 @@ func (r *OrderRepository) List(ctx context.Context) ([]*models.OrderRequest, err
 			orderID, tableID, customerID, waiterID, outletID int
 			orderNumber, status, orderType                   string
 			orderItemID, menuItemID                          int
-			UnitPrice, qty                                   float64
-			hotelRoom, notes                                 sql.NullString
-			excludedIngID                                    sql.NullInt64
+			qty                                              float64
+			UnitPrice, modifiersPrice                        money.Amount
+			hotelRoom, notes, overrideReason                 sql.NullString
+			course                                           string
+			excludedIngID, overrideBy                        sql.NullInt64
+			overridePrice                                    money.NullAmount
+			modifierIDs                                      pq.Int64Array
+			orderComboID, seatNo                             sql.NullInt64
 		)
 
 		err := rows.Scan(
 			&orderID, &orderNumber, &tableID, &customerID, &hotelRoom,
 			&waiterID, &outletID, &status, &orderType,
 			&orderItemID, &menuItemID, &qty, &notes, &UnitPrice,
+			&overridePrice, &overrideReason, &overrideBy,
+			&modifiersPrice, &orderComboID, &seatNo, &course, &modifierIDs,
 			&excludedIngID,
 		)
 		if err != nil {
@@ -202,7 +184,22 @@ func (r *OrderRepository) List(ctx context.Context) ([]*models.OrderRequest, err
 				Qty:                   qty,
 				Notes:                 notes.String,
 				UnitPrice:             UnitPrice,
+				ModifiersPrice:        modifiersPrice,
+				ModifierOptionIDs:     []int{},
 				ExcludedIngredientIDs: []int{},
+				OrderComboID:          int(orderComboID.Int64),
+				SeatNo:                int(seatNo.Int64),
+				Course:                course,
+			}
+			for _, optID := range modifierIDs {
+				item.ModifierOptionIDs = append(item.ModifierOptionIDs, int(optID))
+			}
+			if overridePrice.Valid {
+				item.PriceOverride = &models.PriceOverride{
+					Price:      overridePrice.Amount,
+					Reason:     overrideReason.String,
+					ApprovedBy: int(overrideBy.Int64),
+				}
 			}
 			if excludedIngID.Valid {
 				item.ExcludedIngredientIDs = append(item.ExcludedIngredientIDs, int(excludedIngID.Int64))
@@ -225,9 +222,12 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.OrderReq
 		o.id, o.order_number, o.table_id, o.customer_id, o.hotel_room,
 		o.waiter_id, o.outlet_id, o.status, o.order_type,
 		oi.id, oi.menu_item_id, oi.qty, oi.notes, oi.unit_price,
+		oi.override_price, oi.override_reason, oi.override_approved_by,
+		oi.modifiers_price, oi.order_combo_id, oi.seat_no, COALESCE(oi.course, ''),
+		(SELECT array_agg(m.modifier_option_id) FROM order_item_modifiers m WHERE m.order_item_id = oi.id),
 		ie.ingredient_id
 	FROM orders o
-	LEFT JOIN order_items oi ON o.id = oi.order_id
+	LEFT JOIN order_items oi ON o.id = oi.order_id AND oi.voided_at IS NULL
 	LEFT JOIN order_item_ingredient_excluded ie ON oi.id = ie.order_item_id
 	WHERE o.id = $1
 	`
@@ -245,15 +245,22 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.OrderReq
 			orderID, tableID, customerID, waiterID, outletID int
 			orderNumber, status, orderType                   string
 			orderItemID, menuItemID                          int
-			UnitPrice, qty                                   float64
-			hotelRoom, notes                                 sql.NullString
-			excludedIngID                                    sql.NullInt64
+			qty                                              float64
+			UnitPrice, modifiersPrice                        money.Amount
+			hotelRoom, notes, overrideReason                 sql.NullString
+			course                                           string
+			excludedIngID, overrideBy                        sql.NullInt64
+			overridePrice                                    money.NullAmount
+			modifierIDs                                      pq.Int64Array
+			orderComboID, seatNo                             sql.NullInt64
 		)
 
 		err := rows.Scan(
 			&orderID, &orderNumber, &tableID, &customerID, &hotelRoom,
 			&waiterID, &outletID, &status, &orderType,
 			&orderItemID, &menuItemID, &qty, &notes, &UnitPrice,
+			&overridePrice, &overrideReason, &overrideBy,
+			&modifiersPrice, &orderComboID, &seatNo, &course, &modifierIDs,
 			&excludedIngID,
 		)
 		if err != nil {
@@ -282,7 +289,22 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.OrderReq
 				Qty:                   qty,
 				Notes:                 notes.String,
 				UnitPrice:             UnitPrice,
+				ModifiersPrice:        modifiersPrice,
+				ModifierOptionIDs:     []int{},
 				ExcludedIngredientIDs: []int{},
+				OrderComboID:          int(orderComboID.Int64),
+				SeatNo:                int(seatNo.Int64),
+				Course:                course,
+			}
+			for _, optID := range modifierIDs {
+				item.ModifierOptionIDs = append(item.ModifierOptionIDs, int(optID))
+			}
+			if overridePrice.Valid {
+				item.PriceOverride = &models.PriceOverride{
+					Price:      overridePrice.Amount,
+					Reason:     overrideReason.String,
+					ApprovedBy: int(overrideBy.Int64),
+				}
 			}
 			if excludedIngID.Valid {
 				item.ExcludedIngredientIDs = append(item.ExcludedIngredientIDs, int(excludedIngID.Int64))
@@ -298,33 +320,61 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.OrderReq
 	return order, nil
 }
 
-func (r *OrderRepository) Update(ctx context.Context, order *models.Order) error {
-	_, err := r.db.ExecContext(ctx, `
+// Update mengubah data order yang masih berjalan, lalu (jika toStatus diisi) memindahkan statusnya dari fromStatus
+// dalam transaksi yang sama sehingga perubahan data dan status tidak pernah tersimpan setengah jalan
+func (r *OrderRepository) Update(ctx context.Context, order *models.Order, fromStatus, toStatus string, staffID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockEditableOrder(ctx, tx, order.ID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE orders SET
 			table_id = $1,
 			customer_id = $2,
 			hotel_room = $3,
 			waiter_id = $4,
 			outlet_id = $5,
-			status = $6,
-			order_type = $7,
+			order_type = $6,
 			updated_at = NOW()
-		WHERE id = $8
+		WHERE id = $7
 	`,
 		order.TableID,
 		order.CustomerID,
 		order.HotelRoom,
 		order.WaiterID,
 		order.OutletID,
-		order.Status,
 		order.OrderType,
 		order.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if toStatus == models.OrderSettled {
+		if err := ensureSettleable(ctx, tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if toStatus != "" && toStatus != fromStatus {
+		if err := setOrderStatus(ctx, tx, order.ID, fromStatus, toStatus, staffID, ""); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *OrderRepository) AddItem(ctx context.Context, orderID int, item *models.AddOrderItemRequest) error {
 	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
@@ -334,44 +384,831 @@ func (r *OrderRepository) AddItem(ctx context.Context, orderID int, item *models
 		}
 	}()
 
-	// 1. Tambahkan order item
+	// 1. Pastikan order masih bisa diubah dan belum ditagih
+	var waiterID int
+	if waiterID, _, err = lockEditableOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+	if err = ensureUnbilled(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	// 2. Tambahkan item, kurangi stok atas nama waiter order (dicatat ke ledger)
+	var orderItemIDs []int
+	line := &models.OrderItemInput{
+		MenuItemID:            item.MenuItemID,
+		Qty:                   item.Qty,
+		Notes:                 item.Notes,
+		SeatNo:                item.SeatNo,
+		PriceOverride:         item.PriceOverride,
+		ModifierOptionIDs:     item.ModifierOptionIDs,
+		ExcludedIngredientIDs: item.ExcludedIngredientIDs,
+		ComboID:               item.ComboID,
+		ComboSelections:       item.ComboSelections,
+		Course:                item.Course,
+		Hold:                  item.Hold,
+	}
+	orderItemIDs, err = addOrderLine(ctx, tx, orderID, line, waiterID)
+	if err != nil {
+		return err
+	}
+
+	// 3. Kirim item ke dapur (KDS + printer station), kecuali ditahan atau course-nya belum di-fire
+	var orderType string
+	if err = tx.QueryRowContext(ctx, `SELECT order_type FROM orders WHERE id = $1`, orderID).Scan(&orderType); err != nil {
+		return err
+	}
+	var held bool
+	if held, err = holdLine(ctx, tx, orderID, orderType, line, waiterID); err != nil {
+		return err
+	}
+	if err = createKitchenTickets(ctx, tx, orderID, orderItemIDs, held); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Fire melepas tiket dapur order yang ditahan: satu course, tiket tertentu (ticketIDs), atau semua jika keduanya kosong.
+// Mengembalikan ID tiket yang dilepas.
+func (r *OrderRepository) Fire(ctx context.Context, orderID int, course string, ticketIDs []int, staffID int) ([]int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockEditableOrder(ctx, tx, orderID); err != nil {
+		return nil, err
+	}
+
+	if course != "" {
+		if err := fireCourse(ctx, tx, orderID, course, staffID); err != nil {
+			return nil, err
+		}
+	}
+
+	fired, err := fireKitchenTickets(ctx, tx, orderID, course, ticketIDs, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fired) == 0 && course == "" {
+		return nil, fmt.Errorf("%w: order %d", ErrNothingToFire, orderID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return fired, nil
+}
+
+// addOrderLine menyimpan satu baris pesanan beserta excluded ingredients dan pemakaian stoknya.
+// Paket dipecah menjadi item komponen dengan harga hasil alokasi. Mengembalikan ID order item yang dibuat.
+func addOrderLine(ctx context.Context, tx *sql.Tx, orderID int, item *models.OrderItemInput, staffID int) ([]int, error) {
+	if !models.ValidCourse(item.Course) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCourse, item.Course)
+	}
+	if item.ComboID != 0 {
+		return addComboLine(ctx, tx, orderID, item, staffID)
+	}
+
+	var price money.Amount
+	err := tx.QueryRowContext(ctx, `
+		SELECT price FROM menu_items
+		WHERE id = $1 AND is_active = TRUE AND deleted_at IS NULL
+	`, item.MenuItemID).Scan(&price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: menu %d", ErrMenuUnavailable, item.MenuItemID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	orderItemID, err := insertOrderItem(ctx, tx, orderID, item, price, sql.NullInt64{}, staffID)
+	if err != nil {
+		return nil, err
+	}
+	return []int{orderItemID}, nil
+}
+
+// addComboLine menyimpan paket ke order_combos lalu setiap komponennya sebagai order item.
+// Harga paket dialokasikan ke komponen sehingga bill tetap menagih harga paket
+// dan laporan penjualan per menu mendapat porsi pendapatannya.
+func addComboLine(ctx context.Context, tx *sql.Tx, orderID int, item *models.OrderItemInput, staffID int) ([]int, error) {
+	if item.PriceOverride != nil {
+		return nil, fmt.Errorf("%w: override harga tidak berlaku untuk paket", ErrInvalidComboChoice)
+	}
+
+	name, price, components, err := resolveCombo(ctx, tx, item.ComboID, item.ComboSelections)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderComboID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_combos (order_id, combo_id, name, qty, unit_price)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, orderID, item.ComboID, name, item.Qty, price).Scan(&orderComboID)
+	if err != nil {
+		return nil, err
+	}
+
+	comboRef := sql.NullInt64{Int64: int64(orderComboID), Valid: true}
+	shares := allocateComboPrice(price, components)
+
+	var orderItemIDs []int
+	for i, comp := range components {
+		orderItemID, err := insertOrderItem(ctx, tx, orderID, &models.OrderItemInput{
+			MenuItemID:            comp.selection.MenuItemID,
+			Qty:                   item.Qty,
+			Notes:                 item.Notes,
+			SeatNo:                item.SeatNo,
+			Course:                item.Course,
+			ModifierOptionIDs:     comp.selection.ModifierOptionIDs,
+			ExcludedIngredientIDs: comp.selection.ExcludedIngredientIDs,
+		}, shares[i], comboRef, staffID)
+		if err != nil {
+			return nil, err
+		}
+		orderItemIDs = append(orderItemIDs, orderItemID)
+	}
+	return orderItemIDs, nil
+}
+
+// insertOrderItem menyimpan item dengan harga dari server, modifier divalidasi dan disimpan sebagai snapshot
+// (total price_delta-nya disimpan di modifiers_price sehingga ikut terhitung di bill),
+// lalu excluded ingredients dan pemakaian stoknya dicatat.
+func insertOrderItem(ctx context.Context, tx *sql.Tx, orderID int, item *models.OrderItemInput, price money.Amount, orderComboID sql.NullInt64, staffID int) (int, error) {
+	modifiers, err := resolveModifiers(ctx, tx, item.MenuItemID, item.ModifierOptionIDs)
+	if err != nil {
+		return 0, err
+	}
+	var modifiersPrice money.Amount
+	for _, m := range modifiers {
+		modifiersPrice += m.PriceDelta
+	}
+
+	var (
+		overridePrice  money.NullAmount
+		overrideReason sql.NullString
+		overrideBy     sql.NullInt64
+	)
+	if o := item.PriceOverride; o != nil {
+		overridePrice = money.NullAmount{Amount: o.Price, Valid: true}
+		overrideReason = sql.NullString{String: o.Reason, Valid: true}
+		overrideBy = sql.NullInt64{Int64: int64(o.ApprovedBy), Valid: true}
+	}
+	// price_delta boleh negatif (contoh tanpa nasi) tetapi harga baris tidak boleh di bawah nol
+	base := price
+	if overridePrice.Valid {
+		base = overridePrice.Amount
+	}
+	if base+modifiersPrice < 0 {
+		return 0, fmt.Errorf("%w: harga item %s ditambah modifier %s menjadi negatif", ErrInvalidModifier, base, modifiersPrice)
+	}
+
 	var orderItemID int
 	err = tx.QueryRowContext(ctx, `
 		INSERT INTO order_items (
-			order_id, menu_item_id, qty, notes, unit_price
-		) VALUES ($1, $2, $3, $4, $5)
+			order_id, menu_item_id, qty, notes, unit_price,
+			override_price, override_reason, override_approved_by, modifiers_price, order_combo_id, seat_no, course
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
 		RETURNING id
-	`, orderID, item.MenuItemID, item.Qty, item.Notes, item.UnitPrice).Scan(&orderItemID)
+	`, orderID, item.MenuItemID, item.Qty, item.Notes, price,
+		overridePrice, overrideReason, overrideBy, modifiersPrice, orderComboID,
+		sql.NullInt64{Int64: int64(item.SeatNo), Valid: item.SeatNo > 0}, item.Course,
+	).Scan(&orderItemID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	for _, m := range modifiers {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_item_modifiers (order_item_id, modifier_option_id, name, price_delta)
+			VALUES ($1, $2, $3, $4)
+		`, orderItemID, m.ID, m.Name, m.PriceDelta)
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	// 2. Tambahkan excluded ingredients (jika ada)
 	for _, ingID := range item.ExcludedIngredientIDs {
-		_, err := tx.ExecContext(ctx, `
+		_, err = tx.ExecContext(ctx, `
 			INSERT INTO order_item_ingredient_excluded (
 				order_item_id, ingredient_id
 			) VALUES ($1, $2)
 		`, orderItemID, ingID)
 		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Kurangi stok bahan (dicatat ke ledger)
+	err = consumeIngredients(ctx, tx, orderItemID, item.MenuItemID, item.Qty, item.ExcludedIngredientIDs, staffID)
+	if err != nil {
+		return 0, err
+	}
+
+	return orderItemID, nil
+}
+
+// Void membatalkan order, bill-nya yang belum dibayar, dan semua item & paket yang belum di-void lalu
+// mengembalikan stok item tsb. Item yang sudah di-void sebelumnya tidak disentuh lagi.
+// Order yang bill-nya masih memegang pembayaran ditolak, pembayaran harus di-refund lebih dulu.
+// Jika asWaste = true (makanan sudah dibuat), stok tidak kembali melainkan dicatat sebagai waste.
+func (r *OrderRepository) Void(ctx context.Context, id int, fromStatus string, staffID int, asWaste bool, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setOrderStatus(ctx, tx, id, fromStatus, models.OrderVoid, staffID, reason); err != nil {
+		return err
+	}
+
+	var paidBills int
+	err = tx.QueryRowContext(ctx, `
+		WITH locked AS (
+			SELECT id, paid_amount FROM bills WHERE order_id = $1 AND status <> 'void' FOR UPDATE
+		)
+		SELECT COUNT(*) FROM locked WHERE paid_amount > 0
+	`, id).Scan(&paidBills)
+	if err != nil {
+		return err
+	}
+	if paidBills > 0 {
+		return fmt.Errorf("%w: order %d", ErrOrderHasPayments, id)
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bills SET status = 'void', updated_at = NOW()
+		WHERE order_id = $1 AND status IN ('open', 'partial')
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	staffRef := sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0}
+	reasonRef := sql.NullString{String: reason, Valid: reason != ""}
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE order_items SET voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE order_id = $1 AND voided_at IS NULL
+		RETURNING id
+	`, id, staffRef, reasonRef)
+	if err != nil {
+		return err
+	}
+	var itemIDs []int
+	for rows.Next() {
+		var itemID int
+		if err := rows.Scan(&itemID); err != nil {
+			rows.Close()
 			return err
 		}
+		itemIDs = append(itemIDs, itemID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	// 3. Ambil bahan dari menu_ingredients dan simpan ke slice
-	var ingredients []IngredientUsage
+	_, err = tx.ExecContext(ctx, `
+		UPDATE order_combos SET voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE order_id = $1 AND voided_at IS NULL
+	`, id, staffRef, reasonRef)
+	if err != nil {
+		return err
+	}
+
+	for _, itemID := range itemIDs {
+		if err := reverseIngredients(ctx, tx, itemID, staffID, asWaste, reason); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// VoidItem membatalkan satu order item (atau seluruh isi paketnya) dan mengembalikan stoknya
+func (r *OrderRepository) VoidItem(ctx context.Context, orderID, itemID, staffID int, asWaste bool, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockEditableOrder(ctx, tx, orderID); err != nil {
+		return err
+	}
+	if err := ensureUnbilled(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	// Komponen paket tidak bisa dibatalkan sendiri, seluruh isi paket ikut di-void
+	staffRef := sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0}
+	reasonRef := sql.NullString{String: reason, Valid: reason != ""}
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE order_items SET voided_at = NOW(), voided_by = $4, void_reason = $1
+		WHERE order_id = $3 AND voided_at IS NULL AND (
+			id = $2 OR order_combo_id = (SELECT order_combo_id FROM order_items WHERE id = $2)
+		)
+		RETURNING id
+	`, reasonRef, itemID, orderID, staffRef)
+	if err != nil {
+		return err
+	}
+	var voidedIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		voidedIDs = append(voidedIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(voidedIDs) == 0 {
+		return ErrNothingToVoid
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE order_combos SET voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE id = (SELECT order_combo_id FROM order_items WHERE id = $1) AND voided_at IS NULL
+	`, itemID, staffRef, reasonRef)
+	if err != nil {
+		return err
+	}
 
+	for _, id := range voidedIDs {
+		if err := reverseIngredients(ctx, tx, id, staffID, asWaste, reason); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrderRepository) GetOutletID(ctx context.Context, id int) (int, error) {
+	var outletID int
+	err := r.db.QueryRowContext(ctx, `SELECT outlet_id FROM orders WHERE id = $1`, id).Scan(&outletID)
+	return outletID, err
+}
+
+func (r *OrderRepository) GetStatus(ctx context.Context, id int) (string, error) {
+	var status string
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1`, id).Scan(&status)
+	return status, err
+}
+
+// ChangeStatus memindahkan status order dari fromStatus ke toStatus dan mencatat riwayatnya.
+// Baris order dikunci lebih dulu sehingga syarat settled (semua bill lunas) dicek tanpa bisa disela
+// pembuatan bill baru.
+func (r *OrderRepository) ChangeStatus(ctx context.Context, id int, fromStatus, toStatus string, staffID int, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, id).Scan(&status); err != nil {
+		return err
+	}
+	if status != fromStatus {
+		return ErrStatusConflict
+	}
+	if toStatus == models.OrderSettled {
+		if err := ensureSettleable(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	if err := setOrderStatus(ctx, tx, id, fromStatus, toStatus, staffID, reason); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrderRepository) ListStatusHistory(ctx context.Context, orderID int) ([]*models.OrderStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, from_status, to_status, changed_by, reason, changed_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY changed_at, id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*models.OrderStatusChange
+	for rows.Next() {
+		var h models.OrderStatusChange
+		err := rows.Scan(&h.ID, &h.OrderID, &h.FromStatus, &h.ToStatus, &h.ChangedBy, &h.Reason, &h.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &h)
+	}
+	return history, rows.Err()
+}
+
+// Merge menggabungkan beberapa order open/transferred ke satu order tujuan, lihat mergeOrders. Bill order tujuan
+// yang belum dibayar juga di-void karena tidak mencakup item yang dipindah; bill baru dibuat ulang dari order gabungan.
+func (r *OrderRepository) Merge(ctx context.Context, targetID int, sourceIDs []int, staffID int, reason string) (*models.MergeResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := mergeOrders(ctx, tx, targetID, sourceIDs, staffID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, paid_amount + refunded_amount FROM bills
+		WHERE order_id = $1 AND status NOT IN ('void', 'split')
+		ORDER BY id
+		FOR UPDATE
+	`, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var targetBills []int
+	for rows.Next() {
+		var (
+			billID int
+			paid   money.Amount
+		)
+		if err := rows.Scan(&billID, &paid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if paid != 0 {
+			rows.Close()
+			return nil, fmt.Errorf("%w: bill %d milik order tujuan sudah menerima pembayaran", ErrInvalidMerge, billID)
+		}
+		targetBills = append(targetBills, billID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(targetBills) > 0 {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE bills SET status = 'void', updated_at = NOW() WHERE id = ANY($1)
+		`, pq.Array(targetBills))
+		if err != nil {
+			return nil, err
+		}
+		result.VoidedBillIDs = append(targetBills, result.VoidedBillIDs...)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListMerges mengambil jejak merge di mana order menjadi tujuan maupun sumber
+func (r *OrderRepository) ListMerges(ctx context.Context, orderID int) ([]*models.OrderMerge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, target_order_id, source_order_id, source_table_id, items_moved, bill_id, merged_by, reason, merged_at
+		FROM order_merges
+		WHERE target_order_id = $1 OR source_order_id = $1
+		ORDER BY merged_at, id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	merges := []*models.OrderMerge{}
+	for rows.Next() {
+		var m models.OrderMerge
+		err := rows.Scan(&m.ID, &m.TargetOrderID, &m.SourceOrderID, &m.SourceTableID, &m.ItemsMoved,
+			&m.BillID, &m.MergedBy, &m.Reason, &m.MergedAt)
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, &m)
+	}
+	return merges, rows.Err()
+}
+
+// mergeOrders memindahkan seluruh isi order sumber ke order tujuan: item, paket dan tiket dapur di-re-parent,
+// bill sumber yang belum dibayar di-void, order sumber ditutup dengan status merged dan dicatat di order_merges,
+// lalu meja sumber yang tidak lagi dipakai order aktif dikembalikan ke available.
+// Nomor kursi item sumber digeser setelah kursi terakhir order tujuan supaya split per kursi tetap bisa dipakai.
+func mergeOrders(ctx context.Context, tx *sql.Tx, targetID int, sourceIDs []int, staffID int, reason string) (*models.MergeResult, error) {
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: minimal satu order sumber", ErrInvalidMerge)
+	}
+	seen := map[int]bool{targetID: true}
+	for _, id := range sourceIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: order %d disebut lebih dari sekali atau sama dengan order tujuan", ErrInvalidMerge, id)
+		}
+		seen[id] = true
+	}
+
+	// Kunci semua order berurutan id supaya dua merge yang berjalan bersamaan tidak saling deadlock
+	type lockedOrder struct {
+		status   string
+		outletID int
+		tableID  sql.NullInt64
+	}
+	allIDs := append([]int{targetID}, sourceIDs...)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, status, outlet_id, table_id FROM orders WHERE id = ANY($1) ORDER BY id FOR UPDATE
+	`, pq.Array(allIDs))
+	if err != nil {
+		return nil, err
+	}
+	locked := map[int]lockedOrder{}
+	for rows.Next() {
+		var (
+			id int
+			o  lockedOrder
+		)
+		if err := rows.Scan(&id, &o.status, &o.outletID, &o.tableID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		locked[id] = o
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range allIDs {
+		o, ok := locked[id]
+		if !ok {
+			return nil, fmt.Errorf("order %d: %w", id, sql.ErrNoRows)
+		}
+		if !models.IsLiveOrder(o.status) {
+			return nil, fmt.Errorf("%w: order %d berstatus %s", ErrOrderNotEditable, id, o.status)
+		}
+		if o.outletID != locked[targetID].outletID {
+			return nil, fmt.Errorf("%w: order %d berasal dari outlet lain", ErrInvalidMerge, id)
+		}
+	}
+
+	// Bill sumber yang sudah menerima pembayaran (termasuk yang sudah di-refund) tidak boleh hilang
+	rows, err = tx.QueryContext(ctx, `
+		SELECT id, order_id, paid_amount + refunded_amount FROM bills
+		WHERE order_id = ANY($1) AND status <> 'void'
+		ORDER BY id
+		FOR UPDATE
+	`, pq.Array(sourceIDs))
+	if err != nil {
+		return nil, err
+	}
+	result := &models.MergeResult{
+		OutletID:       locked[targetID].outletID,
+		TargetOrderID:  targetID,
+		MergedOrderIDs: sourceIDs,
+		VoidedBillIDs:  []int{},
+		FreedTables:    []models.FreedTable{},
+	}
+	for rows.Next() {
+		var (
+			billID, orderID int
+			paid            money.Amount
+		)
+		if err := rows.Scan(&billID, &orderID, &paid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if paid != 0 {
+			rows.Close()
+			return nil, fmt.Errorf("%w: bill %d milik order %d sudah menerima pembayaran", ErrInvalidMerge, billID, orderID)
+		}
+		result.VoidedBillIDs = append(result.VoidedBillIDs, billID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result.VoidedBillIDs) > 0 {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE bills SET status = 'void', updated_at = NOW() WHERE id = ANY($1)
+		`, pq.Array(result.VoidedBillIDs))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	note := fmt.Sprintf("digabung ke order %d", targetID)
+	if reason != "" {
+		note += ": " + reason
+	}
+
+	var freeTableIDs []int
+	for _, sourceID := range sourceIDs {
+		var moved int
+		err := tx.QueryRowContext(ctx, `
+			WITH seat_offset AS (
+				SELECT COALESCE(MAX(seat_no), 0) AS n FROM order_items WHERE order_id = $1
+			), moved AS (
+				UPDATE order_items SET order_id = $1, seat_no = seat_no + (SELECT n FROM seat_offset)
+				WHERE order_id = $2
+				RETURNING voided_at
+			)
+			SELECT COUNT(*) FILTER (WHERE voided_at IS NULL) FROM moved
+		`, targetID, sourceID).Scan(&moved)
+		if err != nil {
+			return nil, err
+		}
+		result.ItemsMoved += moved
+
+		for _, table := range []string{"order_combos", "kitchen_tickets"} {
+			_, err := tx.ExecContext(ctx, `UPDATE `+table+` SET order_id = $1 WHERE order_id = $2`, targetID, sourceID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Course yang sama digabung: masih ditahan jika salah satunya ditahan (sisa tiketnya belum di-fire),
+		// served hanya jika keduanya sudah served
+		_, err = tx.ExecContext(ctx, `
+			WITH src AS (
+				DELETE FROM order_courses WHERE order_id = $2
+				RETURNING course, fired_at, fired_by, served_at, created_at
+			)
+			INSERT INTO order_courses AS oc (order_id, course, fired_at, fired_by, served_at, created_at)
+			SELECT $1, course, fired_at, fired_by, served_at, created_at FROM src
+			ON CONFLICT (order_id, course) DO UPDATE SET
+				fired_at = CASE WHEN oc.fired_at IS NULL OR EXCLUDED.fired_at IS NULL THEN NULL
+					ELSE LEAST(oc.fired_at, EXCLUDED.fired_at) END,
+				fired_by = CASE WHEN oc.fired_at IS NULL OR EXCLUDED.fired_at IS NULL THEN NULL
+					ELSE oc.fired_by END,
+				served_at = CASE WHEN oc.served_at IS NULL OR EXCLUDED.served_at IS NULL THEN NULL
+					ELSE GREATEST(oc.served_at, EXCLUDED.served_at) END,
+				created_at = LEAST(oc.created_at, EXCLUDED.created_at)
+		`, targetID, sourceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := setOrderStatus(ctx, tx, sourceID, locked[sourceID].status, models.OrderMerged, staffID, note); err != nil {
+			return nil, err
+		}
+
+		sourceTable := locked[sourceID].tableID
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_merges (target_order_id, source_order_id, source_table_id, items_moved, merged_by, reason)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, targetID, sourceID, sourceTable, moved,
+			sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+			sql.NullString{String: reason, Valid: reason != ""})
+		if err != nil {
+			return nil, err
+		}
+
+		if sourceTable.Valid && sourceTable != locked[targetID].tableID {
+			freeTableIDs = append(freeTableIDs, int(sourceTable.Int64))
+		}
+	}
+
+	// Meja sumber kembali available jika tidak ada order aktif lain di meja tersebut
+	if len(freeTableIDs) > 0 {
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE tables t SET status = 'available', updated_at = NOW()
+			WHERE t.id = ANY($1) AND t.status = 'occupied'
+				AND NOT EXISTS (
+					SELECT 1 FROM orders o WHERE o.table_id = t.id AND o.status IN ('open', 'transferred')
+				)
+			RETURNING t.id, t.table_number
+		`, pq.Array(freeTableIDs))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var t models.FreedTable
+			if err := rows.Scan(&t.TableID, &t.TableNumber); err != nil {
+				return nil, err
+			}
+			result.FreedTables = append(result.FreedTables, t)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// lockEditableOrder mengunci baris order dan memastikan order masih berjalan (open/transferred),
+// mengembalikan waiter_id dan status order
+func lockEditableOrder(ctx context.Context, tx *sql.Tx, orderID int) (int, string, error) {
+	var waiterID int
+	var status string
+	err := tx.QueryRowContext(ctx, `
+		SELECT waiter_id, status FROM orders WHERE id = $1 FOR UPDATE
+	`, orderID).Scan(&waiterID, &status)
+	if err != nil {
+		return 0, "", err
+	}
+	if !models.IsLiveOrder(status) {
+		return 0, "", ErrOrderNotEditable
+	}
+	return waiterID, status, nil
+}
+
+// ensureUnbilled memastikan order belum punya bill aktif (bukan void / induk split). Item yang berubah setelah
+// ditagih tidak akan ikut di bill lama, sehingga order bisa di-settle dengan item yang tidak pernah dibayar.
+func ensureUnbilled(ctx context.Context, tx *sql.Tx, orderID int) error {
+	var active int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bills WHERE order_id = $1 AND status NOT IN ('void', 'split')
+	`, orderID).Scan(&active)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return fmt.Errorf("%w: order %d", ErrOrderBilled, orderID)
+	}
+	return nil
+}
+
+// ensureSettleable memastikan order punya bill aktif (bukan void / induk split) dan semuanya sudah lunas.
+// Dipanggil setelah baris order dikunci.
+func ensureSettleable(ctx context.Context, tx *sql.Tx, orderID int) error {
+	var unpaid, total int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status NOT IN ('paid', 'refunded')), COUNT(*)
+		FROM bills
+		WHERE order_id = $1 AND status NOT IN ('void', 'split')
+	`, orderID).Scan(&unpaid, &total)
+	if err != nil {
+		return err
+	}
+	if total == 0 || unpaid > 0 {
+		return fmt.Errorf("%w: order %d", ErrOrderUnpaid, orderID)
+	}
+	return nil
+}
+
+// setOrderStatus mengubah status hanya jika status saat ini masih fromStatus, lalu mencatat riwayat
+func setOrderStatus(ctx context.Context, tx *sql.Tx, orderID int, fromStatus, toStatus string, staffID int, reason string) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE orders SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`, toStatus, orderID, fromStatus)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrStatusConflict
+	}
+
+	return insertStatusHistory(ctx, tx, orderID, fromStatus, toStatus, staffID, reason)
+}
+
+func insertStatusHistory(ctx context.Context, tx *sql.Tx, orderID int, fromStatus, toStatus string, staffID int, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+	`,
+		orderID,
+		sql.NullString{String: fromStatus, Valid: fromStatus != ""},
+		toStatus,
+		sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+		sql.NullString{String: reason, Valid: reason != ""},
+	)
+	return err
+}
+
+// consumeIngredients mengurangi stok setiap bahan menu untuk satu order item, kecuali bahan yang di-exclude,
+// ditambah bahan extra dari modifier yang dipilih
+func consumeIngredients(ctx context.Context, tx *sql.Tx, orderItemID, menuItemID int, qty float64, excluded []int, staffID int) error {
 	rows, err := tx.QueryContext(ctx, `
-		SELECT ingredient_id, qty
+		SELECT ingredient_id, qty, FALSE
 		FROM menu_ingredients
 		WHERE menu_item_id = $1
-	`, item.MenuItemID)
+		UNION ALL
+		SELECT mo.ingredient_id, mo.ingredient_qty, TRUE
+		FROM order_item_modifiers oim
+		JOIN modifier_options mo ON mo.id = oim.modifier_option_id
+		WHERE oim.order_item_id = $2 AND mo.ingredient_id IS NOT NULL
+	`, menuItemID, orderItemID)
 	if err != nil {
 		return err
 	}
+
+	var ingredients []IngredientUsage
 	for rows.Next() {
 		var ing IngredientUsage
-		if err := rows.Scan(&ing.IngredientID, &ing.UsedQty); err != nil {
+		if err := rows.Scan(&ing.IngredientID, &ing.UsedQty, &ing.IsExtra); err != nil {
 			rows.Close()
 			return err
 		}
@@ -379,45 +1216,113 @@ func (r *OrderRepository) AddItem(ctx context.Context, orderID int, item *models
 	}
 	rows.Close()
 
-	// 4. Proses stok dan update ingredients
 	for _, ing := range ingredients {
-		// Skip jika termasuk dalam excluded
-		if slices.Contains(item.ExcludedIngredientIDs, ing.IngredientID) {
+		// Bahan extra dari modifier tetap dipakai walaupun bahan bawaan yang sama di-exclude
+		if !ing.IsExtra && slices.Contains(excluded, ing.IngredientID) {
 			continue
 		}
 
-		// Ambil stok
-		var currentQty float64
-		err := tx.QueryRowContext(ctx, `
-			SELECT qty FROM ingredients WHERE id = $1
-		`, ing.IngredientID).Scan(&currentQty)
+		err := applyStockMovement(ctx, tx, &models.StockMovement{
+			IngredientID: ing.IngredientID,
+			MovementType: models.StockSale,
+			QtyChange:    -ing.UsedQty * qty,
+			OrderItemID:  sql.NullInt64{Int64: int64(orderItemID), Valid: true},
+			StaffID:      sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0},
+		})
 		if err != nil {
 			return err
 		}
+	}
 
-		totalNeeded := ing.UsedQty * item.Qty
-		if currentQty < totalNeeded {
-			return fmt.Errorf("stok tidak cukup untuk bahan id %d", ing.IngredientID)
+	return nil
+}
+
+// reverseIngredients mengembalikan sisa pengurangan stok dari ledger untuk satu order item.
+// Hanya bahan yang benar-benar dikurangi saat order (bahan excluded tidak pernah tercatat) yang dikembalikan.
+func reverseIngredients(ctx context.Context, tx *sql.Tx, orderItemID, staffID int, asWaste bool, reason string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT ingredient_id, movement_type, qty_change
+		FROM stock_movements
+		WHERE order_item_id = $1
+		ORDER BY id
+	`, orderItemID)
+	if err != nil {
+		return err
+	}
+
+	var ledger []models.StockMovement
+	for rows.Next() {
+		var m models.StockMovement
+		if err := rows.Scan(&m.IngredientID, &m.MovementType, &m.QtyChange); err != nil {
+			rows.Close()
+			return err
 		}
+		ledger = append(ledger, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	consumed := outstandingUsage(ledger)
 
-		// Kurangi stok
-		_, err = tx.ExecContext(ctx, `
-			UPDATE ingredients
-			SET qty = qty - $1
-			WHERE id = $2
-		`, totalNeeded, ing.IngredientID)
+	itemRef := sql.NullInt64{Int64: int64(orderItemID), Valid: true}
+	staffRef := sql.NullInt64{Int64: int64(staffID), Valid: staffID != 0}
+	notes := sql.NullString{String: reason, Valid: reason != ""}
+
+	for _, ing := range consumed {
+		err := applyStockMovement(ctx, tx, &models.StockMovement{
+			IngredientID: ing.IngredientID,
+			MovementType: models.StockVoidReversal,
+			QtyChange:    ing.UsedQty,
+			OrderItemID:  itemRef,
+			StaffID:      staffRef,
+			Notes:        notes,
+		})
 		if err != nil {
 			return err
 		}
+
+		// Sudah terlanjur dibuat: stok tetap terpakai tetapi tercatat sebagai waste
+		if asWaste {
+			err = applyStockMovement(ctx, tx, &models.StockMovement{
+				IngredientID: ing.IngredientID,
+				MovementType: models.StockWaste,
+				QtyChange:    -ing.UsedQty,
+				OrderItemID:  itemRef,
+				StaffID:      staffRef,
+				Notes:        notes,
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-func (r *OrderRepository) SoftDelete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE orders SET status = 'void', updated_at = NOW()
-		WHERE id = $1
-	`, id)
-	return err
+// outstandingUsage menghitung per bahan stok yang masih terpakai oleh satu order item menurut ledger-nya:
+// pengurangan sale dikurangi void_reversal yang sudah tercatat. Waste tidak dihitung karena bukan pengembalian,
+// sehingga void kedua (misalnya item setelah order-nya di-void) tidak mengembalikan stok lagi.
+func outstandingUsage(ledger []models.StockMovement) []IngredientUsage {
+	used := map[int]float64{}
+	var order []int
+	for _, m := range ledger {
+		if m.MovementType != models.StockSale && m.MovementType != models.StockVoidReversal {
+			continue
+		}
+		if _, ok := used[m.IngredientID]; !ok {
+			order = append(order, m.IngredientID)
+		}
+		used[m.IngredientID] -= m.QtyChange
+	}
+
+	var out []IngredientUsage
+	for _, id := range order {
+		// qty_change DECIMAL(10,2), bulatkan agar sisa pembulatan float tidak dikembalikan
+		if qty := math.Round(used[id]*100) / 100; qty > 0 {
+			out = append(out, IngredientUsage{IngredientID: id, UsedQty: qty})
+		}
+	}
+	return out
 }
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"pos-restaurant/models"
+)
+
+// voidLedger meniru reverseIngredients: catat void_reversal (dan waste jika asWaste) untuk sisa pemakaian
+func voidLedger(ledger []models.StockMovement, asWaste bool) []models.StockMovement {
+	for _, ing := range outstandingUsage(ledger) {
+		ledger = append(ledger, models.StockMovement{IngredientID: ing.IngredientID, MovementType: models.StockVoidReversal, QtyChange: ing.UsedQty})
+		if asWaste {
+			ledger = append(ledger, models.StockMovement{IngredientID: ing.IngredientID, MovementType: models.StockWaste, QtyChange: -ing.UsedQty})
+		}
+	}
+	return ledger
+}
+
+func stockChange(ledger []models.StockMovement) map[int]float64 {
+	out := map[int]float64{}
+	for _, m := range ledger {
+		out[m.IngredientID] += m.QtyChange
+	}
+	return out
+}
+
+// Ledger satu item: resep (bahan 1 & 2) ditambah bahan extra modifier yang sama dengan bahan resep
+func saleLedger() []models.StockMovement {
+	return []models.StockMovement{
+		{IngredientID: 1, MovementType: models.StockSale, QtyChange: -0.3},
+		{IngredientID: 2, MovementType: models.StockSale, QtyChange: -2},
+		{IngredientID: 1, MovementType: models.StockSale, QtyChange: -0.1},
+	}
+}
+
+func TestOutstandingUsage(t *testing.T) {
+	got := outstandingUsage(saleLedger())
+	want := []IngredientUsage{{IngredientID: 1, UsedQty: 0.4}, {IngredientID: 2, UsedQty: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("outstandingUsage = %+v, want %+v", got, want)
+	}
+	if got := outstandingUsage(nil); len(got) != 0 {
+		t.Errorf("ledger kosong = %+v, want kosong", got)
+	}
+}
+
+func TestVoidOrderThenItemRestoresStockOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		asWaste bool
+		want    map[int]float64 // perubahan stok bersih setelah kedua void
+	}{
+		{"stok kembali", false, map[int]float64{1: 0, 2: 0}},
+		{"dicatat waste", true, map[int]float64{1: -0.4, 2: -2}},
+	}
+	for _, tt := range tests {
+		// Void order lalu void item yang sama (atau void order dua kali): void kedua tidak boleh menambah movement
+		afterOrder := voidLedger(saleLedger(), tt.asWaste)
+		afterItem := voidLedger(afterOrder, false)
+		if len(afterItem) != len(afterOrder) {
+			t.Errorf("%s: void item setelah void order menambah %d movement", tt.name, len(afterItem)-len(afterOrder))
+		}
+
+		got := stockChange(afterItem)
+		for id, want := range tt.want {
+			if diff := got[id] - want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("%s: perubahan stok bahan %d = %v, want %v", tt.name, id, got[id], want)
+			}
+		}
+	}
+}
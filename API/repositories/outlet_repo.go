@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"pos-restaurant/models"
+
+	"github.com/lib/pq"
 )
 
 type OutletRepository struct {
@@ -16,16 +18,20 @@ func NewOutletRepository(db *sql.DB) *OutletRepository {
 }
 
 func (r *OutletRepository) Create(ctx context.Context, outlet *models.Outlet) (int, error) {
-	query := `INSERT INTO outlets (name, location, service_charge_percentage, tax_percentage, is_active) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO outlets (name, location, service_charge_percentage, tax_percentage,
+	          prices_include_tax, tax_on_service_charge, service_charge_exempt_types, rounding_unit, rounding_mode, currency, is_active) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
 	err := r.db.QueryRowContext(ctx, query,
-		outlet.Name, outlet.Location, outlet.ServiceChargePercent, outlet.TaxPercentage, outlet.IsActive,
+		outlet.Name, outlet.Location, outlet.ServiceChargePercent, outlet.TaxPercentage,
+		outlet.PricesIncludeTax, outlet.TaxOnServiceCharge, pq.Array(outlet.ServiceExemptTypes),
+		outlet.RoundingUnit, outlet.RoundingMode, outlet.Currency, outlet.IsActive,
 	).Scan(&outlet.ID)
 	return outlet.ID, err
 }
 
 func (r *OutletRepository) List(ctx context.Context) ([]*models.Outlet, error) {
-	query := `SELECT id, name, location, service_charge_percentage, tax_percentage, is_active 
+	query := `SELECT id, name, location, service_charge_percentage, tax_percentage,
+	          prices_include_tax, tax_on_service_charge, service_charge_exempt_types, rounding_unit, rounding_mode, currency, is_active 
 	          FROM outlets WHERE deleted_at IS NULL ORDER BY name`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -38,6 +44,8 @@ func (r *OutletRepository) List(ctx context.Context) ([]*models.Outlet, error) {
 		var outlet models.Outlet
 		err := rows.Scan(&outlet.ID, &outlet.Name, &outlet.Location,
 			&outlet.ServiceChargePercent, &outlet.TaxPercentage,
+			&outlet.PricesIncludeTax, &outlet.TaxOnServiceCharge, pq.Array(&outlet.ServiceExemptTypes),
+			&outlet.RoundingUnit, &outlet.RoundingMode, &outlet.Currency,
 			&outlet.IsActive)
 		if err != nil {
 			return nil, err
@@ -48,13 +56,16 @@ func (r *OutletRepository) List(ctx context.Context) ([]*models.Outlet, error) {
 }
 
 func (r *OutletRepository) GetByID(ctx context.Context, id int) (*models.Outlet, error) {
-	query := `SELECT id, name, location, service_charge_percentage, tax_percentage, is_active 
+	query := `SELECT id, name, location, service_charge_percentage, tax_percentage,
+	          prices_include_tax, tax_on_service_charge, service_charge_exempt_types, rounding_unit, rounding_mode, currency, is_active 
 	          FROM outlets WHERE id = $1 AND deleted_at IS NULL`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var outlet models.Outlet
 	err := row.Scan(&outlet.ID, &outlet.Name, &outlet.Location,
 		&outlet.ServiceChargePercent, &outlet.TaxPercentage,
+		&outlet.PricesIncludeTax, &outlet.TaxOnServiceCharge, pq.Array(&outlet.ServiceExemptTypes),
+		&outlet.RoundingUnit, &outlet.RoundingMode, &outlet.Currency,
 		&outlet.IsActive)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -67,9 +78,12 @@ func (r *OutletRepository) GetByID(ctx context.Context, id int) (*models.Outlet,
 
 func (r *OutletRepository) Update(ctx context.Context, outlet *models.Outlet) error {
 	query := `UPDATE outlets SET name=$1, location=$2, service_charge_percentage=$3, 
-	          tax_percentage=$4, is_active=$5, updated_at=NOW() WHERE id=$6 AND deleted_at IS NULL`
+	          tax_percentage=$4, prices_include_tax=$5, tax_on_service_charge=$6, service_charge_exempt_types=$7,
+	          rounding_unit=$8, rounding_mode=$9, currency=$10, is_active=$11, updated_at=NOW() WHERE id=$12 AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query,
-		outlet.Name, outlet.Location, outlet.ServiceChargePercent, outlet.TaxPercentage, outlet.IsActive, outlet.ID)
+		outlet.Name, outlet.Location, outlet.ServiceChargePercent, outlet.TaxPercentage,
+		outlet.PricesIncludeTax, outlet.TaxOnServiceCharge, pq.Array(outlet.ServiceExemptTypes),
+		outlet.RoundingUnit, outlet.RoundingMode, outlet.Currency, outlet.IsActive, outlet.ID)
 	return err
 }
 
@@ -78,3 +92,113 @@ func (r *OutletRepository) SoftDelete(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+func (r *OutletRepository) ListCategoryTaxRates(ctx context.Context, outletID int) ([]models.CategoryTaxRate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ct.category_id, mc.name, ct.tax_percentage
+		FROM category_tax_rates ct
+		JOIN menu_categories mc ON mc.id = ct.category_id
+		WHERE ct.outlet_id = $1
+		ORDER BY mc.name
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.CategoryTaxRate{}
+	for rows.Next() {
+		var rate models.CategoryTaxRate
+		if err := rows.Scan(&rate.CategoryID, &rate.CategoryName, &rate.TaxPercentage); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// ReplaceCategoryTaxRates mengganti seluruh tarif pajak kategori outlet; kategori yang tidak disebut
+// kembali memakai outlets.tax_percentage
+func (r *OutletRepository) ReplaceCategoryTaxRates(ctx context.Context, outletID int, rates []models.CategoryTaxRate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE outlets SET updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, outletID)
+	if err := requireAffected(res, err); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM category_tax_rates WHERE outlet_id = $1`, outletID); err != nil {
+		return err
+	}
+	for _, rate := range rates {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO category_tax_rates (outlet_id, category_id, tax_percentage) VALUES ($1, $2, $3)
+		`, outletID, rate.CategoryID, rate.TaxPercentage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *OutletRepository) ListDiningPeriods(ctx context.Context, outletID int) ([]models.DiningPeriod, error) {
+	return diningPeriods(ctx, r.db, outletID)
+}
+
+func diningPeriods(ctx context.Context, q queryer, outletID int) ([]models.DiningPeriod, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT visit_type, to_char(first_seating, 'HH24:MI'), to_char(last_seating, 'HH24:MI'), duration_minutes
+		FROM outlet_dining_periods
+		WHERE outlet_id = $1
+		ORDER BY first_seating
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []models.DiningPeriod{}
+	for rows.Next() {
+		var p models.DiningPeriod
+		if err := rows.Scan(&p.VisitType, &p.FirstSeating, &p.LastSeating, &p.DurationMinutes); err != nil {
+			return nil, err
+		}
+		periods = append(periods, p)
+	}
+	return periods, rows.Err()
+}
+
+// ReplaceDiningPeriods mengganti seluruh periode makan outlet. Outlet tanpa periode tidak punya slot reservasi
+// dan lama makan reservasinya memakai durasi default.
+func (r *OutletRepository) ReplaceDiningPeriods(ctx context.Context, outletID int, periods []models.DiningPeriod) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE outlets SET updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, outletID)
+	if err := requireAffected(res, err); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM outlet_dining_periods WHERE outlet_id = $1`, outletID); err != nil {
+		return err
+	}
+	for _, p := range periods {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO outlet_dining_periods (outlet_id, visit_type, first_seating, last_seating, duration_minutes)
+			VALUES ($1, $2, $3, $4, $5)
+		`, outletID, p.VisitType, p.FirstSeating, p.LastSeating, p.DurationMinutes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -3,8 +3,19 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"pos-restaurant/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReservationOverlap      = errors.New("meja sudah dipesan pada waktu tersebut")
+	ErrTableCapacity           = errors.New("kapasitas meja kurang dari jumlah tamu")
+	ErrInvalidReservationTable = errors.New("meja reservasi tidak valid")
+	ErrReservationNotSeatable  = errors.New("reservasi tidak bisa didudukkan")
 )
 
 type ReservationRepository struct {
@@ -15,41 +26,124 @@ func NewReservationRepository(db *sql.DB) *ReservationRepository {
 	return &ReservationRepository{db: db}
 }
 
+// Create menyimpan reservasi beserta semua mejanya. Meja dikunci selama transaksi sehingga dua reservasi
+// yang bentrok di meja yang sama tidak bisa lolos bersamaan.
 func (r *ReservationRepository) Create(ctx context.Context, res *models.Reservation) (int, error) {
-
-	// Cek double booking
-	var exists bool
-	err := r.db.QueryRowContext(ctx, `
-		SELECT EXISTS (
-			SELECT 1 FROM reservations
-			WHERE table_id = $1 AND reservation_time = $2
-		)
-	`, res.TableID, res.ReservationTime).Scan(&exists)
-
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 
-	if exists {
-		return 0, fmt.Errorf("meja sudah dipesan pada waktu tersebut")
+	if err := checkReservationTables(ctx, tx, res); err != nil {
+		return 0, err
 	}
 
 	var id int
-	err = r.db.QueryRowContext(ctx, `
+	err = tx.QueryRowContext(ctx, `
 		INSERT INTO reservations (
-			customer_id, reservation_time, pax, table_id, status, special_request
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			customer_id, reservation_time, pax, table_id, visit_type, duration_minutes,
+			status, special_request, seated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $7 = 'seated' THEN NOW() END)
 		RETURNING id
 	`,
 		res.CustomerID,
 		res.ReservationTime,
 		res.Pax,
 		res.TableID,
+		res.VisitType,
+		res.DurationMinutes,
 		res.Status,
 		res.SpecialRequest,
 	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	if err := insertReservationTables(ctx, tx, id, res.TableIDs); err != nil {
+		return 0, err
+	}
 
-	return id, err
+	return id, tx.Commit()
+}
+
+// checkReservationTables mengunci meja reservasi lalu memastikan meja ada di outlet reservasi. Untuk reservasi
+// confirmed/seated meja juga tidak boleh out_of_order, harus cukup untuk jumlah tamu dan tidak bentrok.
+func checkReservationTables(ctx context.Context, tx *sql.Tx, res *models.Reservation) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, outlet_id, capacity, status
+		FROM tables
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`, pq.Array(res.TableIDs))
+	if err != nil {
+		return err
+	}
+	var (
+		found    int
+		capacity int
+	)
+	for rows.Next() {
+		var (
+			id, outletID, seats int
+			status              string
+		)
+		if err := rows.Scan(&id, &outletID, &seats, &status); err != nil {
+			rows.Close()
+			return err
+		}
+		if outletID != res.OutletID {
+			rows.Close()
+			return fmt.Errorf("%w: meja %d bukan milik outlet %d", ErrInvalidReservationTable, id, res.OutletID)
+		}
+		if status == models.TableOutOfOrder && models.ReservationHoldsTable(res.Status) {
+			rows.Close()
+			return fmt.Errorf("%w: meja %d sedang out of order", ErrInvalidReservationTable, id)
+		}
+		found++
+		capacity += seats
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if found != len(res.TableIDs) {
+		return fmt.Errorf("%w: meja %v tidak ditemukan", ErrInvalidReservationTable, res.TableIDs)
+	}
+	if !models.ReservationHoldsTable(res.Status) {
+		return nil // reservasi batal / no show / waiting tidak memakai meja
+	}
+	if capacity < res.Pax {
+		return fmt.Errorf("%w: %d kursi untuk %d tamu", ErrTableCapacity, capacity, res.Pax)
+	}
+
+	var conflictID int
+	err = tx.QueryRowContext(ctx, `
+		SELECT r.id
+		FROM reservations r
+		JOIN reservation_tables rt ON rt.reservation_id = r.id
+		WHERE rt.table_id = ANY($1) AND r.id <> $2 AND r.status IN ('confirmed', 'seated')
+			AND r.reservation_time < $4
+			AND r.reservation_time + make_interval(mins => r.duration_minutes) > $3
+		ORDER BY r.reservation_time
+		LIMIT 1
+	`, pq.Array(res.TableIDs), res.ID, res.ReservationTime, res.End()).Scan(&conflictID)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+	return fmt.Errorf("%w (bentrok dengan reservasi %d)", ErrReservationOverlap, conflictID)
+}
+
+func insertReservationTables(ctx context.Context, tx *sql.Tx, reservationID int, tableIDs []int) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reservation_tables (reservation_id, table_id)
+		SELECT $1, unnest($2::int[])
+		ON CONFLICT DO NOTHING
+	`, reservationID, pq.Array(tableIDs))
+	return err
 }
 
 func (r *ReservationRepository) List(ctx context.Context, sortBy string) ([]*models.ReservationWithDetails, error) {
@@ -68,7 +162,13 @@ func (r *ReservationRepository) List(ctx context.Context, sortBy string) ([]*mod
 	query := fmt.Sprintf(`
 		SELECT 
 			r.id, r.customer_id, c.name, r.reservation_time, r.pax, 
-			t.table_number, r.status, r.special_request
+			COALESCE((
+				SELECT string_agg(jt.table_number, '+' ORDER BY jt.table_number)
+				FROM reservation_tables rt
+				JOIN "tables" jt ON jt.id = rt.table_id
+				WHERE rt.reservation_id = r.id
+			), t.table_number),
+			r.duration_minutes, r.status, r.special_request
 		FROM reservations r
 		LEFT JOIN customers c ON r.customer_id = c.cust_id
 		LEFT JOIN "tables" t ON r.table_id = t.id
@@ -88,7 +188,7 @@ func (r *ReservationRepository) List(ctx context.Context, sortBy string) ([]*mod
 		err := rows.Scan(
 			&res.ID, &res.CustomerName, &res.CustomerName,
 			&res.ReservationTime, &res.Pax,
-			&res.TableNumber, &res.Status, &specialReq,
+			&res.TableNumber, &res.DurationMinutes, &res.Status, &specialReq,
 		)
 		if err != nil {
 			return nil, err
@@ -101,44 +201,294 @@ func (r *ReservationRepository) List(ctx context.Context, sortBy string) ([]*mod
 }
 
 func (r *ReservationRepository) GetByID(ctx context.Context, id int) (*models.Reservation, error) {
-	var res models.Reservation
+	var (
+		res      models.Reservation
+		tableIDs pq.Int64Array
+	)
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, customer_id, reservation_time, pax, table_id, status, special_request
-		FROM reservations WHERE id = $1
+		SELECT r.id, r.customer_id, COALESCE(t.outlet_id, 0), r.reservation_time, r.pax, r.table_id,
+			ARRAY(SELECT rt.table_id FROM reservation_tables rt WHERE rt.reservation_id = r.id ORDER BY rt.table_id),
+			r.visit_type, r.duration_minutes, r.status, r.special_request, r.seated_at, r.order_id,
+			r.created_at, r.updated_at
+		FROM reservations r
+		LEFT JOIN tables t ON t.id = r.table_id
+		WHERE r.id = $1
 	`, id).Scan(
 		&res.ID,
 		&res.CustomerID,
+		&res.OutletID,
 		&res.ReservationTime,
 		&res.Pax,
 		&res.TableID,
+		&tableIDs,
+		&res.VisitType,
+		&res.DurationMinutes,
 		&res.Status,
 		&res.SpecialRequest,
+		&res.SeatedAt,
+		&res.OrderID,
+		&res.CreatedAt,
+		&res.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	res.TableIDs = intSlice(tableIDs)
 	return &res, nil
 }
 
 func (r *ReservationRepository) Update(ctx context.Context, res *models.Reservation) error {
-	_, err := r.db.ExecContext(ctx, `
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkReservationTables(ctx, tx, res); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
 		UPDATE reservations SET
 			customer_id = $1, reservation_time = $2, pax = $3, table_id = $4,
-			status = $5, special_request = $6, updated_at = NOW()
-		WHERE id = $7
+			visit_type = $5, duration_minutes = $6,
+			status = $7, special_request = $8, updated_at = NOW(),
+			seated_at = CASE WHEN $7 = 'seated' THEN COALESCE(seated_at, NOW()) END
+		WHERE id = $9
 	`,
 		res.CustomerID,
 		res.ReservationTime,
 		res.Pax,
 		res.TableID,
+		res.VisitType,
+		res.DurationMinutes,
 		res.Status,
 		res.SpecialRequest,
 		res.ID,
 	)
-	return err
+	if err := requireAffected(result, err); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reservation_tables WHERE reservation_id = $1`, res.ID); err != nil {
+		return err
+	}
+	if err := insertReservationTables(ctx, tx, res.ID, res.TableIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *ReservationRepository) Delete(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM reservations WHERE id = $1`, id)
 	return err
 }
+
+// TablesOutlet mengambil outlet meja-meja reservasi, semua meja harus di outlet yang sama
+func (r *ReservationRepository) TablesOutlet(ctx context.Context, tableIDs []int) (int, error) {
+	var outletIDs pq.Int64Array
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ARRAY(SELECT DISTINCT outlet_id FROM tables WHERE id = ANY($1) AND deleted_at IS NULL)
+	`, pq.Array(tableIDs)).Scan(&outletIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(outletIDs) != 1 {
+		return 0, fmt.Errorf("%w: meja %v harus ada dan berada di satu outlet", ErrInvalidReservationTable, tableIDs)
+	}
+	return int(outletIDs[0]), nil
+}
+
+func (r *ReservationRepository) DiningPeriods(ctx context.Context, outletID int) ([]models.DiningPeriod, error) {
+	return diningPeriods(ctx, r.db, outletID)
+}
+
+// ReservableTables mengambil meja outlet yang bisa dipesan (bukan out_of_order) beserta meja bersebelahannya
+func (r *ReservationRepository) ReservableTables(ctx context.Context, outletID int) ([]models.ReservableTable, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.table_number, t.capacity, t.status,
+			ARRAY(
+				SELECT CASE WHEN a.table_id = t.id THEN a.adjacent_table_id ELSE a.table_id END
+				FROM table_adjacency a
+				WHERE t.id IN (a.table_id, a.adjacent_table_id)
+			)
+		FROM tables t
+		WHERE t.outlet_id = $1 AND t.deleted_at IS NULL AND t.status <> 'out_of_order'
+		ORDER BY t.capacity, t.table_number
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []models.ReservableTable{}
+	for rows.Next() {
+		var (
+			t        models.ReservableTable
+			adjacent pq.Int64Array
+		)
+		if err := rows.Scan(&t.ID, &t.TableNumber, &t.Capacity, &t.Status, &adjacent); err != nil {
+			return nil, err
+		}
+		t.Adjacent = intSlice(adjacent)
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// Bookings mengambil pemakaian meja outlet oleh reservasi confirmed/seated yang beririsan dengan [from, to).
+// excludeID dipakai saat reservasi diubah agar tidak bentrok dengan dirinya sendiri.
+func (r *ReservationRepository) Bookings(ctx context.Context, outletID int, from, to time.Time, excludeID int) ([]models.TableBooking, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.id, rt.table_id, r.reservation_time, r.reservation_time + make_interval(mins => r.duration_minutes)
+		FROM reservations r
+		JOIN reservation_tables rt ON rt.reservation_id = r.id
+		JOIN tables t ON t.id = rt.table_id
+		WHERE t.outlet_id = $1 AND r.id <> $4 AND r.status IN ('confirmed', 'seated')
+			AND r.reservation_time < $3
+			AND r.reservation_time + make_interval(mins => r.duration_minutes) > $2
+	`, outletID, from, to, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []models.TableBooking
+	for rows.Next() {
+		var b models.TableBooking
+		if err := rows.Scan(&b.ReservationID, &b.TableID, &b.Start, &b.End); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
+
+// MarkNoShows menandai no_show reservasi confirmed yang tamunya belum datang ReservedGraceTime setelah jam
+// reservasi, sama dengan batas meja ditandai reserved. Reservasi yang ditandai dikembalikan beserta mejanya
+// agar status meja bisa dihitung ulang.
+func (r *ReservationRepository) MarkNoShows(ctx context.Context) ([]*models.Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE reservations r SET status = 'no_show', updated_at = NOW()
+		WHERE r.status = 'confirmed' AND r.reservation_time < LOCALTIMESTAMP - make_interval(secs => $1)
+		RETURNING r.id, r.customer_id, r.reservation_time, r.pax,
+			COALESCE((SELECT t.outlet_id FROM tables t WHERE t.id = r.table_id), 0),
+			ARRAY(SELECT rt.table_id FROM reservation_tables rt WHERE rt.reservation_id = r.id ORDER BY rt.table_id)
+	`, ReservedGraceTime.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var marked []*models.Reservation
+	for rows.Next() {
+		var (
+			res      = models.Reservation{Status: models.ReservationNoShow}
+			tableIDs pq.Int64Array
+		)
+		if err := rows.Scan(&res.ID, &res.CustomerID, &res.ReservationTime, &res.Pax, &res.OutletID, &tableIDs); err != nil {
+			return nil, err
+		}
+		res.TableIDs = intSlice(tableIDs)
+		marked = append(marked, &res)
+	}
+	return marked, rows.Err()
+}
+
+// DueTableIDs mengambil meja yang statusnya bisa berubah karena waktu berjalan dalam rentang window terakhir:
+// jendela reserved reservasi confirmed mulai atau berakhir, atau batas SeatedHoldTime tamu seated terlewati.
+func (r *ReservationRepository) DueTableIDs(ctx context.Context, window time.Duration) ([]int, error) {
+	var tableIDs pq.Int64Array
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ARRAY(
+			SELECT DISTINCT rt.table_id
+			FROM reservations r
+			JOIN reservation_tables rt ON rt.reservation_id = r.id
+			WHERE (
+				r.status = 'confirmed' AND (
+					r.reservation_time - make_interval(secs => $2)
+						BETWEEN LOCALTIMESTAMP - make_interval(secs => $1) AND LOCALTIMESTAMP
+					OR r.reservation_time + make_interval(secs => $3)
+						BETWEEN LOCALTIMESTAMP - make_interval(secs => $1) AND LOCALTIMESTAMP
+				)
+			) OR (
+				r.status = 'seated'
+				AND r.seated_at + make_interval(secs => $4)
+					BETWEEN LOCALTIMESTAMP - make_interval(secs => $1) AND LOCALTIMESTAMP
+			)
+		)
+	`, window.Seconds(), ReservedLeadTime.Seconds(), ReservedGraceTime.Seconds(), SeatedHoldTime.Seconds()).Scan(&tableIDs)
+	if err != nil {
+		return nil, err
+	}
+	return intSlice(tableIDs), nil
+}
+
+// Seat mendudukkan tamu reservasi: kunjungan pelanggan (reservation_id) dan order dine-in dibuka di meja
+// seating, lalu reservasi menjadi seated dengan meja yang benar-benar dipakai dan order tersebut. Hanya reservasi confirmed, waiting
+// atau no_show (tamu terlambat) yang bisa didudukkan.
+func (r *ReservationRepository) Seat(ctx context.Context, id int, seating *models.Seating) (*models.SeatResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, customer_id, pax FROM reservations WHERE id = $1 FOR UPDATE
+	`, id).Scan(&status, &seating.CustomerID, &seating.Pax)
+	if err != nil {
+		return nil, err
+	}
+	if !models.ReservationSeatable(status) {
+		return nil, fmt.Errorf("%w: reservasi %d %s", ErrReservationNotSeatable, id, status)
+	}
+
+	seating.ReservationID = sql.NullInt64{Int64: int64(id), Valid: true}
+	result, err := seatParty(ctx, tx, seating)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reservations
+		SET status = 'seated', seated_at = NOW(), table_id = $2, order_id = $3, updated_at = NOW()
+		WHERE id = $1
+	`, id, result.TableIDs[0], result.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reservation_tables WHERE reservation_id = $1`, id); err != nil {
+		return nil, err
+	}
+	if err := insertReservationTables(ctx, tx, id, result.TableIDs); err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// Now mengambil jam dinding database (LOCALTIMESTAMP), jam yang sama dengan NOW() yang mengisi kolom waktu.
+// Dipakai sebagai "sekarang" setiap kali waktu reservasi dibandingkan di Go, bukan time.Now() proses.
+func (r *ReservationRepository) Now(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT LOCALTIMESTAMP`).Scan(&now)
+	return now, err
+}
+
+// WallClock membuang zona waktu t dan mengembalikan jam dindingnya dalam UTC, bentuk yang sama dengan kolom
+// TIMESTAMP tanpa zona yang dibaca driver. Jam reservasi dari klien (apa pun offset-nya) disimpan dan
+// dibandingkan apa adanya sebagai jam dinding database.
+func WallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+func intSlice(a pq.Int64Array) []int {
+	out := make([]int, len(a))
+	for i, v := range a {
+		out[i] = int(v)
+	}
+	return out
+}
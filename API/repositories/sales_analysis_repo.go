@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"pos-restaurant/models"
+	"time"
+)
+
+type SalesAnalysisRepository struct {
+	db *sql.DB
+}
+
+func NewSalesAnalysisRepository(db *sql.DB) *SalesAnalysisRepository {
+	return &SalesAnalysisRepository{db: db}
+}
+
+// Today mengambil tanggal hari ini menurut database (CURRENT_DATE), tanggal yang sama dengan NOW() yang
+// mengisi kolom waktu, bukan tanggal zona waktu proses Go
+func (r *SalesAnalysisRepository) Today(ctx context.Context) (time.Time, error) {
+	var today time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT CURRENT_DATE`).Scan(&today)
+	return today, err
+}
+
+// Aggregate menghitung ringkasan penjualan semua outlet untuk satu tanggal lalu meng-upsert ke
+// sales_analysis_daily, sehingga aman dijalankan berulang kali. Batas hari dihitung di database dari tanggal
+// tsb, jadi sama dengan jam NOW() yang mengisi kolom waktu, bukan zona waktu proses Go.
+//
+//   - total_sales & discount: subtotal bill (nilai item sebelum diskon, pajak dan service charge) berstatus
+//     paid/refunded dari order yang settled pada tanggal tsb, satu dasar dengan void_amount
+//   - total_covers: jumlah pax customer_visits pada tanggal tsb
+//   - void_amount: nilai item (harga + modifier) yang di-void pada tanggal tsb, termasuk item dari order yang di-void
+//   - refund_amount: pembayaran yang di-refund pada tanggal tsb (termasuk pajak), terpisah dari void dan dari
+//     tanggal penjualannya
+func (r *SalesAnalysisRepository) Aggregate(ctx context.Context, date time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH settled AS (
+			SELECT DISTINCT order_id
+			FROM order_status_history
+			WHERE to_status = 'settled' AND changed_at >= $1::date AND changed_at < $1::date + 1
+		),
+		voided_orders AS (
+			SELECT DISTINCT order_id
+			FROM order_status_history
+			WHERE to_status = 'void' AND changed_at >= $1::date AND changed_at < $1::date + 1
+		),
+		sales AS (
+			SELECT o.outlet_id, SUM(b.subtotal) AS total_sales, SUM(b.discount_amount) AS discount
+			FROM bills b
+			JOIN orders o ON o.id = b.order_id
+			JOIN settled s ON s.order_id = o.id
+			WHERE b.status IN ('paid', 'refunded')
+			GROUP BY o.outlet_id
+		),
+		voids AS (
+			SELECT o.outlet_id,
+				SUM(oi.qty * (COALESCE(oi.override_price, oi.unit_price) + oi.modifiers_price)) AS amount
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE (oi.voided_at >= $1::date AND oi.voided_at < $1::date + 1)
+				OR (oi.voided_at IS NULL AND o.status = 'void' AND o.id IN (SELECT order_id FROM voided_orders))
+			GROUP BY o.outlet_id
+		),
+		refunds AS (
+			SELECT o.outlet_id, SUM(r.amount) AS amount
+			FROM bill_refunds r
+			JOIN bills b ON b.id = r.bill_id
+			JOIN orders o ON o.id = b.order_id
+			WHERE r.refunded_at >= $1::date AND r.refunded_at < $1::date + 1 AND NOT r.is_reversal
+			GROUP BY o.outlet_id
+		),
+		covers AS (
+			SELECT outlet_id, SUM(COALESCE(pax, 0)) AS covers
+			FROM customer_visits
+			WHERE visit_date >= $1::date AND visit_date < $1::date + 1
+			GROUP BY outlet_id
+		)
+		INSERT INTO sales_analysis_daily (
+			outlet_id, analysis_date, total_sales, total_covers,
+			avg_spend_per_cover, discount_amount, void_amount, refund_amount
+		)
+		SELECT
+			ot.id, $1::date,
+			COALESCE(s.total_sales, 0),
+			COALESCE(c.covers, 0),
+			CASE WHEN COALESCE(c.covers, 0) > 0 THEN ROUND(COALESCE(s.total_sales, 0) / c.covers, 2) ELSE 0 END,
+			COALESCE(s.discount, 0),
+			COALESCE(v.amount, 0),
+			COALESCE(rf.amount, 0)
+		FROM outlets ot
+		LEFT JOIN sales s ON s.outlet_id = ot.id
+		LEFT JOIN voids v ON v.outlet_id = ot.id
+		LEFT JOIN refunds rf ON rf.outlet_id = ot.id
+		LEFT JOIN covers c ON c.outlet_id = ot.id
+		WHERE ot.deleted_at IS NULL
+		ON CONFLICT (outlet_id, analysis_date) DO UPDATE SET
+			total_sales = EXCLUDED.total_sales,
+			total_covers = EXCLUDED.total_covers,
+			avg_spend_per_cover = EXCLUDED.avg_spend_per_cover,
+			discount_amount = EXCLUDED.discount_amount,
+			void_amount = EXCLUDED.void_amount,
+			refund_amount = EXCLUDED.refund_amount,
+			updated_at = NOW()
+	`, date.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ListRange mengambil ringkasan harian dalam rentang [from, to), outletID 0 = semua outlet
+func (r *SalesAnalysisRepository) ListRange(ctx context.Context, outletID int, from, to time.Time) ([]*models.SalesAnalysisDaily, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, outlet_id, analysis_date, total_sales, total_covers,
+			avg_spend_per_cover, discount_amount, void_amount, refund_amount, created_at, updated_at
+		FROM sales_analysis_daily
+		WHERE analysis_date >= $1::date AND analysis_date < $2::date
+			AND ($3 = 0 OR outlet_id = $3)
+		ORDER BY analysis_date, outlet_id
+	`, from.Format("2006-01-02"), to.Format("2006-01-02"), outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*models.SalesAnalysisDaily{}
+	for rows.Next() {
+		var s models.SalesAnalysisDaily
+		err := rows.Scan(
+			&s.ID, &s.OutletID, &s.AnalysisDate, &s.TotalSales, &s.TotalCovers,
+			&s.AvgSpendPerCover, &s.DiscountAmount, &s.VoidAmount, &s.RefundAmount, &s.CreatedAt, &s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &s)
+	}
+	return result, rows.Err()
+}
+
+// CourseTimes menghitung waktu layanan per course untuk course yang sudah served dalam rentang [from, to),
+// outletID 0 = semua outlet
+func (r *SalesAnalysisRepository) CourseTimes(ctx context.Context, outletID int, from, to time.Time) ([]*models.CourseServiceTime, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.outlet_id, oc.course, COUNT(*),
+			AVG(EXTRACT(EPOCH FROM oc.served_at - oc.fired_at) / 60),
+			MAX(EXTRACT(EPOCH FROM oc.served_at - oc.fired_at) / 60),
+			AVG(EXTRACT(EPOCH FROM oc.fired_at - o.created_at) / 60)
+		FROM order_courses oc
+		JOIN orders o ON o.id = oc.order_id
+		WHERE oc.served_at IS NOT NULL AND oc.fired_at IS NOT NULL
+			AND oc.served_at >= $1 AND oc.served_at < $2
+			AND ($3 = 0 OR o.outlet_id = $3)
+		GROUP BY o.outlet_id, oc.course
+		ORDER BY o.outlet_id, CASE oc.course WHEN 'appetizer' THEN 1 WHEN 'main' THEN 2 ELSE 3 END
+	`, from, to, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*models.CourseServiceTime{}
+	for rows.Next() {
+		var t models.CourseServiceTime
+		err := rows.Scan(&t.OutletID, &t.Course, &t.Orders, &t.AvgFireToServe, &t.MaxFireToServe, &t.AvgOrderToFire)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &t)
+	}
+	return result, rows.Err()
+}
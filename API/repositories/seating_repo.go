@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTableNotFree     = errors.New("meja masih terisi")
+	ErrInvalidSeatTable = errors.New("meja tidak bisa dipakai")
+)
+
+// seatParty mengunci meja rombongan, memastikan meja masih kosong lalu mencatat kunjungan pelanggan dan
+// membuka order dine-in tanpa item di meja pertama. Dipakai saat antrian walk-in dipanggil dan saat tamu
+// reservasi datang, dalam transaksi pemanggil.
+func seatParty(ctx context.Context, tx *sql.Tx, seating *models.Seating) (*models.SeatResult, error) {
+	if len(seating.TableIDs) == 0 {
+		return nil, fmt.Errorf("%w: meja belum dipilih", ErrInvalidSeatTable)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.id, t.outlet_id, t.status,
+			EXISTS (SELECT 1 FROM orders o WHERE o.table_id = t.id AND o.status IN ('open', 'transferred'))
+		FROM tables t
+		WHERE t.id = ANY($1) AND t.deleted_at IS NULL
+		ORDER BY t.id
+		FOR UPDATE OF t
+	`, pq.Array(seating.TableIDs))
+	if err != nil {
+		return nil, err
+	}
+	found := 0
+	for rows.Next() {
+		var (
+			id, outletID int
+			status       string
+			hasOrder     bool
+		)
+		if err := rows.Scan(&id, &outletID, &status, &hasOrder); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		switch {
+		case outletID != seating.OutletID:
+			err = fmt.Errorf("%w: meja %d bukan milik outlet %d", ErrInvalidSeatTable, id, seating.OutletID)
+		case status == models.TableOutOfOrder:
+			err = fmt.Errorf("%w: meja %d sedang out of order", ErrInvalidSeatTable, id)
+		case hasOrder || status == models.TableOccupied:
+			err = fmt.Errorf("%w: meja %d", ErrTableNotFree, id)
+		}
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		found++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if found != len(seating.TableIDs) {
+		return nil, fmt.Errorf("%w: meja %v tidak ditemukan", ErrInvalidSeatTable, seating.TableIDs)
+	}
+
+	result := &models.SeatResult{TableIDs: seating.TableIDs, CustomerID: seating.CustomerID}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customer_visits (customer_id, visit_type, visit_date, reservation_id, outlet_id, pax)
+		VALUES ($1, $2, NOW(), $3, $4, $5)
+		RETURNING id
+	`, seating.CustomerID, seating.VisitType, seating.ReservationID, seating.OutletID, seating.Pax).Scan(&result.VisitID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE customers
+		SET visit_count = COALESCE(visit_count, 0) + 1, last_visit = NOW(), updated_at = NOW()
+		WHERE cust_id = $1
+	`, seating.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result.OrderNumber = uuid.NewString()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (order_number, table_id, customer_id, waiter_id, outlet_id, status, order_type)
+		VALUES ($1, $2, $3, $4, $5, $6, 'dine_in')
+		RETURNING id
+	`, result.OrderNumber, seating.TableIDs[0], seating.CustomerID, seating.WaiterID, seating.OutletID, models.OrderOpen,
+	).Scan(&result.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertStatusHistory(ctx, tx, result.OrderID, "", models.OrderOpen, seating.WaiterID, ""); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
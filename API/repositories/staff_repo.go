@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"pos-restaurant/models"
 )
 
@@ -23,6 +24,33 @@ func (r *StaffRepository) Create(ctx context.Context, staff *models.Staff) (int,
 	return id, err
 }
 
+// CreateFirstManager membuat staff manager aktif hanya jika belum ada manager aktif sama sekali,
+// mengembalikan 0 jika sudah ada
+func (r *StaffRepository) CreateFirstManager(ctx context.Context, name, pinHash string) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO staff (name, role, pin_code, is_active)
+		SELECT $1, 'manager', $2, TRUE
+		WHERE NOT EXISTS (
+			SELECT 1 FROM staff WHERE role = 'manager' AND is_active AND deleted_at IS NULL
+		)
+		RETURNING id
+	`, name, pinHash).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// LogFailedApproval mencatat persetujuan PIN yang gagal ke approval_failures
+func (r *StaffRepository) LogFailedApproval(ctx context.Context, approverID, requestedBy int, action, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO approval_failures (approver_id, requested_by, action, reason)
+		VALUES ($1, $2, $3, $4)
+	`, approverID, sql.NullInt64{Int64: int64(requestedBy), Valid: requestedBy != 0}, action, reason)
+	return err
+}
+
 func (r *StaffRepository) List(ctx context.Context) ([]*models.Staff, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, name, role, pin_code, is_active
@@ -59,7 +87,7 @@ func (r *StaffRepository) GetByID(ctx context.Context, id int) (*models.Staff, e
 
 func (r *StaffRepository) Update(ctx context.Context, s *models.Staff) error {
 	_, err := r.db.ExecContext(ctx, `
-		UPDATE staff SET name = $1, role = $2, pin_code = $3, is_active = $4, updated_at = NOW()
+		UPDATE staff SET name = $1, role = $2, pin_code = COALESCE(NULLIF($3, ''), pin_code), is_active = $4, updated_at = NOW()
 		WHERE id = $5 AND deleted_at IS NULL
 	`, s.Name, s.Role, s.PinCode, s.IsActive, s.ID)
 	return err
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"pos-restaurant/models"
+	"time"
+)
+
+var ErrInsufficientStock = errors.New("stok bahan tidak cukup")
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// qtyScale adalah presisi ingredients.qty dan stock_movements.qty_change (DECIMAL(x,2))
+const qtyScale = 100
+
+// applyStockMovement mencatat movement ke ledger dan menyesuaikan ingredients.qty dalam transaksi yang sama.
+// Semua perubahan stok harus lewat fungsi ini agar ledger selalu sinkron dengan qty.
+// QtyChange dibulatkan sekali di sini sehingga nilai yang ditulis ke kedua kolom sama persis.
+func applyStockMovement(ctx context.Context, tx *sql.Tx, m *models.StockMovement) error {
+	m.QtyChange = math.Round(m.QtyChange*qtyScale) / qtyScale
+
+	var currentQty float64
+	err := tx.QueryRowContext(ctx, `
+		SELECT qty FROM ingredients WHERE id = $1 FOR UPDATE
+	`, m.IngredientID).Scan(&currentQty)
+	if err != nil {
+		return err
+	}
+
+	if currentQty+m.QtyChange < 0 {
+		return fmt.Errorf("%w: bahan id %d", ErrInsufficientStock, m.IngredientID)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ingredients
+		SET qty = qty + $1, updated_at = NOW()
+		WHERE id = $2
+	`, m.QtyChange, m.IngredientID)
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO stock_movements (
+			ingredient_id, movement_type, qty_change, order_item_id, staff_id, notes
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, m.IngredientID, m.MovementType, m.QtyChange, m.OrderItemID, m.StaffID, m.Notes,
+	).Scan(&m.ID, &m.CreatedAt)
+}
+
+func (r *StockMovementRepository) Create(ctx context.Context, m *models.StockMovement) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := applyStockMovement(ctx, tx, m); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return m.ID, nil
+}
+
+// ListByIngredient mengambil movement dalam rentang [from, to)
+func (r *StockMovementRepository) ListByIngredient(ctx context.Context, ingredientID int, from, to time.Time) ([]*models.StockMovement, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, ingredient_id, movement_type, qty_change, order_item_id, staff_id, notes, created_at
+		FROM stock_movements
+		WHERE ingredient_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at, id
+	`, ingredientID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []*models.StockMovement
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(
+			&m.ID, &m.IngredientID, &m.MovementType, &m.QtyChange,
+			&m.OrderItemID, &m.StaffID, &m.Notes, &m.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, &m)
+	}
+	return movements, rows.Err()
+}
+
+func (r *StockMovementRepository) Reconcile(ctx context.Context, ingredientID int) (*models.StockReconciliation, error) {
+	rec := models.StockReconciliation{IngredientID: ingredientID}
+	// Selisih dihitung dalam DECIMAL di database agar tidak ada sisa pembulatan float
+	err := r.db.QueryRowContext(ctx, `
+		SELECT i.qty, COALESCE(SUM(sm.qty_change), 0), i.qty - COALESCE(SUM(sm.qty_change), 0),
+			i.qty = COALESCE(SUM(sm.qty_change), 0)
+		FROM ingredients i
+		LEFT JOIN stock_movements sm ON sm.ingredient_id = i.id
+		WHERE i.id = $1
+		GROUP BY i.qty
+	`, ingredientID).Scan(&rec.CurrentQty, &rec.LedgerQty, &rec.Difference, &rec.IsBalanced)
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
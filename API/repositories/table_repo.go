@@ -3,7 +3,22 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"pos-restaurant/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidLayout = errors.New("denah meja tidak valid")
+
+// Meja ditandai reserved mulai ReservedLeadTime sebelum jam reservasi confirmed sampai ReservedGraceTime setelahnya.
+// Tamu reservasi yang sudah seated menempati meja sampai order pertamanya dibuka, paling lama SeatedHoldTime.
+const (
+	ReservedLeadTime  = 30 * time.Minute
+	ReservedGraceTime = 15 * time.Minute
+	SeatedHoldTime    = 30 * time.Minute
 )
 
 type TableRepository struct {
@@ -15,19 +30,23 @@ func NewTableRepository(db *sql.DB) *TableRepository {
 }
 
 func (r *TableRepository) Create(ctx context.Context, table *models.Table) (int, error) {
+	if table.Shape == "" {
+		table.Shape = "square"
+	}
 	var id int
 	err := r.db.QueryRowContext(ctx, `
-		INSERT INTO tables (outlet_id, table_number, capacity, location_type, status)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO tables (outlet_id, table_number, capacity, location_type, status, section, shape, pos_x, pos_y)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`,
 		table.OutletID, table.TableNumber, table.Capacity, table.LocationType, table.Status,
+		table.Section, table.Shape, table.PosX, table.PosY,
 	).Scan(&id)
 	return id, err
 }
 
 func (r *TableRepository) List(ctx context.Context) ([]*models.Table, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, outlet_id, table_number, capacity, location_type, status
+		SELECT id, outlet_id, table_number, capacity, location_type, status, section, shape, pos_x, pos_y
 		FROM tables WHERE deleted_at IS NULL`)
 	if err != nil {
 		return nil, err
@@ -39,7 +58,7 @@ func (r *TableRepository) List(ctx context.Context) ([]*models.Table, error) {
 		var t models.Table
 		err := rows.Scan(
 			&t.ID, &t.OutletID, &t.TableNumber, &t.Capacity, &t.LocationType,
-			&t.Status,
+			&t.Status, &t.Section, &t.Shape, &t.PosX, &t.PosY,
 		)
 		if err != nil {
 			return nil, err
@@ -52,19 +71,26 @@ func (r *TableRepository) List(ctx context.Context) ([]*models.Table, error) {
 func (r *TableRepository) GetByID(ctx context.Context, id int) (*models.Table, error) {
 	var t models.Table
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, outlet_id, table_number, capacity, location_type, status
+		SELECT id, outlet_id, table_number, capacity, location_type, status, section, shape, pos_x, pos_y
 		FROM tables WHERE id = $1 AND deleted_at IS NULL`, id).
 		Scan(
 			&t.ID, &t.OutletID, &t.TableNumber, &t.Capacity, &t.LocationType,
-			&t.Status,
+			&t.Status, &t.Section, &t.Shape, &t.PosX, &t.PosY,
 		)
 	return &t, err
 }
 
+// Update menyimpan data meja. Status hanya berubah untuk out_of_order: diisi out_of_order menandai meja rusak,
+// diisi status lain melepas out_of_order (sementara available sampai dihitung ulang), kosong = tetap.
 func (r *TableRepository) Update(ctx context.Context, table *models.Table) error {
 	_, err := r.db.ExecContext(ctx, `
-		UPDATE tables SET outlet_id = $1, table_number = $2, capacity = $3,
-		                 location_type = $4, status = $5, updated_at = NOW()
+		UPDATE tables SET outlet_id = $1, table_number = $2, capacity = $3, location_type = $4,
+			status = CASE
+				WHEN $5 = 'out_of_order' THEN 'out_of_order'
+				WHEN $5 <> '' AND status = 'out_of_order' THEN 'available'
+				ELSE status
+			END,
+			updated_at = NOW()
 		WHERE id = $6 AND deleted_at IS NULL`,
 		table.OutletID, table.TableNumber, table.Capacity,
 		table.LocationType, table.Status, table.ID)
@@ -76,3 +102,306 @@ func (r *TableRepository) SoftDelete(ctx context.Context, id int) error {
 		UPDATE tables SET deleted_at = NOW() WHERE id = $1`, id)
 	return err
 }
+
+// SaveLayout menyimpan posisi meja di denah outlet sekaligus, semua meja harus milik outlet tersebut.
+// Meja yang mengisi adjacent diganti daftar meja sebelahnya (yang bisa digabung untuk rombongan besar).
+func (r *TableRepository) SaveLayout(ctx context.Context, outletID int, layout []models.TableLayout) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, l := range layout {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE tables SET section = $3, shape = COALESCE(NULLIF($4, ''), shape), pos_x = $5, pos_y = $6, updated_at = NOW()
+			WHERE id = $1 AND outlet_id = $2 AND deleted_at IS NULL
+		`, l.TableID, outletID, sql.NullString{String: l.Section, Valid: l.Section != ""}, l.Shape, l.PosX, l.PosY)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: meja %d tidak ada di outlet %d", ErrInvalidLayout, l.TableID, outletID)
+		}
+		if l.Adjacent != nil {
+			_, err := tx.ExecContext(ctx, `
+				DELETE FROM table_adjacency WHERE table_id = $1 OR adjacent_table_id = $1
+			`, l.TableID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Pasangan disisipkan setelah semua penghapusan agar urutan meja di payload tidak berpengaruh
+	for _, l := range layout {
+		for _, adj := range l.Adjacent {
+			if adj == l.TableID {
+				return fmt.Errorf("%w: meja %d tidak bisa bersebelahan dengan dirinya sendiri", ErrInvalidLayout, adj)
+			}
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO table_adjacency (table_id, adjacent_table_id)
+				SELECT LEAST($1::int, $2::int), GREATEST($1::int, $2::int)
+				FROM tables
+				WHERE id = $2 AND outlet_id = $3 AND deleted_at IS NULL
+				ON CONFLICT DO NOTHING
+			`, l.TableID, adj, outletID)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				var exists bool
+				err := tx.QueryRowContext(ctx, `
+					SELECT EXISTS (SELECT 1 FROM tables WHERE id = $1 AND outlet_id = $2 AND deleted_at IS NULL)
+				`, adj, outletID).Scan(&exists)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return fmt.Errorf("%w: meja sebelah %d tidak ada di outlet %d", ErrInvalidLayout, adj, outletID)
+				}
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RefreshStatus menghitung ulang status meja dari order dan reservasi:
+//   - occupied: ada order aktif (open/transferred), meja gabungan walk-in yang order rombongannya masih aktif,
+//     atau meja bagian reservasi seated yang rombongannya masih punya order aktif / baru duduk dan belum order
+//   - reserved: ada reservasi confirmed dalam jendela ReservedLeadTime sebelum s/d ReservedGraceTime setelah jam reservasi
+//   - available: selain itu
+//
+// Meja out_of_order tidak disentuh. Hanya meja yang statusnya berubah yang dikembalikan.
+func (r *TableRepository) RefreshStatus(ctx context.Context, tableIDs []int) ([]models.TableStatusChange, error) {
+	if len(tableIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH next AS (
+			SELECT t.id, t.status AS old_status,
+				CASE
+					WHEN EXISTS (
+						SELECT 1 FROM orders o WHERE o.table_id = t.id AND o.status IN ('open', 'transferred')
+					) THEN 'occupied'
+					WHEN EXISTS (
+						-- Meja gabungan tamu walk-in selama order rombongannya aktif
+						SELECT 1
+						FROM waitlist_entries w
+						JOIN orders o ON o.id = w.order_id
+						WHERE w.status = 'seated' AND t.id = ANY(w.table_ids) AND o.status IN ('open', 'transferred')
+					) THEN 'occupied'
+					WHEN EXISTS (
+						-- Meja gabungan reservasi yang sudah seated: terisi selama order rombongan masih aktif
+						-- di salah satu mejanya, atau order pertama belum dibuka dalam SeatedHoldTime
+						SELECT 1
+						FROM reservation_tables rt
+						JOIN reservations rs ON rs.id = rt.reservation_id AND rs.status = 'seated'
+						WHERE rt.table_id = t.id AND (
+							EXISTS (
+								SELECT 1 FROM reservation_tables rt2
+								JOIN orders o ON o.table_id = rt2.table_id
+								WHERE rt2.reservation_id = rs.id AND o.status IN ('open', 'transferred')
+									AND o.created_at >= rs.seated_at
+							)
+							OR (
+								rs.seated_at > LOCALTIMESTAMP - make_interval(secs => $4)
+								AND NOT EXISTS (
+									SELECT 1 FROM reservation_tables rt2
+									JOIN orders o ON o.table_id = rt2.table_id
+									WHERE rt2.reservation_id = rs.id AND o.created_at >= rs.seated_at
+								)
+							)
+						)
+					) THEN 'occupied'
+					WHEN EXISTS (
+						SELECT 1
+						FROM reservation_tables rt
+						JOIN reservations rs ON rs.id = rt.reservation_id
+						WHERE rt.table_id = t.id AND rs.status = 'confirmed'
+							AND rs.reservation_time BETWEEN LOCALTIMESTAMP - make_interval(secs => $3)
+								AND LOCALTIMESTAMP + make_interval(secs => $2)
+					) THEN 'reserved'
+					ELSE 'available'
+				END AS new_status
+			FROM tables t
+			WHERE t.id = ANY($1) AND t.deleted_at IS NULL AND t.status <> 'out_of_order'
+		)
+		UPDATE tables t SET status = next.new_status, updated_at = NOW()
+		FROM next
+		WHERE t.id = next.id AND next.old_status IS DISTINCT FROM next.new_status
+		RETURNING t.id, t.outlet_id, t.table_number, COALESCE(next.old_status, ''), t.status
+	`, pq.Array(tableIDs), ReservedLeadTime.Seconds(), ReservedGraceTime.Seconds(), SeatedHoldTime.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.TableStatusChange
+	for rows.Next() {
+		var c models.TableStatusChange
+		if err := rows.Scan(&c.TableID, &c.OutletID, &c.TableNumber, &c.From, &c.To); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// OrderTableIDs mengambil meja order beserta meja lain yang digabung untuk rombongan yang sama (reservasi
+// seated atau antrian walk-in). Kosong jika order tanpa meja (takeaway, delivery, room service).
+func (r *TableRepository) OrderTableIDs(ctx context.Context, orderID int) ([]int, error) {
+	var ids pq.Int64Array
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ARRAY(
+			SELECT o.table_id FROM orders o WHERE o.id = $1 AND o.table_id IS NOT NULL
+			UNION
+			SELECT rt2.table_id
+			FROM orders o
+			JOIN reservation_tables rt ON rt.table_id = o.table_id
+			JOIN reservations rs ON rs.id = rt.reservation_id AND rs.status = 'seated' AND o.created_at >= rs.seated_at
+			JOIN reservation_tables rt2 ON rt2.reservation_id = rs.id
+			WHERE o.id = $1
+			UNION
+			SELECT unnest(w.table_ids) FROM waitlist_entries w WHERE w.order_id = $1
+		)
+	`, orderID).Scan(&ids)
+	return intSlice(ids), err
+}
+
+// Floor menyusun tampilan denah outlet: setiap meja dengan order aktifnya, lama duduk, total berjalan
+// dan reservasi berikutnya hari ini. section kosong = semua area.
+func (r *TableRepository) Floor(ctx context.Context, outletID int, section string) ([]*models.FloorTable, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.table_number, t.capacity, COALESCE(t.section, ''), t.shape, t.pos_x, t.pos_y, t.status,
+			ARRAY(
+				SELECT CASE WHEN a.table_id = t.id THEN a.adjacent_table_id ELSE a.table_id END
+				FROM table_adjacency a
+				WHERE t.id IN (a.table_id, a.adjacent_table_id)
+			)
+		FROM tables t
+		WHERE t.outlet_id = $1 AND t.deleted_at IS NULL AND ($2 = '' OR t.section = $2)
+		ORDER BY t.section NULLS LAST, t.pos_y, t.pos_x, t.table_number
+	`, outletID, section)
+	if err != nil {
+		return nil, err
+	}
+	floor := []*models.FloorTable{}
+	byID := map[int]*models.FloorTable{}
+	for rows.Next() {
+		var (
+			t        = &models.FloorTable{Orders: []models.FloorOrder{}}
+			adjacent pq.Int64Array
+		)
+		err := rows.Scan(&t.TableID, &t.TableNumber, &t.Capacity, &t.Section, &t.Shape, &t.PosX, &t.PosY, &t.Status, &adjacent)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.Adjacent = intSlice(adjacent)
+		floor = append(floor, t)
+		byID[t.TableID] = t
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT o.id, o.order_number, o.table_id, COALESCE(w.name, ''), o.created_at,
+			FLOOR(EXTRACT(EPOCH FROM LOCALTIMESTAMP - o.created_at) / 60)::int,
+			(SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = o.id AND oi.voided_at IS NULL),
+			(SELECT COALESCE(SUM(b.paid_amount), 0) FROM bills b WHERE b.order_id = o.id AND b.status <> 'void')
+		FROM orders o
+		LEFT JOIN staff w ON w.id = o.waiter_id
+		WHERE o.outlet_id = $1 AND o.table_id IS NOT NULL AND o.status IN ('open', 'transferred')
+		ORDER BY o.created_at
+	`, outletID)
+	if err != nil {
+		return nil, err
+	}
+	// elapsed dihitung di database: created_at adalah TIMESTAMP tanpa zona dari NOW() sesi,
+	// jadi dibandingkan dengan LOCALTIMESTAMP sesi yang sama, bukan time.Now() di Go
+	var orders []struct {
+		tableID int
+		elapsed int
+		order   models.FloorOrder
+	}
+	for rows.Next() {
+		var (
+			o       models.FloorOrder
+			tableID int
+			elapsed int
+		)
+		if err := rows.Scan(&o.OrderID, &o.OrderNumber, &tableID, &o.Waiter, &o.OpenedAt, &elapsed, &o.Items, &o.Paid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		orders = append(orders, struct {
+			tableID int
+			elapsed int
+			order   models.FloorOrder
+		}{tableID, elapsed, o})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]int, 0, len(orders))
+	for _, entry := range orders {
+		if _, ok := byID[entry.tableID]; ok {
+			orderIDs = append(orderIDs, entry.order.OrderID)
+		}
+	}
+	totals, err := priceOrders(ctx, r.db, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung total order: %w", err)
+	}
+
+	for _, entry := range orders {
+		t, ok := byID[entry.tableID]
+		if !ok {
+			continue // meja di area lain
+		}
+		o := entry.order
+		o.Total = totals[o.OrderID].Total
+		if len(t.Orders) == 0 {
+			t.ElapsedMinutes = entry.elapsed
+		}
+		t.RunningTotal += o.Total
+		t.Orders = append(t.Orders, o)
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (rt.table_id)
+			rt.table_id, rs.id, COALESCE(c.name, ''), rs.reservation_time, rs.pax, rs.status
+		FROM reservations rs
+		JOIN reservation_tables rt ON rt.reservation_id = rs.id
+		JOIN tables t ON t.id = rt.table_id
+		LEFT JOIN customers c ON c.cust_id = rs.customer_id
+		WHERE t.outlet_id = $1 AND rs.status = 'confirmed'
+			AND rs.reservation_time >= LOCALTIMESTAMP - make_interval(secs => $2)
+			AND rs.reservation_time < CURRENT_DATE + 1
+		ORDER BY rt.table_id, rs.reservation_time
+	`, outletID, ReservedGraceTime.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tableID int
+			res     models.FloorReservation
+		)
+		if err := rows.Scan(&tableID, &res.ReservationID, &res.CustomerName, &res.ReservationTime, &res.Pax, &res.Status); err != nil {
+			return nil, err
+		}
+		if t, ok := byID[tableID]; ok {
+			t.NextReservation = &res
+		}
+	}
+	return floor, rows.Err()
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"pos-restaurant/models"
 )
 
@@ -21,6 +22,16 @@ func (r *TableTransferRepository) Create(ctx context.Context, t *models.TableTra
 	}
 	defer tx.Rollback()
 
+	// Hanya order yang masih berjalan yang bisa pindah meja, order open menjadi transferred
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, t.OrderID).Scan(&status)
+	if err != nil {
+		return 0, err
+	}
+	if !models.IsLiveOrder(status) {
+		return 0, fmt.Errorf("%w: order %d berstatus %s", ErrOrderNotEditable, t.OrderID, status)
+	}
+
 	var id int
 	err = tx.QueryRowContext(ctx, `
 		INSERT INTO table_transfers (
@@ -43,6 +54,16 @@ func (r *TableTransferRepository) Create(ctx context.Context, t *models.TableTra
 		return 0, err
 	}
 
+	if status == models.OrderOpen {
+		note := fmt.Sprintf("pindah meja %d ke %d", t.FromTableID, t.ToTableID)
+		if t.Reason.Valid {
+			note += ": " + t.Reason.String
+		}
+		if err := setOrderStatus(ctx, tx, t.OrderID, status, models.OrderTransferred, t.TransferredBy, note); err != nil {
+			return 0, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
@@ -50,6 +71,13 @@ func (r *TableTransferRepository) Create(ctx context.Context, t *models.TableTra
 	return id, nil
 }
 
+// GetOrderOutletID mengambil outlet dari order yang dipindah
+func (r *TableTransferRepository) GetOrderOutletID(ctx context.Context, orderID int) (int, error) {
+	var outletID int
+	err := r.db.QueryRowContext(ctx, `SELECT outlet_id FROM orders WHERE id = $1`, orderID).Scan(&outletID)
+	return outletID, err
+}
+
 func (r *TableTransferRepository) List(ctx context.Context) ([]models.TableTransfer, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, order_id, from_table_id, to_table_id, transferred_by, transferred_at, reason
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrWaitlistNotWaiting = errors.New("antrian sudah tidak menunggu")
+
+type WaitlistRepository struct {
+	db *sql.DB
+}
+
+func NewWaitlistRepository(db *sql.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+func (r *WaitlistRepository) Create(ctx context.Context, e *models.WaitlistEntry) (int, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO waitlist_entries (outlet_id, customer_id, guest_name, phone, pax, quoted_minutes, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at
+	`, e.OutletID, e.CustomerID, e.GuestName, e.Phone, e.Pax, e.QuotedMinutes, e.Notes, e.CreatedBy,
+	).Scan(&e.ID, &e.Status, &e.CreatedAt)
+	return e.ID, err
+}
+
+const waitlistColumns = `
+	id, outlet_id, customer_id, guest_name, phone, pax, status, quoted_minutes, notes,
+	table_ids, visit_id, order_id, created_by, created_at, seated_at,
+	(EXTRACT(EPOCH FROM (COALESCE(seated_at, LOCALTIMESTAMP) - created_at)) / 60)::int
+`
+
+func scanWaitlistEntry(row interface{ Scan(...any) error }) (*models.WaitlistEntry, error) {
+	var (
+		e        models.WaitlistEntry
+		tableIDs pq.Int64Array
+	)
+	err := row.Scan(&e.ID, &e.OutletID, &e.CustomerID, &e.GuestName, &e.Phone, &e.Pax, &e.Status,
+		&e.QuotedMinutes, &e.Notes, &tableIDs, &e.VisitID, &e.OrderID, &e.CreatedBy, &e.CreatedAt, &e.SeatedAt,
+		&e.WaitedMinutes)
+	if err != nil {
+		return nil, err
+	}
+	e.TableIDs = intSlice(tableIDs)
+	return &e, nil
+}
+
+func (r *WaitlistRepository) GetByID(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return scanWaitlistEntry(r.db.QueryRowContext(ctx, `
+		SELECT `+waitlistColumns+` FROM waitlist_entries WHERE id = $1
+	`, id))
+}
+
+// List mengambil antrian outlet urut waktu daftar. status kosong = semua antrian hari ini.
+func (r *WaitlistRepository) List(ctx context.Context, outletID int, status string) ([]*models.WaitlistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries
+		WHERE outlet_id = $1
+			AND (($2 = '' AND created_at >= CURRENT_DATE) OR status = $2)
+		ORDER BY created_at, id
+		LIMIT 200
+	`, outletID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.WaitlistEntry{}
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// TurnMinutes menghitung rata-rata lama meja dine-in dipakai (order dibuka sampai settled) 30 hari terakhir,
+// 0 jika belum ada data
+func (r *WaitlistRepository) TurnMinutes(ctx context.Context, outletID int) (int, error) {
+	var minutes int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(ROUND(AVG(EXTRACT(EPOCH FROM (h.changed_at - o.created_at)) / 60)), 0)::int
+		FROM orders o
+		JOIN order_status_history h ON h.order_id = o.id AND h.to_status = 'settled'
+		WHERE o.outlet_id = $1 AND o.order_type = 'dine_in' AND o.table_id IS NOT NULL
+			AND h.changed_at >= LOCALTIMESTAMP - INTERVAL '30 days'
+			AND h.changed_at - o.created_at < INTERVAL '6 hours'
+	`, outletID).Scan(&minutes)
+	return minutes, err
+}
+
+// TableTurns mengambil kondisi meja outlet untuk perkiraan waktu tunggu: sejak kapan meja terisi order aktif
+// dan sisa waktu reservasi confirmed/seated yang memakai meja dalam horizonMinutes ke depan
+func (r *WaitlistRepository) TableTurns(ctx context.Context, outletID, horizonMinutes int) ([]models.TableTurn, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.capacity,
+			(
+				SELECT (EXTRACT(EPOCH FROM (LOCALTIMESTAMP - MIN(o.created_at))) / 60)::int
+				FROM orders o
+				WHERE o.status IN ('open', 'transferred') AND (
+					o.table_id = t.id
+					OR EXISTS (
+						SELECT 1 FROM waitlist_entries w
+						WHERE w.order_id = o.id AND w.status = 'seated' AND t.id = ANY(w.table_ids)
+					)
+				)
+			),
+			(
+				SELECT (EXTRACT(EPOCH FROM (MAX(rs.reservation_time + make_interval(mins => rs.duration_minutes)) - LOCALTIMESTAMP)) / 60)::int
+				FROM reservation_tables rt
+				JOIN reservations rs ON rs.id = rt.reservation_id
+				WHERE rt.table_id = t.id AND rs.status IN ('confirmed', 'seated')
+					AND rs.reservation_time < LOCALTIMESTAMP + make_interval(mins => $2)
+					AND rs.reservation_time + make_interval(mins => rs.duration_minutes) > LOCALTIMESTAMP
+			)
+		FROM tables t
+		WHERE t.outlet_id = $1 AND t.deleted_at IS NULL AND t.status <> 'out_of_order'
+		ORDER BY t.capacity, t.id
+	`, outletID, horizonMinutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var turns []models.TableTurn
+	for rows.Next() {
+		var t models.TableTurn
+		if err := rows.Scan(&t.TableID, &t.Capacity, &t.OccupiedMinutes, &t.BookedMinutes); err != nil {
+			return nil, err
+		}
+		turns = append(turns, t)
+	}
+	return turns, rows.Err()
+}
+
+// Seat memanggil antrian: tamu tanpa data pelanggan dibuatkan customer, lalu kunjungan dan order dine-in
+// dibuka di meja yang dipilih. seating cukup berisi meja, waiter dan visit type.
+func (r *WaitlistRepository) Seat(ctx context.Context, id int, seating *models.Seating) (*models.SeatResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	e, err := scanWaitlistEntry(tx.QueryRowContext(ctx, `
+		SELECT `+waitlistColumns+` FROM waitlist_entries WHERE id = $1 FOR UPDATE
+	`, id))
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != models.WaitlistWaiting {
+		return nil, fmt.Errorf("%w: antrian %d %s", ErrWaitlistNotWaiting, id, e.Status)
+	}
+
+	if !e.CustomerID.Valid {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO customers (type, name, phone) VALUES ('non-guest', $1, $2) RETURNING cust_id
+		`, e.GuestName, e.Phone).Scan(&e.CustomerID.Int64)
+		if err != nil {
+			return nil, err
+		}
+		e.CustomerID.Valid = true
+	}
+
+	seating.OutletID = e.OutletID
+	seating.CustomerID = int(e.CustomerID.Int64)
+	seating.Pax = e.Pax
+	result, err := seatParty(ctx, tx, seating)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'seated', customer_id = $2, table_ids = $3, visit_id = $4, order_id = $5,
+			seated_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, result.CustomerID, pq.Array(result.TableIDs), result.VisitID, result.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// Remove mengeluarkan antrian yang masih menunggu (tamu pergi atau batal)
+func (r *WaitlistRepository) Remove(ctx context.Context, id int, status string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE waitlist_entries SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'waiting'
+	`, id, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	var current string
+	if err := r.db.QueryRowContext(ctx, `SELECT status FROM waitlist_entries WHERE id = $1`, id).Scan(&current); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: antrian %d %s", ErrWaitlistNotWaiting, id, current)
+}
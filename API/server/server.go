@@ -2,6 +2,9 @@ package server
 
 import (
 	"pos-restaurant/handlers"
+	"pos-restaurant/middleware"
+	"pos-restaurant/models"
+	"pos-restaurant/services"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,10 +15,16 @@ import (
 )
 
 func NewServer(
+	authService *services.AuthService,
+	authHandler *handlers.AuthHandler,
+
 	menuHandler *handlers.MenuItemHandler,
 	categoryHandler *handlers.MenuCategoryHandler,
 	ingredientHandler *handlers.IngredientHandler,
+	stockMovementHandler *handlers.StockMovementHandler,
 	menuIngredientHandler *handlers.MenuIngredientHandler,
+	modifierHandler *handlers.ModifierHandler,
+	comboHandler *handlers.ComboHandler,
 
 	outletHandler *handlers.OutletHandler,
 	tableHandler *handlers.TableHandler,
@@ -24,25 +33,50 @@ func NewServer(
 	customerHandler *handlers.CustomerHandler,
 	visitHandler *handlers.CustomerVisitHandler,
 	reservationHandler *handlers.ReservationHandler,
+	waitlistHandler *handlers.WaitlistHandler,
 
 	orderHandler *handlers.OrderHandler,
+	kitchenHandler *handlers.KitchenHandler,
 	billHandler *handlers.BillHandler,
 	tableTransferHandler *handlers.TableTransferHandler,
+	salesAnalysisHandler *handlers.SalesAnalysisHandler,
+	cashDrawerHandler *handlers.CashDrawerHandler,
+	eventHandler *handlers.EventHandler,
 ) *gin.Engine {
 
-	r := gin.Default()
+	// Sama dengan gin.Default(), tetapi query access_token dibuang sebelum URL dicatat logger
+	r := gin.New()
+	r.Use(middleware.StripQueryToken(), gin.Logger(), gin.Recovery())
 	api := r.Group("/api")
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Auth Routes (tanpa token)
+	api.POST("/auth/login", authHandler.Login)
+
+	// SSE: EventSource di browser tidak bisa mengirim header, satu-satunya route yang menerima query access_token
+	api.GET("/events", middleware.StreamAuthRequired(authService), eventHandler.Stream)
+
+	// Semua route di bawah ini membutuhkan token
+	api.Use(middleware.AuthRequired(authService))
+
+	// Hak akses per role
+	managerOnly := middleware.RequireRole(models.RoleManager)
+	backOffice := middleware.RequireRole(models.RoleManager, models.RoleSupervisor)
+	kitchen := middleware.RequireRole(models.RoleChef, models.RoleManager, models.RoleSupervisor)
+	frontOfHouse := middleware.RequireRole(models.RoleWaiter, models.RoleManager, models.RoleSupervisor)
+	cashierDesk := middleware.RequireRole(models.RoleCashier, models.RoleManager, models.RoleSupervisor)
+	billPrinting := middleware.RequireRole(models.RoleWaiter, models.RoleCashier, models.RoleManager, models.RoleSupervisor)
+	kitchenStaff := middleware.RequireRole(models.RoleChef, models.RoleWaiter, models.RoleManager, models.RoleSupervisor)
+
 	// Menu-items Routes
 	menu := api.Group("/menu")
 	{
 		// Admin use
-		menu.POST("/menu-items", menuHandler.CreateMenuItem)
+		menu.POST("/menu-items", backOffice, menuHandler.CreateMenuItem)
 		menu.GET("/menu-items", menuHandler.ListMenuItems)
-		menu.PUT("/menu-items/:id", menuHandler.UpdateMenuItem)
-		menu.DELETE("/menu-items/:id", menuHandler.DeleteMenuItem)
+		menu.PUT("/menu-items/:id", backOffice, menuHandler.UpdateMenuItem)
+		menu.DELETE("/menu-items/:id", managerOnly, menuHandler.DeleteMenuItem)
 
 		// Front use
 		menu.GET("/menu-items-active", menuHandler.ListActiveMenuItems)      // Show only active menu
@@ -51,59 +85,92 @@ func NewServer(
 
 		menu.GET("/menu-items/detail/:id", menuHandler.GetMenuDetail) // Show selected menu detail for ordering
 
-		menu.POST("/category", categoryHandler.CreateCategory)
+		menu.POST("/category", backOffice, categoryHandler.CreateCategory)
 		menu.GET("/category", categoryHandler.ListCategories)
-		menu.DELETE("/category/:id", categoryHandler.DeleteCategory)
+		menu.DELETE("/category/:id", managerOnly, categoryHandler.DeleteCategory)
 
 	}
 
 	// Ingredients Routes
 	ingredient := api.Group("/ingredients")
 	{
-		ingredient.POST("/", ingredientHandler.CreateIngredient)
+		ingredient.POST("/", kitchen, ingredientHandler.CreateIngredient)
 		ingredient.GET("/", ingredientHandler.ListIngredients)
 		ingredient.GET("/:id", ingredientHandler.GetIngredientByID)
-		ingredient.PUT("/:id", ingredientHandler.UpdateIngredient)
-		ingredient.DELETE("/:id", ingredientHandler.DeleteIngredient)
+		ingredient.PUT("/:id", kitchen, ingredientHandler.UpdateIngredient)
+		ingredient.DELETE("/:id", managerOnly, ingredientHandler.DeleteIngredient)
+
+		// Ledger stok
+		ingredient.GET("/:id/movements", stockMovementHandler.ListByIngredient) // ?from=2025-01-01&to=2025-01-31
+		ingredient.POST("/:id/movements", kitchen, stockMovementHandler.Create) // receiving, waste, adjustment
+		ingredient.GET("/:id/reconcile", backOffice, stockMovementHandler.Reconcile)
 	}
 
 	// Menu <-> Ingredients Routes
 	menuIngredient := api.Group("/menu-ingredients")
 	{
-		menuIngredient.POST("/", menuIngredientHandler.Create)
+		menuIngredient.POST("/", backOffice, menuIngredientHandler.Create)
 		menuIngredient.GET("/:menu_item_id", menuIngredientHandler.ListByMenuItem)
-		menuIngredient.PUT("/:id", menuIngredientHandler.UpdateMenuIngredient)
-		menuIngredient.DELETE("/:id", menuIngredientHandler.DeleteMenuIngredient)
+		menuIngredient.PUT("/:id", backOffice, menuIngredientHandler.UpdateMenuIngredient)
+		menuIngredient.DELETE("/:id", backOffice, menuIngredientHandler.DeleteMenuIngredient)
+	}
+
+	// Modifier Routes (ukuran, kematangan, extra topping)
+	modifiers := api.Group("/modifiers")
+	{
+		modifiers.GET("/menu-items/:menu_item_id", modifierHandler.ListByMenuItem)
+		modifiers.POST("/groups", backOffice, modifierHandler.CreateGroup)
+		modifiers.PUT("/groups/:id", backOffice, modifierHandler.UpdateGroup)
+		modifiers.DELETE("/groups/:id", backOffice, modifierHandler.DeleteGroup)
+		modifiers.POST("/groups/:id/options", backOffice, modifierHandler.CreateOption)
+		modifiers.PUT("/options/:id", backOffice, modifierHandler.UpdateOption)
+		modifiers.DELETE("/options/:id", backOffice, modifierHandler.DeleteOption)
+	}
+
+	// Combo / Set Menu Routes
+	combos := api.Group("/combos")
+	{
+		combos.POST("/", backOffice, comboHandler.Create)
+		combos.GET("/", comboHandler.List) // ?active=true
+		combos.GET("/:id", comboHandler.GetByID)
+		combos.PUT("/:id", backOffice, comboHandler.Update)
+		combos.DELETE("/:id", managerOnly, comboHandler.Delete)
 	}
 
 	// Outlet Routes
 	outlet := api.Group("/outlets")
 	{
-		outlet.POST("/", outletHandler.Create)
+		outlet.POST("/", managerOnly, outletHandler.Create)
 		outlet.GET("/", outletHandler.List)
 		outlet.GET("/:id", outletHandler.GetByID)
-		outlet.PUT("/:id", outletHandler.Update)
-		outlet.DELETE("/:id", outletHandler.Delete)
+		outlet.PUT("/:id", managerOnly, outletHandler.Update)
+		outlet.DELETE("/:id", managerOnly, outletHandler.Delete)
+		outlet.GET("/:id/category-taxes", outletHandler.ListCategoryTaxes)
+		outlet.PUT("/:id/category-taxes", managerOnly, outletHandler.ReplaceCategoryTaxes)
+		outlet.GET("/:id/dining-periods", outletHandler.ListDiningPeriods)
+		outlet.PUT("/:id/dining-periods", managerOnly, outletHandler.ReplaceDiningPeriods)
 	}
 
 	// Table Routes
 	table := api.Group("tables")
 	{
-		table.POST("/", tableHandler.Create)
+		table.POST("/", backOffice, tableHandler.Create)
 		table.GET("/", tableHandler.List)
+		table.GET("/floor", tableHandler.Floor)
+		table.PUT("/layout", backOffice, tableHandler.SaveLayout)
 		table.GET("/:id", tableHandler.GetByID)
-		table.PUT("/:id", tableHandler.Update)
-		table.DELETE("/:id", tableHandler.Delete)
+		table.PUT("/:id", frontOfHouse, tableHandler.Update)
+		table.DELETE("/:id", backOffice, tableHandler.Delete)
 	}
 
 	// Staff Routes
 	staff := api.Group("/staff")
 	{
-		staff.POST("/", staffHandler.Create)
-		staff.GET("/", staffHandler.List)
-		staff.GET("/:id", staffHandler.GetByID)
-		staff.PUT("/:id", staffHandler.Update)
-		staff.DELETE("/:id", staffHandler.SoftDelete)
+		staff.POST("/", managerOnly, staffHandler.Create)
+		staff.GET("/", backOffice, staffHandler.List)
+		staff.GET("/:id", backOffice, staffHandler.GetByID)
+		staff.PUT("/:id", managerOnly, staffHandler.Update)
+		staff.DELETE("/:id", managerOnly, staffHandler.SoftDelete)
 	}
 
 	customer := api.Group("/customers")
@@ -112,7 +179,7 @@ func NewServer(
 		customer.GET("/", customerHandler.List)
 		customer.GET("/:id", customerHandler.GetByID)
 		customer.PUT("/:id", customerHandler.Update)
-		customer.DELETE("/:id", customerHandler.SoftDelete)
+		customer.DELETE("/:id", backOffice, customerHandler.SoftDelete)
 	}
 
 	visits := api.Group("/visits")
@@ -121,48 +188,110 @@ func NewServer(
 		visits.GET("/", visitHandler.List)
 		visits.GET("/:id", visitHandler.GetByID)
 		visits.PUT("/:id", visitHandler.Update)
-		visits.DELETE("/:id", visitHandler.Delete)
+		visits.DELETE("/:id", backOffice, visitHandler.Delete)
 	}
 
 	group := api.Group("/reservations")
 	{
 		group.POST("/", reservationHandler.Create)
 		group.GET("/", reservationHandler.List)
+		group.GET("/availability", reservationHandler.Availability)
 		group.GET("/:id", reservationHandler.GetByID)
 		group.PUT("/:id", reservationHandler.Update)
-		group.DELETE("/:id", reservationHandler.Delete)
+		group.POST("/:id/seat", frontOfHouse, reservationHandler.Seat)
+		group.DELETE("/:id", backOffice, reservationHandler.Delete)
+	}
+
+	// Antrian walk-in
+	waitlist := api.Group("/waitlist")
+	{
+		waitlist.POST("/", frontOfHouse, waitlistHandler.Create)
+		waitlist.GET("/", waitlistHandler.List)
+		waitlist.GET("/quote", waitlistHandler.Quote)
+		waitlist.GET("/:id", waitlistHandler.GetByID)
+		waitlist.POST("/:id/seat", frontOfHouse, waitlistHandler.Seat)
+		waitlist.POST("/:id/remove", frontOfHouse, waitlistHandler.Remove)
 	}
 
 	// Orders
 	orders := api.Group("/orders")
 	{
-		orders.POST("/", orderHandler.Create)
+		orders.POST("/", frontOfHouse, orderHandler.Create)
 		orders.GET("/", orderHandler.List)
 		orders.GET("/:id", orderHandler.GetByID)
-		orders.PUT("/:id", orderHandler.Update)
-		orders.POST("/:id/add", orderHandler.AddItem)
-		orders.DELETE("/:id", orderHandler.Delete)
+		orders.PUT("/:id", frontOfHouse, orderHandler.Update)
+		orders.POST("/:id/add", frontOfHouse, orderHandler.AddItem)
+		orders.DELETE("/:id", backOffice, orderHandler.Delete)
+		orders.POST("/:id/items/:item_id/void", backOffice, orderHandler.VoidItem)
+		orders.POST("/:id/status", frontOfHouse, orderHandler.ChangeStatus)
+		orders.GET("/:id/history", orderHandler.StatusHistory)
+		orders.GET("/:id/courses", orderHandler.Courses)
+		orders.POST("/merge", frontOfHouse, orderHandler.Merge)
+		orders.GET("/:id/merges", orderHandler.Merges)
+		orders.POST("/:id/fire", frontOfHouse, orderHandler.Fire)
+	}
+
+	// Kitchen Display System
+	kds := api.Group("/kitchen")
+	{
+		kds.POST("/stations", backOffice, kitchenHandler.CreateStation)
+		kds.GET("/stations", kitchenHandler.ListStations)
+		kds.PUT("/stations/:id/categories", backOffice, kitchenHandler.AssignCategories)
+		kds.PUT("/stations/:id/printer", backOffice, kitchenHandler.SetStationPrinter)
+
+		kds.GET("/queue", kitchenHandler.Queue) // ?station_id=1&outlet_id=1
+		kds.POST("/items/:id/bump", kitchenStaff, kitchenHandler.Bump)
+
+		kds.GET("/print-jobs", kitchenHandler.PrintJobs) // ?status=failed&outlet_id=1
+		kds.POST("/print-jobs/:id/retry", kitchenStaff, kitchenHandler.RetryPrintJob)
+		kds.POST("/tickets/:id/reprint", kitchenStaff, kitchenHandler.ReprintTicket)
 	}
 
 	// Bill
 	bills := api.Group("/bills")
 	{
-		bills.POST("/", billHandler.Create)
-		bills.POST("/split", billHandler.CreateSplit)
+		bills.POST("/", cashierDesk, billHandler.Create)
+		bills.POST("/split", cashierDesk, billHandler.CreateSplit)
+		bills.POST("/merge", cashierDesk, billHandler.Merge)
+		bills.GET("/preview", cashierDesk, billHandler.Preview)
 		bills.GET("/", billHandler.List)
 		bills.GET("/:id", billHandler.GetByID)
-		bills.DELETE("/:id", billHandler.Delete)
+		bills.DELETE("/:id", managerOnly, billHandler.Delete)
 
-		bills.POST("/pay", billHandler.Pay)
+		bills.POST("/pay", cashierDesk, billHandler.Pay)
+		bills.POST("/refund", cashierDesk, billHandler.Refund)
+		bills.GET("/:id/refunds", billHandler.ListRefunds)
+		bills.GET("/:id/receipt", billPrinting, billHandler.Receipt)
+		bills.GET("/room-guest/:room", cashierDesk, billHandler.LookupRoomGuest)
+	}
+
+	// Cash drawer / shift kasir
+	drawers := api.Group("/cash-drawers")
+	{
+		drawers.POST("/open", cashierDesk, cashDrawerHandler.Open)
+		drawers.GET("/current", cashierDesk, cashDrawerHandler.Current)
+		drawers.POST("/:id/close", cashierDesk, cashDrawerHandler.Close)
+		drawers.GET("/", backOffice, cashDrawerHandler.List) // ?outlet_id=1&status=open
+		drawers.GET("/:id/report", backOffice, cashDrawerHandler.Report)
 	}
 
 	tabletf := api.Group("/table-transfer")
 	{
-		tabletf.POST("/", tableTransferHandler.Create)
+		tabletf.POST("/", frontOfHouse, tableTransferHandler.Create)
 		tabletf.GET("/", tableTransferHandler.List)
 		tabletf.GET("/:id", tableTransferHandler.GetByID)
-		tabletf.PUT("/:id", tableTransferHandler.Update)
-		tabletf.DELETE("/:id", tableTransferHandler.Delete)
+		tabletf.PUT("/:id", frontOfHouse, tableTransferHandler.Update)
+		tabletf.DELETE("/:id", backOffice, tableTransferHandler.Delete)
+	}
+
+	// Report Routes
+	reports := api.Group("/reports", backOffice)
+	{
+		reports.GET("/sales-daily", salesAnalysisHandler.List)         // ?outlet_id=1&from=2025-01-01&to=2025-01-31
+		reports.POST("/sales-daily/run", salesAnalysisHandler.Run)     // ?from=&to= (default hari ini)
+		reports.GET("/course-times", salesAnalysisHandler.CourseTimes) // ?outlet_id=1&from=&to=
+		reports.GET("/x-report", cashDrawerHandler.XReport)            // ?outlet_id=1
+		reports.GET("/z-report", cashDrawerHandler.ZReport)            // ?outlet_id=1&date=2025-01-31
 	}
 
 	return r
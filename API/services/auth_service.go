@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pos-restaurant/repositories"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("staff id atau pin salah")
+	ErrInvalidToken       = errors.New("token tidak valid")
+	ErrTokenExpired       = errors.New("token sudah kedaluwarsa")
+	ErrApprovalDenied     = errors.New("persetujuan ditolak: pin salah atau role tidak berwenang")
+	ErrTooManyAttempts    = errors.New("terlalu banyak percobaan pin salah")
+	ErrSessionRevoked     = errors.New("sesi dicabut: staff nonaktif atau role berubah")
+)
+
+// Isi token sesi yang ditandatangani server
+type SessionClaims struct {
+	StaffID   int       `json:"staff_id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Batas percobaan PIN salah: per staff (PIN yang ditebak) dan per IP (penebak yang mencoba banyak staff)
+const (
+	MaxPinFailuresPerStaff = 5
+	MaxPinFailuresPerIP    = 20
+	PinFailureWindow       = 15 * time.Minute
+	PinLockout             = 15 * time.Minute
+)
+
+type AuthService struct {
+	staffRepo *repositories.StaffRepository
+	secret    []byte
+	ttl       time.Duration
+
+	staffAttempts *attemptLimiter
+	ipAttempts    *attemptLimiter
+}
+
+// Jika secret kosong, dibuat secret acak sehingga token hanya berlaku selama proses berjalan
+func NewAuthService(staffRepo *repositories.StaffRepository, secret string, ttl time.Duration) *AuthService {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+	}
+	return &AuthService{
+		staffRepo:     staffRepo,
+		secret:        key,
+		ttl:           ttl,
+		staffAttempts: newAttemptLimiter(MaxPinFailuresPerStaff, PinFailureWindow, PinLockout),
+		ipAttempts:    newAttemptLimiter(MaxPinFailuresPerIP, PinFailureWindow, PinLockout),
+	}
+}
+
+// Hash pembanding untuk staff yang tidak ada atau nonaktif, agar penolakannya memakan waktu bcrypt yang sama
+// dengan PIN salah dan ID staff yang valid tidak bisa ditebak dari waktu respons
+var dummyPinHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-pin"), bcrypt.DefaultCost)
+
+func HashPin(pin string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Login memverifikasi ID + PIN staff. Setelah terlalu banyak PIN salah untuk staff yang sama atau dari IP
+// yang sama, login dikunci sementara (ErrTooManyAttempts) walaupun PIN berikutnya benar.
+func (s *AuthService) Login(ctx context.Context, staffID int, pin, clientIP string) (string, *SessionClaims, error) {
+	staffKey, ipKey := staffAttemptKey(staffID), "ip:"+clientIP
+	now := time.Now()
+	if err := lockedErr(s.staffAttempts.locked(staffKey, now), s.ipAttempts.locked(ipKey, now)); err != nil {
+		return "", nil, err
+	}
+	fail := func() (string, *SessionClaims, error) {
+		s.staffAttempts.fail(staffKey, now)
+		s.ipAttempts.fail(ipKey, now)
+		return "", nil, ErrInvalidCredentials
+	}
+
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+			return fail()
+		}
+		return "", nil, err
+	}
+
+	if !staff.IsActive {
+		bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+		return fail()
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(staff.PinCode), []byte(pin)) != nil {
+		return fail()
+	}
+	s.staffAttempts.reset(staffKey)
+
+	claims := &SessionClaims{
+		StaffID:   staff.ID,
+		Name:      staff.Name,
+		Role:      staff.Role,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// Aksi yang membutuhkan persetujuan PIN, dicatat di approval_failures
+const (
+	ApprovalPriceOverride = "price_override"
+	ApprovalRefund        = "refund"
+)
+
+// VerifyApproval memastikan staff approver aktif, PIN-nya benar dan role-nya termasuk roles.
+// Kegagalan dihitung bersama login untuk approver (batas per staff) dan per staff peminta (requestedBy),
+// sehingga PIN manager tidak bisa ditebak lewat persetujuan, dan setiap kegagalan dicatat untuk audit.
+func (s *AuthService) VerifyApproval(ctx context.Context, approverID int, pin string, requestedBy int, action string, roles ...string) error {
+	approverKey, requesterKey := staffAttemptKey(approverID), "requester:"+strconv.Itoa(requestedBy)
+	now := time.Now()
+	if err := lockedErr(s.staffAttempts.locked(approverKey, now), s.staffAttempts.locked(requesterKey, now)); err != nil {
+		s.logFailedApproval(ctx, approverID, requestedBy, action, "locked")
+		return err
+	}
+	deny := func(reason string) error {
+		s.staffAttempts.fail(approverKey, now)
+		s.staffAttempts.fail(requesterKey, now)
+		s.logFailedApproval(ctx, approverID, requestedBy, action, reason)
+		return ErrApprovalDenied
+	}
+
+	staff, err := s.staffRepo.GetByID(ctx, approverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+			return deny("unknown_staff")
+		}
+		return err
+	}
+
+	if !staff.IsActive {
+		bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+		return deny("inactive")
+	}
+	if !slices.Contains(roles, staff.Role) {
+		bcrypt.CompareHashAndPassword(dummyPinHash, []byte(pin))
+		return deny("role")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(staff.PinCode), []byte(pin)) != nil {
+		return deny("wrong_pin")
+	}
+	s.staffAttempts.reset(approverKey)
+	return nil
+}
+
+// logFailedApproval mencatat audit tanpa menggagalkan request, penolakan tetap dikembalikan ke pemanggil
+func (s *AuthService) logFailedApproval(ctx context.Context, approverID, requestedBy int, action, reason string) {
+	if err := s.staffRepo.LogFailedApproval(ctx, approverID, requestedBy, action, reason); err != nil {
+		log.Printf("Gagal mencatat persetujuan gagal (approver %d, oleh %d, %s): %v", approverID, requestedBy, action, err)
+	}
+}
+
+func staffAttemptKey(staffID int) string {
+	return "staff:" + strconv.Itoa(staffID)
+}
+
+// lockedErr mengubah sisa waktu kunci terlama menjadi ErrTooManyAttempts, nil jika tidak ada yang terkunci
+func lockedErr(waits ...time.Duration) error {
+	longest := slices.Max(waits)
+	if longest <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w, coba lagi dalam %d menit", ErrTooManyAttempts, int(math.Ceil(longest.Minutes())))
+}
+
+// attemptLimiter menghitung percobaan gagal per kunci dalam satu jendela waktu dan mengunci kunci tersebut
+// sementara setelah max kegagalan. Disimpan di memori proses, cukup untuk satu instance server POS.
+type attemptLimiter struct {
+	mu       sync.Mutex
+	max      int
+	window   time.Duration
+	lockout  time.Duration
+	failures map[string]*attemptState
+}
+
+type attemptState struct {
+	count       int
+	since       time.Time
+	lockedUntil time.Time
+}
+
+func newAttemptLimiter(max int, window, lockout time.Duration) *attemptLimiter {
+	return &attemptLimiter{max: max, window: window, lockout: lockout, failures: map[string]*attemptState{}}
+}
+
+// locked mengembalikan sisa waktu kunci, 0 jika kunci tidak sedang dikunci
+func (l *attemptLimiter) locked(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if st, ok := l.failures[key]; ok && now.Before(st.lockedUntil) {
+		return st.lockedUntil.Sub(now)
+	}
+	return 0
+}
+
+func (l *attemptLimiter) fail(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st, ok := l.failures[key]
+	if !ok || (now.Sub(st.since) > l.window && !now.Before(st.lockedUntil)) {
+		l.prune(now)
+		st = &attemptState{since: now}
+		l.failures[key] = st
+	}
+	st.count++
+	if st.count >= l.max {
+		st.lockedUntil = now.Add(l.lockout)
+		st.count = 0
+		st.since = now
+	}
+}
+
+func (l *attemptLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, key)
+}
+
+// prune membuang kunci yang jendelanya sudah lewat dan tidak sedang dikunci supaya map tidak terus membesar
+func (l *attemptLimiter) prune(now time.Time) {
+	for key, st := range l.failures {
+		if now.Sub(st.since) > l.window && !now.Before(st.lockedUntil) {
+			delete(l.failures, key)
+		}
+	}
+}
+
+// Format token: base64url(payload) + "." + base64url(HMAC-SHA256(payload))
+func (s *AuthService) sign(claims *SessionClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Authenticate memverifikasi token lalu memastikan staff masih aktif dan role-nya sama dengan saat login,
+// sehingga staff yang dinonaktifkan/dihapus atau diubah role-nya tidak bisa memakai token lama sampai kedaluwarsa
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*SessionClaims, error) {
+	claims, err := s.ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	staff, err := s.staffRepo.GetByID(ctx, claims.StaffID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	if !staff.IsActive || staff.Role != claims.Role {
+		return nil, ErrSessionRevoked
+	}
+	return claims, nil
+}
+
+func (s *AuthService) ParseToken(token string) (*SessionClaims, error) {
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims SessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAttemptLimiter(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	type step struct {
+		at     time.Duration // sejak start
+		fail   bool
+		reset  bool
+		locked bool // hasil locked setelah langkah
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"terkunci setelah max kegagalan", []step{
+			{at: 0, fail: true},
+			{at: time.Minute, fail: true},
+			{at: 2 * time.Minute, fail: true, locked: true},
+			{at: 10 * time.Minute, locked: true},
+			{at: 17 * time.Minute},
+		}},
+		{"kegagalan di luar jendela tidak dihitung", []step{
+			{at: 0, fail: true},
+			{at: time.Minute, fail: true},
+			{at: 6 * time.Minute, fail: true},
+			{at: 7 * time.Minute, fail: true},
+			{at: 8 * time.Minute, fail: true, locked: true},
+		}},
+		{"reset setelah login berhasil", []step{
+			{at: 0, fail: true},
+			{at: time.Minute, fail: true},
+			{at: 2 * time.Minute, reset: true},
+			{at: 3 * time.Minute, fail: true},
+			{at: 4 * time.Minute, fail: true},
+		}},
+		{"hitungan mulai lagi setelah kunci habis", []step{
+			{at: 0, fail: true},
+			{at: 0, fail: true},
+			{at: 0, fail: true, locked: true},
+			{at: 16 * time.Minute, fail: true},
+			{at: 17 * time.Minute, fail: true},
+			{at: 18 * time.Minute, fail: true, locked: true},
+		}},
+	}
+	for _, tt := range tests {
+		l := newAttemptLimiter(3, 5*time.Minute, 15*time.Minute)
+		for i, st := range tt.steps {
+			now := start.Add(st.at)
+			switch {
+			case st.fail:
+				l.fail("staff:1", now)
+			case st.reset:
+				l.reset("staff:1")
+			}
+			if got := l.locked("staff:1", now) > 0; got != st.locked {
+				t.Errorf("%s: langkah %d locked = %v, want %v", tt.name, i+1, got, st.locked)
+			}
+		}
+		if l.locked("staff:2", start.Add(tt.steps[len(tt.steps)-1].at)) > 0 {
+			t.Errorf("%s: kunci lain ikut terkunci", tt.name)
+		}
+	}
+}
+
+func TestLockedErr(t *testing.T) {
+	if err := lockedErr(0, -time.Second); err != nil {
+		t.Errorf("lockedErr tanpa kunci = %v, want nil", err)
+	}
+	err := lockedErr(time.Minute, 90*time.Second)
+	if !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("lockedErr = %v, want ErrTooManyAttempts", err)
+	}
+	if want := ErrTooManyAttempts.Error() + ", coba lagi dalam 2 menit"; err.Error() != want {
+		t.Errorf("lockedErr = %q, want %q", err.Error(), want)
+	}
+}
@@ -3,34 +3,51 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"pos-restaurant/events"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/pms"
+	"pos-restaurant/pricing"
+	"pos-restaurant/receipt"
 	"pos-restaurant/repositories"
+	"strings"
+	"time"
 )
 
 type BillService struct {
-	repo *repositories.BillRepository
+	repo   *repositories.BillRepository
+	auth   *AuthService
+	tables *TableService
+	broker *events.Broker
 }
 
-func NewBillService(repo *repositories.BillRepository) *BillService {
-	return &BillService{repo: repo}
+func NewBillService(repo *repositories.BillRepository, auth *AuthService, tables *TableService, broker *events.Broker) *BillService {
+	return &BillService{repo: repo, auth: auth, tables: tables, broker: broker}
 }
 
-func (s *BillService) Create(ctx context.Context, orderID int, discount float64) (int, error) {
+func (s *BillService) Create(ctx context.Context, orderID int, discount money.Amount) (int, error) {
 	return s.repo.Create(ctx, orderID, discount)
 }
 
+func (s *BillService) Preview(ctx context.Context, orderID int, discount money.Amount) (pricing.Breakdown, error) {
+	return s.repo.Preview(ctx, orderID, discount)
+}
+
+// CreateSplit memecah tagihan order. Bill asal dikunci dan divalidasi di repository dalam transaksi split.
 func (s *BillService) CreateSplit(ctx context.Context, req models.SplitBillRequest) ([]int, error) {
-	originalBill, err := s.repo.GetByID(ctx, req.OriginalBillID)
-	if err != nil {
-		return nil, fmt.Errorf("bill tidak ditemukan: %w", err)
-	}
+	return s.repo.CreateSplit(ctx, req)
+}
 
-	// Validasi bahwa original_order_id di request sama dengan order_id di original bill
-	if originalBill.OrderID != req.OriginalOrderID {
-		return nil, fmt.Errorf("order_id tidak sesuai dengan original_bill_id yang dituju")
+// Merge menggabungkan bill open dari beberapa order menjadi satu bill baru
+func (s *BillService) Merge(ctx context.Context, billIDs []int, staffID int, reason string) (*models.MergeResult, error) {
+	result, err := s.repo.Merge(ctx, billIDs, staffID, reason)
+	if err != nil {
+		return nil, err
 	}
-
-	return s.repo.CreateSplit(ctx, req)
+	publishMerge(s.broker, result)
+	s.tables.refreshFreed(ctx, result)
+	return result, nil
 }
 
 func (s *BillService) List(ctx context.Context) ([]*models.Bill, error) {
@@ -41,10 +58,105 @@ func (s *BillService) GetByID(ctx context.Context, id int) (*models.Bill, error)
 	return s.repo.GetByID(ctx, id)
 }
 
+// SoftDelete mem-void bill yang belum dibayar, lalu menghitung ulang status meja order-nya dan mengabarkan POS
 func (s *BillService) SoftDelete(ctx context.Context, id int) error {
-	return s.repo.SoftDelete(ctx, id)
+	orderID, err := s.repo.SoftDelete(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.tables.RefreshOrder(ctx, orderID)
+
+	outletID, err := s.repo.GetOutletID(ctx, id)
+	if err != nil {
+		log.Printf("Gagal ambil outlet bill %d untuk event void: %v", id, err)
+		return nil
+	}
+	s.broker.Publish(events.BillVoided, outletID, map[string]any{"bill_id": id, "order_id": orderID})
+	return nil
+}
+
+// Pay memvalidasi nominal lalu mencatat pembayaran, hasilnya bukti pembayaran beserta kembalian
+func (s *BillService) Pay(ctx context.Context, payment *models.BillPayment, staffID int) (*models.PaymentReceipt, error) {
+	if payment.Amount <= 0 || payment.TipAmount < 0 {
+		return nil, fmt.Errorf("%w: amount harus lebih dari 0 dan tip tidak boleh negatif", repositories.ErrOverpayment)
+	}
+
+	receipt, err := s.repo.Pay(ctx, payment, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.OrderSettled {
+		s.tables.RefreshOrder(ctx, receipt.OrderID)
+	}
+
+	outletID, err := s.repo.GetOutletID(ctx, payment.BillID)
+	if err != nil {
+		log.Printf("Gagal ambil outlet bill %d untuk event pembayaran: %v", payment.BillID, err)
+		return receipt, nil
+	}
+	s.broker.Publish(events.BillPaid, outletID, map[string]any{
+		"bill_id": receipt.BillID, "order_id": receipt.OrderID, "payment_method": receipt.PaymentMethod,
+		"amount": receipt.Amount, "tip_amount": receipt.TipAmount, "status": receipt.BillStatus,
+		"paid_amount": receipt.PaidAmount, "balance_due": receipt.BalanceDue,
+	})
+	return receipt, nil
+}
+
+// Refund mengembalikan pembayaran setelah disetujui manager dengan PIN
+func (s *BillService) Refund(ctx context.Context, refund *models.BillRefund, approverPin string, staffID int) error {
+	if refund.Amount < 0 || strings.TrimSpace(refund.Reason) == "" {
+		return fmt.Errorf("%w: nominal tidak boleh negatif dan alasan wajib diisi", repositories.ErrInvalidRefund)
+	}
+	if err := s.auth.VerifyApproval(ctx, refund.ApprovedBy, approverPin, staffID, ApprovalRefund, models.RoleManager); err != nil {
+		return err
+	}
+
+	if err := s.repo.Refund(ctx, refund, staffID); err != nil {
+		return err
+	}
+
+	outletID, err := s.repo.GetOutletID(ctx, refund.BillID)
+	if err != nil {
+		log.Printf("Gagal ambil outlet bill %d untuk event refund: %v", refund.BillID, err)
+		return nil
+	}
+	s.broker.Publish(events.BillRefunded, outletID, map[string]any{
+		"bill_id": refund.BillID, "payment_id": refund.PaymentID, "refund_id": refund.ID,
+		"refund_method": refund.RefundMethod, "amount": refund.Amount, "status": refund.BillStatus,
+	})
+	return nil
+}
+
+func (s *BillService) LookupRoomGuest(ctx context.Context, room string) (*pms.Guest, error) {
+	return s.repo.LookupRoomGuest(ctx, room)
 }
 
-func (s *BillService) Pay(ctx context.Context, payment *models.BillPayment) error {
-	return s.repo.Pay(ctx, payment)
+func (s *BillService) ListRefunds(ctx context.Context, billID int) ([]*models.BillRefund, error) {
+	return s.repo.ListRefunds(ctx, billID)
+}
+
+// Receipt merender struk bill. Setiap cetak dicatat; cetakan kedua dan seterusnya ditandai cetak ulang.
+// Preview tidak dicatat, selalu ditandai PREVIEW / BUKAN STRUK dan menampilkan nomor salinan yang akan didapat cetakan berikutnya.
+func (s *BillService) Receipt(ctx context.Context, billID int, format string, width, staffID int, preview bool) ([]byte, string, error) {
+	if format != receipt.FormatESCPOS && format != receipt.FormatHTML && format != receipt.FormatPDF {
+		return nil, "", fmt.Errorf("%w: %s", receipt.ErrUnknownFormat, format)
+	}
+
+	rc, err := s.repo.LoadReceipt(ctx, billID, staffID)
+	if err != nil {
+		return nil, "", err
+	}
+	rc.PrintedAt = time.Now()
+	rc.Preview = preview
+
+	if preview {
+		rc.Copy, err = s.repo.CountPrints(ctx, billID)
+	} else {
+		rc.Copy, err = s.repo.RecordPrint(ctx, billID, format, staffID)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return receipt.Render(rc, format, width)
 }
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"pos-restaurant/events"
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+	"pos-restaurant/repositories"
+	"time"
+)
+
+var ErrDrawerNotOwner = errors.New("sesi laci kas milik kasir lain, hanya manager/supervisor yang boleh menutup")
+
+type CashDrawerService struct {
+	repo   *repositories.CashDrawerRepository
+	broker *events.Broker
+}
+
+func NewCashDrawerService(repo *repositories.CashDrawerRepository, broker *events.Broker) *CashDrawerService {
+	return &CashDrawerService{repo: repo, broker: broker}
+}
+
+// Open membuka laci kas kasir di outlet dengan modal awal
+func (s *CashDrawerService) Open(ctx context.Context, outletID, cashierID int, openingFloat money.Amount) (*models.CashDrawerSession, error) {
+	id, err := s.repo.Open(ctx, outletID, cashierID, openingFloat)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.broker.Publish(events.DrawerOpened, outletID, map[string]any{
+		"session_id": id, "cashier_id": cashierID, "opening_float": openingFloat,
+	})
+	return session, nil
+}
+
+// Current mengembalikan sesi kasir yang terbuka tanpa angka penjualan (hitung buta)
+func (s *CashDrawerService) Current(ctx context.Context, cashierID int) (*models.CashDrawerSession, error) {
+	return s.repo.Current(ctx, cashierID)
+}
+
+func (s *CashDrawerService) List(ctx context.Context, outletID int, status string) ([]*models.CashDrawerSession, error) {
+	return s.repo.List(ctx, outletID, status, time.Time{}, time.Time{})
+}
+
+// Close menutup sesi dengan hasil hitung buta dan mengembalikan laporan Z-nya.
+// Kasir hanya boleh menutup sesinya sendiri, manager/supervisor boleh menutup sesi siapa pun.
+func (s *CashDrawerService) Close(ctx context.Context, sessionID int, counts []models.DrawerCount, staffID int, role, notes string) (*models.DrawerReport, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.CashierID != staffID && role != models.RoleManager && role != models.RoleSupervisor {
+		return nil, ErrDrawerNotOwner
+	}
+
+	if err := s.repo.Close(ctx, sessionID, counts, staffID, notes); err != nil {
+		return nil, err
+	}
+
+	report, err := s.Report(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	s.broker.Publish(events.DrawerClosed, session.OutletID, map[string]any{
+		"session_id": sessionID, "cashier_id": session.CashierID, "closed_by": staffID,
+		"total_sales": report.TotalSales, "over_short": report.OverShort,
+	})
+	return report, nil
+}
+
+// Report menyusun laporan X untuk sesi yang masih terbuka atau laporan Z untuk sesi yang sudah ditutup
+func (s *CashDrawerService) Report(ctx context.Context, sessionID int) (*models.DrawerReport, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionReport(ctx, session)
+}
+
+func (s *CashDrawerService) sessionReport(ctx context.Context, session *models.CashDrawerSession) (*models.DrawerReport, error) {
+	methods, err := s.repo.MethodTotals(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DrawerReport{Type: models.DrawerReportX, Session: session, GeneratedAt: time.Now()}
+	if session.Status == models.DrawerClosed {
+		report.Type = models.DrawerReportZ
+		counts, err := s.repo.Counts(ctx, session.ID)
+		if err != nil {
+			return nil, err
+		}
+		methods = withCounts(methods, counts)
+	}
+	report.Methods = methods
+	report.TotalSales, report.TotalTips, report.TotalRefunds, report.OverShort = sumMethods(methods)
+	return report, nil
+}
+
+// OutletX menggabungkan laporan X semua sesi yang masih terbuka di outlet
+func (s *CashDrawerService) OutletX(ctx context.Context, outletID int) (*models.OutletDrawerReport, error) {
+	sessions, err := s.repo.List(ctx, outletID, models.DrawerOpen, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return s.outletReport(ctx, models.DrawerReportX, outletID, "", sessions)
+}
+
+// OutletZ menggabungkan laporan Z semua sesi outlet yang ditutup pada tanggal tersebut
+func (s *CashDrawerService) OutletZ(ctx context.Context, outletID int, date time.Time) (*models.OutletDrawerReport, error) {
+	sessions, err := s.repo.List(ctx, outletID, models.DrawerClosed, date, date.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	return s.outletReport(ctx, models.DrawerReportZ, outletID, date.Format("2006-01-02"), sessions)
+}
+
+func (s *CashDrawerService) outletReport(ctx context.Context, kind string, outletID int, date string, sessions []*models.CashDrawerSession) (*models.OutletDrawerReport, error) {
+	report := &models.OutletDrawerReport{
+		Type:        kind,
+		OutletID:    outletID,
+		Date:        date,
+		Sessions:    []*models.DrawerReport{},
+		Methods:     []models.DrawerMethodTotal{},
+		GeneratedAt: time.Now(),
+	}
+
+	index := map[string]int{}
+	for _, session := range sessions {
+		sr, err := s.sessionReport(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		report.Sessions = append(report.Sessions, sr)
+
+		for _, m := range sr.Methods {
+			i, ok := index[m.PaymentMethod]
+			if !ok {
+				i = len(report.Methods)
+				index[m.PaymentMethod] = i
+				report.Methods = append(report.Methods, models.DrawerMethodTotal{PaymentMethod: m.PaymentMethod})
+			}
+			total := &report.Methods[i]
+			total.Payments += m.Payments
+			total.Sales += m.Sales
+			total.Tips += m.Tips
+			total.Refunds += m.Refunds
+			total.Expected += m.Expected
+			if m.Counted != nil {
+				total.Counted = addAmount(total.Counted, *m.Counted)
+				total.OverShort = addAmount(total.OverShort, *m.OverShort)
+			}
+		}
+	}
+	report.TotalSales, report.TotalTips, report.TotalRefunds, report.OverShort = sumMethods(report.Methods)
+	return report, nil
+}
+
+// withCounts mengisi hasil hitung dan selisih untuk metode yang dihitung saat tutup sesi.
+// Metode yang dihitung tetapi tidak punya transaksi (salah input kasir) tetap ditampilkan.
+func withCounts(methods []models.DrawerMethodTotal, counts map[string]money.Amount) []models.DrawerMethodTotal {
+	seen := map[string]bool{}
+	for i := range methods {
+		m := &methods[i]
+		seen[m.PaymentMethod] = true
+		if counted, ok := counts[m.PaymentMethod]; ok {
+			diff := counted - m.Expected
+			m.Counted, m.OverShort = &counted, &diff
+		}
+	}
+	for method, counted := range counts {
+		if !seen[method] {
+			diff := counted
+			methods = append(methods, models.DrawerMethodTotal{PaymentMethod: method, Counted: &counted, OverShort: &diff})
+		}
+	}
+	return methods
+}
+
+func sumMethods(methods []models.DrawerMethodTotal) (sales, tips, refunds, overShort money.Amount) {
+	for _, m := range methods {
+		sales += m.Sales
+		tips += m.Tips
+		refunds += m.Refunds
+		if m.OverShort != nil {
+			overShort += *m.OverShort
+		}
+	}
+	return sales, tips, refunds, overShort
+}
+
+func addAmount(total *money.Amount, a money.Amount) *money.Amount {
+	if total == nil {
+		return &a
+	}
+	sum := *total + a
+	return &sum
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+)
+
+func TestWithCountsAndSumMethods(t *testing.T) {
+	methods := []models.DrawerMethodTotal{
+		{PaymentMethod: models.PaymentCash, Sales: money.New(350000, 0), Tips: money.New(20000, 0),
+			Refunds: money.New(50000, 0), Expected: money.New(820000, 0)},
+		{PaymentMethod: models.PaymentCreditCard, Sales: money.New(400000, 0), Tips: money.New(10000, 0),
+			Expected: money.New(410000, 0)},
+		{PaymentMethod: models.PaymentRoomCharge, Sales: money.New(150000, 0), Expected: money.New(150000, 0)},
+	}
+	counts := map[string]money.Amount{
+		models.PaymentCash:       money.New(815000, 0), // kurang 5.000
+		models.PaymentCreditCard: money.New(410000, 0), // pas
+		models.PaymentVoucher:    money.New(25000, 0),  // dihitung tanpa transaksi
+	}
+
+	got := withCounts(methods, counts)
+	want := map[string]*money.Amount{
+		models.PaymentCash:       ptr(-money.New(5000, 0)),
+		models.PaymentCreditCard: ptr(0),
+		models.PaymentRoomCharge: nil,
+		models.PaymentVoucher:    ptr(money.New(25000, 0)),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("withCounts mengembalikan %d metode, want %d", len(got), len(want))
+	}
+	for _, m := range got {
+		w, ok := want[m.PaymentMethod]
+		if !ok {
+			t.Errorf("metode %s tidak diharapkan", m.PaymentMethod)
+			continue
+		}
+		switch {
+		case w == nil && m.OverShort != nil:
+			t.Errorf("%s: over/short = %s, want kosong (tidak dihitung)", m.PaymentMethod, *m.OverShort)
+		case w != nil && (m.OverShort == nil || *m.OverShort != *w):
+			t.Errorf("%s: over/short = %v, want %s", m.PaymentMethod, m.OverShort, *w)
+		case w != nil && *m.Counted != counts[m.PaymentMethod]:
+			t.Errorf("%s: counted = %s, want %s", m.PaymentMethod, *m.Counted, counts[m.PaymentMethod])
+		}
+	}
+
+	sales, tips, refunds, overShort := sumMethods(got)
+	if sales != money.New(900000, 0) || tips != money.New(30000, 0) || refunds != money.New(50000, 0) || overShort != money.New(20000, 0) {
+		t.Errorf("sumMethods = %s, %s, %s, %s", sales, tips, refunds, overShort)
+	}
+}
+
+func TestAddAmount(t *testing.T) {
+	total := addAmount(nil, money.New(10, 0))
+	total = addAmount(total, -money.New(2, 50))
+	if *total != money.New(7, 50) {
+		t.Errorf("addAmount = %s, want 7.50", *total)
+	}
+}
+
+func ptr(a money.Amount) *money.Amount {
+	return &a
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+)
+
+var ErrInvalidCombo = errors.New("paket wajib punya harga >= 0 (termasuk setelah upcharge negatif) dan minimal satu slot berisi pilihan menu, maksimal satu default per slot")
+
+type ComboService struct {
+	repo *repositories.ComboRepository
+}
+
+func NewComboService(repo *repositories.ComboRepository) *ComboService {
+	return &ComboService{repo: repo}
+}
+
+// validCombo juga memastikan harga termurah paket (harga dasar + upcharge terkecil tiap slot) tidak negatif
+func validCombo(combo *models.Combo) bool {
+	if combo.Price < 0 || len(combo.Slots) == 0 {
+		return false
+	}
+	cheapest := combo.Price
+	for _, slot := range combo.Slots {
+		if len(slot.Options) == 0 {
+			return false
+		}
+		defaults := 0
+		minDelta := slot.Options[0].PriceDelta
+		for _, opt := range slot.Options {
+			if opt.IsDefault {
+				defaults++
+			}
+			minDelta = min(minDelta, opt.PriceDelta)
+		}
+		if defaults > 1 {
+			return false
+		}
+		cheapest += minDelta
+	}
+	return cheapest >= 0
+}
+
+func (s *ComboService) Create(ctx context.Context, combo *models.Combo) (int, error) {
+	if !validCombo(combo) {
+		return 0, ErrInvalidCombo
+	}
+	return s.repo.Create(ctx, combo)
+}
+
+func (s *ComboService) Update(ctx context.Context, combo *models.Combo) error {
+	if !validCombo(combo) {
+		return ErrInvalidCombo
+	}
+	return s.repo.Update(ctx, combo)
+}
+
+func (s *ComboService) List(ctx context.Context, activeOnly bool) ([]*models.Combo, error) {
+	return s.repo.List(ctx, activeOnly)
+}
+
+func (s *ComboService) GetByID(ctx context.Context, id int) (*models.Combo, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ComboService) Delete(ctx context.Context, id int) error {
+	return s.repo.SoftDelete(ctx, id)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"pos-restaurant/models"
+	"pos-restaurant/money"
+)
+
+func TestValidCombo(t *testing.T) {
+	opt := func(delta money.Amount, isDefault bool) models.ComboSlotOption {
+		return models.ComboSlotOption{MenuItemID: 1, PriceDelta: delta, IsDefault: isDefault}
+	}
+	tests := []struct {
+		name  string
+		combo models.Combo
+		want  bool
+	}{
+		{"tanpa slot", models.Combo{Price: money.New(50000, 0)}, false},
+		{"harga negatif", models.Combo{Price: -1, Slots: []models.ComboSlot{{Options: []models.ComboSlotOption{opt(0, true)}}}}, false},
+		{"slot kosong", models.Combo{Price: money.New(50000, 0), Slots: []models.ComboSlot{{}}}, false},
+		{"dua default", models.Combo{Price: money.New(50000, 0), Slots: []models.ComboSlot{
+			{Options: []models.ComboSlotOption{opt(0, true), opt(0, true)}},
+		}}, false},
+		{"valid dengan upcharge", models.Combo{Price: money.New(50000, 0), Slots: []models.ComboSlot{
+			{Options: []models.ComboSlotOption{opt(0, true), opt(money.New(5000, 0), false)}},
+		}}, true},
+		{"potongan masih di atas nol", models.Combo{Price: money.New(50000, 0), Slots: []models.ComboSlot{
+			{Options: []models.ComboSlotOption{opt(0, true), opt(-money.New(10000, 0), false)}},
+			{Options: []models.ComboSlotOption{opt(-money.New(40000, 0), false)}},
+		}}, true},
+		{"potongan membuat harga negatif", models.Combo{Price: money.New(50000, 0), Slots: []models.ComboSlot{
+			{Options: []models.ComboSlotOption{opt(0, true), opt(-money.New(30000, 0), false)}},
+			{Options: []models.ComboSlotOption{opt(-money.New(25000, 0), false)}},
+		}}, false},
+	}
+	for _, tt := range tests {
+		if got := validCombo(&tt.combo); got != tt.want {
+			t.Errorf("%s: validCombo = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -14,8 +14,8 @@ func NewIngredientService(repo *repositories.IngredientRepository) *IngredientSe
 	return &IngredientService{repo: repo}
 }
 
-func (s *IngredientService) CreateIngredient(ctx context.Context, ing *models.Ingredient) (int, error) {
-	return s.repo.Create(ctx, ing)
+func (s *IngredientService) CreateIngredient(ctx context.Context, ing *models.Ingredient, staffID int) (int, error) {
+	return s.repo.Create(ctx, ing, staffID)
 }
 
 func (s *IngredientService) ListIngredients(ctx context.Context) ([]*models.Ingredient, error) {
@@ -26,8 +26,8 @@ func (s *IngredientService) GetIngredientByID(ctx context.Context, id int) (*mod
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *IngredientService) UpdateIngredient(ctx context.Context, ing *models.Ingredient) error {
-	return s.repo.Update(ctx, ing)
+func (s *IngredientService) UpdateIngredient(ctx context.Context, ing *models.Ingredient, staffID int) error {
+	return s.repo.Update(ctx, ing, staffID)
 }
 
 func (s *IngredientService) DeleteIngredient(ctx context.Context, id int) error {
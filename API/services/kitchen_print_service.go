@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"log"
+	"pos-restaurant/events"
+	"pos-restaurant/printing"
+	"pos-restaurant/receipt"
+	"pos-restaurant/repositories"
+	"time"
+)
+
+// KitchenPrintQueue menghubungkan spooler dengan tabel kitchen_print_jobs.
+// Tiket dirender ke ESC/POS saat diambil sehingga cetak ulang dan item void selalu mengikuti data terbaru.
+type KitchenPrintQueue struct {
+	repo   *repositories.KitchenRepository
+	broker *events.Broker
+}
+
+func NewKitchenPrintQueue(repo *repositories.KitchenRepository, broker *events.Broker) *KitchenPrintQueue {
+	return &KitchenPrintQueue{repo: repo, broker: broker}
+}
+
+func (q *KitchenPrintQueue) Claim(ctx context.Context, limit int) ([]printing.Job, error) {
+	claimed, err := q.repo.ClaimPrintJobs(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]printing.Job, 0, len(claimed))
+	for _, c := range claimed {
+		ticket, width, err := q.repo.LoadTicketPrint(ctx, c.TicketID)
+		if err != nil {
+			log.Printf("Gagal memuat tiket %d untuk job cetak %d: %v", c.TicketID, c.ID, err)
+			if err := q.Fail(ctx, c.ID, err.Error(), printing.Backoff(c.Attempts), c.Attempts >= printing.MaxAttempts); err != nil {
+				log.Printf("Gagal mencatat kegagalan job cetak %d: %v", c.ID, err)
+			}
+			continue
+		}
+
+		job := printing.Job{ID: c.ID, Printer: c.Printer, Attempt: c.Attempts}
+		if len(ticket.Items) > 0 { // semua item sudah di-void, tidak ada yang perlu dicetak
+			ticket.Reprint = c.Reprint
+			job.Payload = receipt.KitchenESCPOS(ticket, width)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (q *KitchenPrintQueue) Done(ctx context.Context, jobID int) error {
+	return q.repo.CompletePrintJob(ctx, jobID)
+}
+
+// Fail menjadwalkan ulang job; job yang menyerah dikabarkan ke POS agar tiket bisa diantar manual
+func (q *KitchenPrintQueue) Fail(ctx context.Context, jobID int, reason string, retryIn time.Duration, final bool) error {
+	outletID, err := q.repo.FailPrintJob(ctx, jobID, reason, retryIn, final)
+	if err != nil {
+		return err
+	}
+	if final {
+		q.broker.Publish(events.KitchenPrintFailed, outletID, map[string]any{"job_id": jobID, "error": reason})
+	}
+	return nil
+}
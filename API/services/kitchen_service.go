@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"pos-restaurant/events"
+	"pos-restaurant/models"
+	"pos-restaurant/printing"
+	"pos-restaurant/repositories"
+)
+
+type KitchenService struct {
+	repo     *repositories.KitchenRepository
+	broker   *events.Broker
+	printers map[string]printing.Printer
+}
+
+func NewKitchenService(repo *repositories.KitchenRepository, broker *events.Broker, printers map[string]printing.Printer) *KitchenService {
+	return &KitchenService{repo: repo, broker: broker, printers: printers}
+}
+
+func (s *KitchenService) CreateStation(ctx context.Context, station *models.KitchenStation) (int, error) {
+	if err := s.checkPrinter(station.Printer); err != nil {
+		return 0, err
+	}
+	return s.repo.CreateStation(ctx, station)
+}
+
+func (s *KitchenService) SetStationPrinter(ctx context.Context, stationID int, printer string, width int) error {
+	if err := s.checkPrinter(printer); err != nil {
+		return err
+	}
+	return s.repo.SetStationPrinter(ctx, stationID, printer, width)
+}
+
+// checkPrinter memastikan nama printer terdaftar di POS_PRINTERS, kosong berarti tanpa printer
+func (s *KitchenService) checkPrinter(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := s.printers[name]; !ok {
+		return fmt.Errorf("%w: %s tidak ada di POS_PRINTERS", repositories.ErrPrinterInvalid, name)
+	}
+	return nil
+}
+
+func (s *KitchenService) ListStations(ctx context.Context) ([]*models.KitchenStation, error) {
+	return s.repo.ListStations(ctx)
+}
+
+func (s *KitchenService) AssignCategories(ctx context.Context, stationID int, categoryIDs []int) error {
+	return s.repo.AssignCategories(ctx, stationID, categoryIDs)
+}
+
+func (s *KitchenService) Queue(ctx context.Context, stationID, outletID int) ([]*models.KitchenQueueItem, error) {
+	return s.repo.Queue(ctx, stationID, outletID)
+}
+
+func (s *KitchenService) Bump(ctx context.Context, ticketItemID int) (string, error) {
+	status, outletID, err := s.repo.Bump(ctx, ticketItemID)
+	if err != nil {
+		return "", err
+	}
+	s.broker.Publish(events.KitchenItemBumped, outletID, map[string]any{"ticket_item_id": ticketItemID, "status": status})
+	return status, nil
+}
+
+func (s *KitchenService) PrintJobs(ctx context.Context, status string, outletID int) ([]*models.KitchenPrintJob, error) {
+	return s.repo.ListPrintJobs(ctx, status, outletID)
+}
+
+func (s *KitchenService) RetryPrintJob(ctx context.Context, jobID int) error {
+	return s.repo.RetryPrintJob(ctx, jobID)
+}
+
+func (s *KitchenService) ReprintTicket(ctx context.Context, ticketID int) error {
+	return s.repo.ReprintTicket(ctx, ticketID)
+}
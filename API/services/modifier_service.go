@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+)
+
+var ErrInvalidModifierRule = errors.New("aturan pilihan tidak valid: 0 <= min_select <= max_select dan max_select > 0")
+
+type ModifierService struct {
+	repo *repositories.ModifierRepository
+}
+
+func NewModifierService(repo *repositories.ModifierRepository) *ModifierService {
+	return &ModifierService{repo: repo}
+}
+
+func validModifierRule(g *models.ModifierGroup) bool {
+	return g.MinSelect >= 0 && g.MaxSelect > 0 && g.MaxSelect >= g.MinSelect
+}
+
+func (s *ModifierService) CreateGroup(ctx context.Context, g *models.ModifierGroup) (int, error) {
+	if !validModifierRule(g) {
+		return 0, ErrInvalidModifierRule
+	}
+	return s.repo.CreateGroup(ctx, g)
+}
+
+func (s *ModifierService) UpdateGroup(ctx context.Context, g *models.ModifierGroup) error {
+	if !validModifierRule(g) {
+		return ErrInvalidModifierRule
+	}
+	return s.repo.UpdateGroup(ctx, g)
+}
+
+func (s *ModifierService) DeleteGroup(ctx context.Context, id int) error {
+	return s.repo.SoftDeleteGroup(ctx, id)
+}
+
+func (s *ModifierService) CreateOption(ctx context.Context, o *models.ModifierOption) (int, error) {
+	return s.repo.CreateOption(ctx, o)
+}
+
+func (s *ModifierService) UpdateOption(ctx context.Context, o *models.ModifierOption) error {
+	return s.repo.UpdateOption(ctx, o)
+}
+
+func (s *ModifierService) DeleteOption(ctx context.Context, id int) error {
+	return s.repo.SoftDeleteOption(ctx, id)
+}
+
+func (s *ModifierService) ListByMenuItem(ctx context.Context, menuItemID int) ([]models.ModifierGroup, error) {
+	return s.repo.ListByMenuItem(ctx, menuItemID)
+}
@@ -2,23 +2,89 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"pos-restaurant/events"
 	"pos-restaurant/models"
 	"pos-restaurant/repositories"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidOrderTransition = errors.New("perubahan status order tidak diizinkan")
+	ErrInvalidPriceOverride   = errors.New("override harga wajib berisi harga >= 0, alasan dan approver")
+	ErrInvalidQty             = errors.New("qty harus 0.01 - 9999.99 dengan maksimal 2 desimal")
+)
+
 type OrderService struct {
-	repo *repositories.OrderRepository
+	repo   *repositories.OrderRepository
+	auth   *AuthService
+	tables *TableService
+	broker *events.Broker
 }
 
-func NewOrderService(repo *repositories.OrderRepository) *OrderService {
-	return &OrderService{repo: repo}
+func NewOrderService(repo *repositories.OrderRepository, auth *AuthService, tables *TableService, broker *events.Broker) *OrderService {
+	return &OrderService{repo: repo, auth: auth, tables: tables, broker: broker}
 }
 
-func (s *OrderService) Create(ctx context.Context, req *models.OrderRequest) (int, error) {
+// Harga item diambil dari menu_items oleh repository, override harga butuh persetujuan manager/supervisor
+func (s *OrderService) Create(ctx context.Context, req *models.OrderRequest, staffID int) (int, error) {
+	for _, item := range req.Items {
+		if !validQty(item.Qty) {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidQty, item.Qty)
+		}
+		if err := s.verifyPriceOverride(ctx, item.PriceOverride, staffID); err != nil {
+			return 0, err
+		}
+	}
+
 	req.OrderNumber = uuid.NewString()
-	return s.repo.Create(ctx, req)
+	req.Status = models.OrderOpen // Order baru selalu open
+	id, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	s.broker.Publish(events.OrderCreated, req.OutletID, map[string]any{
+		"order_id": id, "order_number": req.OrderNumber, "table_id": req.TableID,
+	})
+	s.tables.Refresh(ctx, req.TableID)
+	return id, nil
+}
+
+// publish mengirim event order ke outlet-nya, gagal ambil outlet tidak menggagalkan request
+func (s *OrderService) publish(ctx context.Context, eventType string, orderID int, data map[string]any) {
+	outletID, err := s.repo.GetOutletID(ctx, orderID)
+	if err != nil {
+		log.Printf("Gagal ambil outlet order %d untuk event %s: %v", orderID, eventType, err)
+		return
+	}
+	data["order_id"] = orderID
+	s.broker.Publish(eventType, outletID, data)
+}
+
+func (s *OrderService) verifyPriceOverride(ctx context.Context, o *models.PriceOverride, staffID int) error {
+	if o == nil {
+		return nil
+	}
+	if o.Price < 0 || strings.TrimSpace(o.Reason) == "" || o.ApprovedBy == 0 {
+		return ErrInvalidPriceOverride
+	}
+	return s.auth.VerifyApproval(ctx, o.ApprovedBy, o.ApproverPin, staffID, ApprovalPriceOverride,
+		models.RoleManager, models.RoleSupervisor)
+}
+
+// validQty memastikan qty muat di kolom DECIMAL(6,2) tanpa dibulatkan diam-diam oleh database
+func validQty(q float64) bool {
+	cents := math.Round(q * 100)
+	return cents >= 1 && cents <= 999999 && math.Abs(q*100-cents) < 1e-6
+}
+
+func (s *OrderService) publishStatus(ctx context.Context, id int, fromStatus, toStatus string) {
+	s.publish(ctx, events.OrderStatusChanged, id, map[string]any{"from": fromStatus, "to": toStatus})
 }
 
 func (s *OrderService) List(ctx context.Context) ([]*models.OrderRequest, error) {
@@ -29,14 +95,175 @@ func (s *OrderService) GetByID(ctx context.Context, id int) (*models.OrderReques
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *OrderService) Update(ctx context.Context, order *models.Order) error {
-	return s.repo.Update(ctx, order)
+// Update mengubah data order yang masih open. Perubahan status ikut disimpan dalam transaksi yang sama
+// setelah divalidasi seperti ChangeStatus, void tetap lewat Void.
+func (s *OrderService) Update(ctx context.Context, order *models.Order, staffID int) error {
+	fromStatus, err := s.repo.GetStatus(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	toStatus := order.Status
+	if toStatus == fromStatus {
+		toStatus = ""
+	}
+	if toStatus == models.OrderVoid {
+		return fmt.Errorf("%w: gunakan void order", ErrInvalidOrderTransition)
+	}
+	if toStatus != "" {
+		if err := s.checkTransition(fromStatus, toStatus); err != nil {
+			return err
+		}
+	}
+
+	prevTables, err := s.tables.orderTables(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, order, fromStatus, toStatus, staffID); err != nil {
+		return err
+	}
+	if toStatus != "" {
+		s.publishStatus(ctx, order.ID, fromStatus, toStatus)
+	}
+	s.tables.Refresh(ctx, append(prevTables, order.TableID)...)
+	return nil
+}
+
+// ChangeStatus menjalankan state machine order: open/transferred -> settled/void.
+// Status transferred hanya lewat transfer meja (TableTransferService) yang juga memindahkan meja order,
+// status merged hanya lewat Merge karena item order harus ikut dipindah.
+func (s *OrderService) ChangeStatus(ctx context.Context, id int, toStatus string, staffID int, reason string) error {
+	fromStatus, err := s.repo.GetStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if fromStatus == toStatus {
+		return nil
+	}
+	if err := s.checkTransition(fromStatus, toStatus); err != nil {
+		return err
+	}
+
+	if toStatus == models.OrderVoid {
+		if err := s.repo.Void(ctx, id, fromStatus, staffID, false, reason); err != nil {
+			return err
+		}
+		s.publishStatus(ctx, id, fromStatus, toStatus)
+		s.tables.RefreshOrder(ctx, id)
+		return nil
+	}
+
+	if err := s.repo.ChangeStatus(ctx, id, fromStatus, toStatus, staffID, reason); err != nil {
+		return err
+	}
+	s.publishStatus(ctx, id, fromStatus, toStatus)
+	s.tables.RefreshOrder(ctx, id)
+	return nil
+}
+
+// checkTransition memastikan perpindahan status diizinkan: transferred hanya lewat transfer meja,
+// merged hanya lewat Merge. Syarat settled (semua bill lunas) dicek repository di bawah kunci order.
+func (s *OrderService) checkTransition(fromStatus, toStatus string) error {
+	if !models.CanTransitionOrder(fromStatus, toStatus) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, fromStatus, toStatus)
+	}
+
+	switch toStatus {
+	case models.OrderTransferred:
+		return fmt.Errorf("%w: gunakan transfer meja untuk memindahkan order", ErrInvalidOrderTransition)
+	case models.OrderMerged:
+		return fmt.Errorf("%w: gunakan merge order untuk memindahkan item", ErrInvalidOrderTransition)
+	}
+	return nil
+}
+
+// Merge menggabungkan order sumber ke order tujuan, misalnya saat dua rombongan tamu bergabung
+func (s *OrderService) Merge(ctx context.Context, targetID int, sourceIDs []int, staffID int, reason string) (*models.MergeResult, error) {
+	result, err := s.repo.Merge(ctx, targetID, sourceIDs, staffID, reason)
+	if err != nil {
+		return nil, err
+	}
+	publishMerge(s.broker, result)
+	s.tables.refreshFreed(ctx, result)
+	return result, nil
+}
+
+func (s *OrderService) Merges(ctx context.Context, id int) ([]*models.OrderMerge, error) {
+	return s.repo.ListMerges(ctx, id)
+}
+
+// publishMerge mengirim event merge, perubahan status order sumber dan meja yang kembali kosong
+func publishMerge(broker *events.Broker, result *models.MergeResult) {
+	broker.Publish(events.OrdersMerged, result.OutletID, map[string]any{
+		"order_id": result.TargetOrderID, "merged_order_ids": result.MergedOrderIDs, "bill_id": result.BillID,
+	})
+	for _, id := range result.MergedOrderIDs {
+		broker.Publish(events.OrderStatusChanged, result.OutletID, map[string]any{
+			"order_id": id, "from": models.OrderOpen, "to": models.OrderMerged,
+		})
+	}
+	for _, t := range result.FreedTables {
+		broker.Publish(events.TableStatusChanged, result.OutletID, map[string]any{
+			"table_id": t.TableID, "table_number": t.TableNumber, "from": "occupied", "to": "available",
+		})
+	}
+}
+
+func (s *OrderService) StatusHistory(ctx context.Context, id int) ([]*models.OrderStatusChange, error) {
+	return s.repo.ListStatusHistory(ctx, id)
+}
+
+func (s *OrderService) Courses(ctx context.Context, id int) ([]*models.OrderCourse, error) {
+	return s.repo.ListCourses(ctx, id)
+}
+
+func (s *OrderService) AddItem(ctx context.Context, orderID int, item *models.AddOrderItemRequest, staffID int) error {
+	if !validQty(item.Qty) {
+		return fmt.Errorf("%w: %v", ErrInvalidQty, item.Qty)
+	}
+	if err := s.verifyPriceOverride(ctx, item.PriceOverride, staffID); err != nil {
+		return err
+	}
+	if err := s.repo.AddItem(ctx, orderID, item); err != nil {
+		return err
+	}
+	s.publish(ctx, events.OrderItemAdded, orderID, map[string]any{
+		"menu_item_id": item.MenuItemID, "combo_id": item.ComboID, "qty": item.Qty,
+	})
+	return nil
+}
+
+// Fire melepas item yang ditahan ke dapur: satu course, tiket tertentu, atau semua tiket order yang ditahan
+func (s *OrderService) Fire(ctx context.Context, orderID int, course string, ticketIDs []int, staffID int) ([]int, error) {
+	fired, err := s.repo.Fire(ctx, orderID, course, ticketIDs, staffID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events.KitchenFired, orderID, map[string]any{"course": course, "ticket_ids": fired})
+	return fired, nil
 }
 
-func (s *OrderService) AddItem(ctx context.Context, orderID int, item *models.AddOrderItemRequest) error {
-	return s.repo.AddItem(ctx, orderID, item)
+// Void membatalkan order, prepared = true berarti makanan sudah dibuat sehingga dihitung sebagai waste
+func (s *OrderService) Void(ctx context.Context, id, staffID int, prepared bool, reason string) error {
+	fromStatus, err := s.repo.GetStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !models.CanTransitionOrder(fromStatus, models.OrderVoid) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, fromStatus, models.OrderVoid)
+	}
+	if err := s.repo.Void(ctx, id, fromStatus, staffID, prepared, reason); err != nil {
+		return err
+	}
+	s.publishStatus(ctx, id, fromStatus, models.OrderVoid)
+	s.tables.RefreshOrder(ctx, id)
+	return nil
 }
 
-func (s *OrderService) SoftDelete(ctx context.Context, id int) error {
-	return s.repo.SoftDelete(ctx, id)
+func (s *OrderService) VoidItem(ctx context.Context, orderID, itemID, staffID int, prepared bool, reason string) error {
+	if err := s.repo.VoidItem(ctx, orderID, itemID, staffID, prepared, reason); err != nil {
+		return err
+	}
+	s.publish(ctx, events.OrderItemVoided, orderID, map[string]any{"order_item_id": itemID, "prepared": prepared})
+	return nil
 }
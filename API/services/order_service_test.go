@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"pos-restaurant/models"
+)
+
+func TestCheckTransitionRejectsSideChannels(t *testing.T) {
+	s := &OrderService{}
+	tests := []struct {
+		from, to string
+	}{
+		{models.OrderOpen, models.OrderTransferred},
+		{models.OrderOpen, models.OrderMerged},
+		{models.OrderTransferred, models.OrderMerged},
+		{models.OrderTransferred, models.OrderOpen},
+		{models.OrderSettled, models.OrderVoid},
+		{models.OrderVoid, models.OrderOpen},
+	}
+	for _, tt := range tests {
+		if err := s.checkTransition(tt.from, tt.to); !errors.Is(err, ErrInvalidOrderTransition) {
+			t.Errorf("checkTransition(%s -> %s) = %v, want ErrInvalidOrderTransition", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestValidQty(t *testing.T) {
+	tests := []struct {
+		qty  float64
+		want bool
+	}{
+		{1, true},
+		{0.5, true},
+		{0.01, true},
+		{0.1 + 0.2, true}, // 0.30000000000000004 tetap dianggap 0.30
+		{9999.99, true},
+		{0, false},
+		{0.004, false},
+		{-1, false},
+		{1.005, false},
+		{0.333, false},
+		{10000, false},
+	}
+	for _, tt := range tests {
+		if got := validQty(tt.qty); got != tt.want {
+			t.Errorf("validQty(%v) = %v, want %v", tt.qty, got, tt.want)
+		}
+	}
+}
@@ -2,8 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"pos-restaurant/models"
+	"pos-restaurant/money"
 	"pos-restaurant/repositories"
+	"time"
+)
+
+var (
+	ErrInvalidTaxRule      = errors.New("aturan pajak tidak valid")
+	ErrUnsupportedCurrency = errors.New("mata uang tidak didukung")
+	ErrInvalidDiningPeriod = errors.New("periode makan tidak valid")
 )
 
 type OutletService struct {
@@ -15,6 +25,9 @@ func NewOutletService(repo *repositories.OutletRepository) *OutletService {
 }
 
 func (s *OutletService) CreateOutlet(ctx context.Context, outlet *models.Outlet) (int, error) {
+	if _, ok := money.LookupCurrency(outlet.Currency); !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, outlet.Currency)
+	}
 	return s.repo.Create(ctx, outlet)
 }
 
@@ -27,9 +40,52 @@ func (s *OutletService) GetOutletByID(ctx context.Context, id int) (*models.Outl
 }
 
 func (s *OutletService) UpdateOutlet(ctx context.Context, outlet *models.Outlet) error {
+	if _, ok := money.LookupCurrency(outlet.Currency); !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurrency, outlet.Currency)
+	}
 	return s.repo.Update(ctx, outlet)
 }
 
 func (s *OutletService) SoftDeleteOutlet(ctx context.Context, id int) error {
 	return s.repo.SoftDelete(ctx, id)
 }
+
+func (s *OutletService) ListCategoryTaxRates(ctx context.Context, outletID int) ([]models.CategoryTaxRate, error) {
+	return s.repo.ListCategoryTaxRates(ctx, outletID)
+}
+
+func (s *OutletService) ReplaceCategoryTaxRates(ctx context.Context, outletID int, rates []models.CategoryTaxRate) error {
+	seen := map[int]bool{}
+	for _, rate := range rates {
+		if seen[rate.CategoryID] {
+			return fmt.Errorf("%w: kategori %d disebut lebih dari sekali", ErrInvalidTaxRule, rate.CategoryID)
+		}
+		seen[rate.CategoryID] = true
+	}
+	return s.repo.ReplaceCategoryTaxRates(ctx, outletID, rates)
+}
+
+func (s *OutletService) ListDiningPeriods(ctx context.Context, outletID int) ([]models.DiningPeriod, error) {
+	return s.repo.ListDiningPeriods(ctx, outletID)
+}
+
+// ReplaceDiningPeriods memvalidasi jam HH:MM. Periode boleh tumpang tindih (misalnya event di jam dinner),
+// reservasi tanpa visit_type memakai periode pertama yang mencakup jamnya.
+func (s *OutletService) ReplaceDiningPeriods(ctx context.Context, outletID int, periods []models.DiningPeriod) error {
+	seen := map[string]bool{}
+	for _, p := range periods {
+		first, err1 := time.Parse("15:04", p.FirstSeating)
+		last, err2 := time.Parse("15:04", p.LastSeating)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("%w: jam %s harus berformat HH:MM", ErrInvalidDiningPeriod, p.VisitType)
+		}
+		if last.Before(first) {
+			return fmt.Errorf("%w: last_seating %s sebelum first_seating", ErrInvalidDiningPeriod, p.VisitType)
+		}
+		if seen[p.VisitType] {
+			return fmt.Errorf("%w: %s disebut lebih dari sekali", ErrInvalidDiningPeriod, p.VisitType)
+		}
+		seen[p.VisitType] = true
+	}
+	return s.repo.ReplaceDiningPeriods(ctx, outletID, periods)
+}
@@ -2,20 +2,54 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"pos-restaurant/events"
 	"pos-restaurant/models"
 	"pos-restaurant/repositories"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoTableAvailable = errors.New("tidak ada meja kosong untuk jumlah tamu tersebut")
+	ErrNoDiningPeriods  = errors.New("jam reservasi outlet belum diatur")
+)
+
+const (
+	// ReservationSlotInterval adalah jarak antar slot di pencarian ketersediaan
+	ReservationSlotInterval = 15 * time.Minute
+	// DefaultDiningDuration dipakai jika jam reservasi tidak masuk periode makan outlet mana pun
+	DefaultDiningDuration = 90 * time.Minute
+	// maxCombinedTables membatasi jumlah meja bersebelahan yang digabung untuk satu rombongan
+	maxCombinedTables = 3
 )
 
 type ReservationService struct {
-	repo *repositories.ReservationRepository
+	repo   *repositories.ReservationRepository
+	tables *TableService
+	broker *events.Broker
 }
 
-func NewReservationService(repo *repositories.ReservationRepository) *ReservationService {
-	return &ReservationService{repo: repo}
+func NewReservationService(repo *repositories.ReservationRepository, tables *TableService, broker *events.Broker) *ReservationService {
+	return &ReservationService{repo: repo, tables: tables, broker: broker}
 }
 
+// Create menyimpan reservasi. Tanpa meja, meja atau gabungan meja bersebelahan dipilih otomatis.
 func (s *ReservationService) Create(ctx context.Context, res *models.Reservation) (int, error) {
-	return s.repo.Create(ctx, res)
+	if err := s.assign(ctx, res); err != nil {
+		return 0, err
+	}
+	id, err := s.repo.Create(ctx, res)
+	if err != nil {
+		return 0, err
+	}
+	s.tables.Refresh(ctx, res.TableIDs...)
+	return id, nil
 }
 
 func (s *ReservationService) List(ctx context.Context, sortBy string) ([]*models.ReservationWithDetails, error) {
@@ -26,10 +60,413 @@ func (s *ReservationService) GetByID(ctx context.Context, id int) (*models.Reser
 	return s.repo.GetByID(ctx, id)
 }
 
+// Update menyimpan reservasi lalu menghitung ulang status meja lama dan meja baru
 func (s *ReservationService) Update(ctx context.Context, res *models.Reservation) error {
-	return s.repo.Update(ctx, res)
+	prev, err := s.repo.GetByID(ctx, res.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.assign(ctx, res); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, res); err != nil {
+		return err
+	}
+	s.tables.Refresh(ctx, append(prev.TableIDs, res.TableIDs...)...)
+	return nil
 }
 
 func (s *ReservationService) Delete(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	prev, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.tables.Refresh(ctx, prev.TableIDs...)
+	return nil
+}
+
+// Seat mendudukkan tamu reservasi yang datang. Tanpa meja, dipakai meja reservasi confirmed atau meja kosong
+// yang dipilih otomatis. Kunjungan pelanggan (terhubung ke reservasi) dan order dine-in langsung dibuka.
+func (s *ReservationService) Seat(ctx context.Context, id int, tableIDs []int, waiterID int) (*models.SeatResult, error) {
+	res, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.ReservationSeatable(res.Status) {
+		return nil, fmt.Errorf("%w: reservasi %d %s", repositories.ErrReservationNotSeatable, id, res.Status)
+	}
+
+	outletID := res.OutletID
+	tableIDs = uniqueTableIDs(tableIDs)
+	switch {
+	case len(tableIDs) > 0:
+		if outletID, err = s.repo.TablesOutlet(ctx, tableIDs); err != nil {
+			return nil, err
+		}
+	case models.ReservationHoldsTable(res.Status):
+		tableIDs = res.TableIDs
+	case outletID == 0:
+		return nil, fmt.Errorf("%w: isi table_id, reservasi %d belum punya meja", repositories.ErrInvalidReservationTable, id)
+	default:
+		suggestion, err := s.SuggestNow(ctx, outletID, res.Pax, time.Duration(res.DurationMinutes)*time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		tableIDs = suggestion.TableIDs
+	}
+
+	visitType := res.VisitType.String
+	if !res.VisitType.Valid {
+		if visitType, _, err = s.DiningPeriodNow(ctx, outletID); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.repo.Seat(ctx, id, &models.Seating{
+		OutletID: outletID, TableIDs: tableIDs, WaiterID: waiterID, VisitType: visitType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.tables.Refresh(ctx, append(res.TableIDs, result.TableIDs...)...)
+	s.broker.Publish(events.OrderCreated, outletID, map[string]any{
+		"order_id": result.OrderID, "order_number": result.OrderNumber, "table_id": result.TableIDs[0],
+	})
+	s.broker.Publish(events.ReservationChanged, outletID, map[string]any{
+		"reservation_id": id, "from": res.Status, "to": models.ReservationSeated,
+		"table_ids": result.TableIDs, "order_id": result.OrderID,
+	})
+	return result, nil
+}
+
+// StartLifecycle menjalankan siklus reservasi setiap interval sampai ctx selesai: reservasi confirmed yang
+// lewat batas tunggu ditandai no_show dan mejanya dilepas, dan status meja yang jendela reserved-nya
+// mulai/berakhir dihitung ulang. Interval sebaiknya jauh lebih pendek dari ReservedLeadTime.
+func (s *ReservationService) StartLifecycle(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runLifecycle(ctx, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *ReservationService) runLifecycle(ctx context.Context, window time.Duration) {
+	noShows, err := s.repo.MarkNoShows(ctx)
+	if err != nil {
+		log.Printf("Gagal menandai reservasi no show: %v", err)
+	}
+	var tableIDs []int
+	for _, res := range noShows {
+		tableIDs = append(tableIDs, res.TableIDs...)
+		s.broker.Publish(events.ReservationChanged, res.OutletID, map[string]any{
+			"reservation_id": res.ID, "from": models.ReservationConfirmed, "to": models.ReservationNoShow,
+			"table_ids": res.TableIDs,
+		})
+	}
+
+	// Jendela sedikit dilebarkan agar tidak ada meja yang terlewat di antara dua putaran
+	due, err := s.repo.DueTableIDs(ctx, window+time.Minute)
+	if err != nil {
+		log.Printf("Gagal mengambil meja reservasi yang jatuh tempo: %v", err)
+	}
+	s.tables.Refresh(ctx, uniqueTableIDs(append(tableIDs, due...))...)
+}
+
+// assign melengkapi outlet, periode makan, lama makan dan meja reservasi sebelum disimpan
+func (s *ReservationService) assign(ctx context.Context, res *models.Reservation) error {
+	res.ReservationTime = repositories.WallClock(res.ReservationTime)
+	res.TableIDs = uniqueTableIDs(append([]int{res.TableID}, res.TableIDs...))
+
+	if len(res.TableIDs) > 0 {
+		outletID, err := s.repo.TablesOutlet(ctx, res.TableIDs)
+		if err != nil {
+			return err
+		}
+		res.OutletID = outletID
+	} else if res.OutletID == 0 {
+		return fmt.Errorf("%w: isi table_id atau outlet_id untuk pilih meja otomatis", repositories.ErrInvalidReservationTable)
+	}
+
+	periods, err := s.repo.DiningPeriods(ctx, res.OutletID)
+	if err != nil {
+		return err
+	}
+	res.DurationMinutes = int(DefaultDiningDuration / time.Minute)
+	if p := diningPeriodFor(periods, res.ReservationTime, res.VisitType.String); p != nil {
+		res.VisitType = sql.NullString{String: p.VisitType, Valid: true}
+		res.DurationMinutes = p.DurationMinutes
+	}
+
+	if len(res.TableIDs) == 0 {
+		suggestion, err := s.suggest(ctx, res.OutletID, res.ReservationTime, res.End(), res.Pax, res.ID)
+		if err != nil {
+			return err
+		}
+		res.TableIDs = suggestion.TableIDs
+	}
+	res.TableID = res.TableIDs[0]
+	return nil
+}
+
+func (s *ReservationService) suggest(ctx context.Context, outletID int, start, end time.Time, pax, excludeID int) (models.TableSuggestion, error) {
+	tables, err := s.repo.ReservableTables(ctx, outletID)
+	if err != nil {
+		return models.TableSuggestion{}, err
+	}
+	bookings, err := s.repo.Bookings(ctx, outletID, start, end, excludeID)
+	if err != nil {
+		return models.TableSuggestion{}, err
+	}
+	suggestion, ok := suggestTables(freeTables(tables, bookings, start, end), pax)
+	if !ok {
+		return suggestion, fmt.Errorf("%w: %d tamu pukul %s", ErrNoTableAvailable, pax, start.Format("15:04"))
+	}
+	return suggestion, nil
+}
+
+// SuggestNow memilih meja untuk rombongan yang datang sekarang: meja berstatus available yang tidak
+// dipesan reservasi lain selama lama makan rombongan
+func (s *ReservationService) SuggestNow(ctx context.Context, outletID, pax int, duration time.Duration) (models.TableSuggestion, error) {
+	tables, err := s.repo.ReservableTables(ctx, outletID)
+	if err != nil {
+		return models.TableSuggestion{}, err
+	}
+	now, err := s.repo.Now(ctx)
+	if err != nil {
+		return models.TableSuggestion{}, err
+	}
+	bookings, err := s.repo.Bookings(ctx, outletID, now, now.Add(duration), 0)
+	if err != nil {
+		return models.TableSuggestion{}, err
+	}
+	available := tables[:0]
+	for _, t := range tables {
+		if t.Status == models.TableAvailable {
+			available = append(available, t)
+		}
+	}
+	suggestion, ok := suggestTables(freeTables(available, bookings, now, now.Add(duration)), pax)
+	if !ok {
+		return suggestion, fmt.Errorf("%w: %d tamu saat ini", ErrNoTableAvailable, pax)
+	}
+	return suggestion, nil
+}
+
+// DiningPeriodNow mengembalikan visit type dan lama makan di outlet untuk jam database saat ini. Di luar
+// periode makan visit type ditebak dari jam dan lama makan memakai DefaultDiningDuration.
+func (s *ReservationService) DiningPeriodNow(ctx context.Context, outletID int) (string, time.Duration, error) {
+	periods, err := s.repo.DiningPeriods(ctx, outletID)
+	if err != nil {
+		return "", 0, err
+	}
+	now, err := s.repo.Now(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	if p := diningPeriodFor(periods, now, ""); p != nil {
+		return p.VisitType, time.Duration(p.DurationMinutes) * time.Minute, nil
+	}
+	switch h := now.Hour(); {
+	case h < 11:
+		return "breakfast", DefaultDiningDuration, nil
+	case h < 16:
+		return "lunch", DefaultDiningDuration, nil
+	}
+	return "dinner", DefaultDiningDuration, nil
+}
+
+// Availability mencari slot reservasi yang masih punya meja (atau gabungan meja) untuk pax tamu pada tanggal
+// tersebut, per ReservationSlotInterval dari first_seating sampai last_seating setiap periode makan outlet.
+// visitType kosong = semua periode. Slot yang sudah lewat tidak ditampilkan.
+func (s *ReservationService) Availability(ctx context.Context, outletID int, date time.Time, pax int, visitType string) ([]models.AvailabilitySlot, error) {
+	periods, err := s.repo.DiningPeriods(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+	if visitType != "" {
+		filtered := periods[:0]
+		for _, p := range periods {
+			if p.VisitType == visitType {
+				filtered = append(filtered, p)
+			}
+		}
+		periods = filtered
+	}
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("%w: outlet %d %s", ErrNoDiningPeriods, outletID, visitType)
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	tables, err := s.repo.ReservableTables(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.repo.Bookings(ctx, outletID, day, day.Add(48*time.Hour), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := s.repo.Now(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := []models.AvailabilitySlot{}
+	for _, p := range periods {
+		first, _ := time.Parse("15:04", p.FirstSeating)
+		last, _ := time.Parse("15:04", p.LastSeating)
+		duration := time.Duration(p.DurationMinutes) * time.Minute
+		from := day.Add(time.Duration(first.Hour())*time.Hour + time.Duration(first.Minute())*time.Minute)
+		to := day.Add(time.Duration(last.Hour())*time.Hour + time.Duration(last.Minute())*time.Minute)
+		for t := from; !t.After(to); t = t.Add(ReservationSlotInterval) {
+			if t.Before(now) {
+				continue
+			}
+			suggestion, ok := suggestTables(freeTables(tables, bookings, t, t.Add(duration)), pax)
+			if !ok {
+				continue
+			}
+			slots = append(slots, models.AvailabilitySlot{
+				Time: t, VisitType: p.VisitType, DurationMinutes: p.DurationMinutes, Tables: suggestion,
+			})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Time.Before(slots[j].Time) })
+	return slots, nil
+}
+
+// diningPeriodFor memilih periode makan reservasi: sesuai visit_type jika diisi, jika tidak periode pertama
+// yang slot-nya mencakup jam reservasi. nil jika tidak ada yang cocok.
+func diningPeriodFor(periods []models.DiningPeriod, t time.Time, visitType string) *models.DiningPeriod {
+	clock := t.Format("15:04")
+	for i, p := range periods {
+		if visitType != "" {
+			if p.VisitType == visitType {
+				return &periods[i]
+			}
+			continue
+		}
+		if clock >= p.FirstSeating && clock <= p.LastSeating {
+			return &periods[i]
+		}
+	}
+	return nil
+}
+
+// freeTables menyaring meja yang tidak dipakai reservasi lain di [start, end)
+func freeTables(tables []models.ReservableTable, bookings []models.TableBooking, start, end time.Time) []models.ReservableTable {
+	busy := map[int]bool{}
+	for _, b := range bookings {
+		if b.Start.Before(end) && b.End.After(start) {
+			busy[b.TableID] = true
+		}
+	}
+	free := make([]models.ReservableTable, 0, len(tables))
+	for _, t := range tables {
+		if !busy[t.ID] {
+			free = append(free, t)
+		}
+	}
+	return free
+}
+
+// suggestTables memilih satu meja terkecil yang cukup. Jika tidak ada, dicari gabungan maksimal
+// maxCombinedTables meja bersebelahan dengan total kursi paling sedikit (lalu jumlah meja paling sedikit).
+// free diurutkan dari kapasitas terkecil.
+func suggestTables(free []models.ReservableTable, pax int) (models.TableSuggestion, bool) {
+	byID := make(map[int]models.ReservableTable, len(free))
+	for _, t := range free {
+		if t.Capacity >= pax {
+			return models.TableSuggestion{TableIDs: []int{t.ID}, TableNumbers: []string{t.TableNumber}, Capacity: t.Capacity}, true
+		}
+		byID[t.ID] = t
+	}
+
+	var (
+		best     []int
+		bestSeat int
+		seen     = map[string]bool{}
+	)
+	var grow func(group []int, seats int)
+	grow = func(group []int, seats int) {
+		if len(group) > 1 && seats >= pax {
+			if best == nil || seats < bestSeat || (seats == bestSeat && len(group) < len(best)) {
+				best, bestSeat = append([]int(nil), group...), seats
+			}
+			return // menambah meja lagi hanya memperbesar gabungan
+		}
+		if len(group) == maxCombinedTables {
+			return
+		}
+		for _, id := range group {
+			for _, next := range byID[id].Adjacent {
+				t, ok := byID[next]
+				if !ok || containsInt(group, next) {
+					continue
+				}
+				candidate := append(append([]int(nil), group...), next)
+				key := groupKey(candidate)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				grow(candidate, seats+t.Capacity)
+			}
+		}
+	}
+	for _, t := range free {
+		grow([]int{t.ID}, t.Capacity)
+	}
+	if best == nil {
+		return models.TableSuggestion{}, false
+	}
+
+	suggestion := models.TableSuggestion{Capacity: bestSeat}
+	for _, id := range best {
+		suggestion.TableIDs = append(suggestion.TableIDs, id)
+		suggestion.TableNumbers = append(suggestion.TableNumbers, byID[id].TableNumber)
+	}
+	return suggestion, true
+}
+
+func groupKey(ids []int) string {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, id := range sorted {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+func containsInt(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueTableIDs membuang meja kosong (0) dan duplikat dengan urutan tetap, meja pertama menjadi meja utama
+func uniqueTableIDs(ids []int) []int {
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 && !containsInt(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
 }
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pos-restaurant/models"
+)
+
+// Denah: meja 1-2-3-4 berjajar bersebelahan, meja 5 berdiri sendiri. Diurutkan dari kapasitas terkecil.
+var floorTables = []models.ReservableTable{
+	{ID: 1, TableNumber: "A1", Capacity: 2, Adjacent: []int{2}},
+	{ID: 2, TableNumber: "A2", Capacity: 2, Adjacent: []int{1, 3}},
+	{ID: 3, TableNumber: "A3", Capacity: 4, Adjacent: []int{2, 4}},
+	{ID: 4, TableNumber: "A4", Capacity: 4, Adjacent: []int{3}},
+	{ID: 5, TableNumber: "B1", Capacity: 6},
+}
+
+func TestSuggestTables(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables []models.ReservableTable
+		pax    int
+		want   []int
+		ok     bool
+	}{
+		{"meja terkecil yang cukup", floorTables, 2, []int{1}, true},
+		{"meja tunggal besar", floorTables, 5, []int{5}, true},
+		{"gabungan kursi paling sedikit lalu meja paling sedikit", floorTables, 7, []int{3, 4}, true},
+		{"gabungan tiga meja", floorTables, 9, []int{2, 3, 4}, true},
+		{"melebihi batas gabungan", floorTables, 11, nil, false},
+		{"meja tidak bersebelahan tidak digabung", []models.ReservableTable{floorTables[0], floorTables[3]}, 5, nil, false},
+		{"tidak ada meja", nil, 2, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := suggestTables(tt.tables, tt.pax)
+		if ok != tt.ok || !slices.Equal(got.TableIDs, tt.want) {
+			t.Errorf("%s: suggestTables(%d) = %v, %v, want %v, %v", tt.name, tt.pax, got.TableIDs, ok, tt.want, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		seats := 0
+		for _, id := range got.TableIDs {
+			seats += floorTables[id-1].Capacity
+		}
+		if got.Capacity != seats || got.Capacity < tt.pax || len(got.TableNumbers) != len(got.TableIDs) {
+			t.Errorf("%s: suggestion %+v tidak konsisten", tt.name, got)
+		}
+	}
+}
+
+func TestFreeTables(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.UTC) }
+	bookings := []models.TableBooking{
+		{TableID: 1, Start: at(17, 0), End: at(19, 0)},  // selesai tepat saat mulai
+		{TableID: 3, Start: at(18, 30), End: at(20, 0)}, // bentrok di awal
+		{TableID: 4, Start: at(20, 30), End: at(22, 0)}, // bentrok di akhir
+		{TableID: 5, Start: at(21, 0), End: at(23, 0)},  // mulai tepat saat selesai
+	}
+	var got []int
+	for _, table := range freeTables(floorTables, bookings, at(19, 0), at(21, 0)) {
+		got = append(got, table.ID)
+	}
+	if want := []int{1, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("freeTables = %v, want %v", got, want)
+	}
+}
+
+func TestDiningPeriodFor(t *testing.T) {
+	periods := []models.DiningPeriod{
+		{VisitType: "lunch", FirstSeating: "11:00", LastSeating: "14:30", DurationMinutes: 75},
+		{VisitType: "dinner", FirstSeating: "17:30", LastSeating: "21:30", DurationMinutes: 120},
+	}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		t         time.Time
+		visitType string
+		want      string
+	}{
+		{"awal slot", at(11, 0), "", "lunch"},
+		{"slot terakhir", at(21, 30), "", "dinner"},
+		{"di antara periode", at(16, 0), "", ""},
+		{"visit type menang atas jam", at(12, 0), "dinner", "dinner"},
+		{"visit type tidak dikenal", at(12, 0), "event", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if p := diningPeriodFor(periods, tt.t, tt.visitType); p != nil {
+			got = p.VisitType
+		}
+		if got != tt.want {
+			t.Errorf("%s: diningPeriodFor = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
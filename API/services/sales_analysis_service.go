@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"time"
+)
+
+// Batas rentang tanggal untuk sekali trigger manual
+const maxAnalysisDays = 92
+
+var ErrAnalysisRangeTooLong = errors.New("rentang tanggal maksimal 92 hari")
+
+type SalesAnalysisService struct {
+	repo *repositories.SalesAnalysisRepository
+}
+
+func NewSalesAnalysisService(repo *repositories.SalesAnalysisRepository) *SalesAnalysisService {
+	return &SalesAnalysisService{repo: repo}
+}
+
+// Run menghitung ulang ringkasan harian untuk setiap tanggal dalam rentang [from, to)
+func (s *SalesAnalysisService) Run(ctx context.Context, from, to time.Time) (int, error) {
+	days := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	if days > maxAnalysisDays {
+		return 0, ErrAnalysisRangeTooLong
+	}
+
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if _, err := s.repo.Aggregate(ctx, d); err != nil {
+			return 0, err
+		}
+	}
+	return days, nil
+}
+
+func (s *SalesAnalysisService) ListRange(ctx context.Context, outletID int, from, to time.Time) ([]*models.SalesAnalysisDaily, error) {
+	return s.repo.ListRange(ctx, outletID, from, to)
+}
+
+func (s *SalesAnalysisService) CourseTimes(ctx context.Context, outletID int, from, to time.Time) ([]*models.CourseServiceTime, error) {
+	return s.repo.CourseTimes(ctx, outletID, from, to)
+}
+
+// StartScheduler menjalankan agregasi kemarin dan hari ini setiap interval sampai ctx selesai.
+// Kemarin ikut dihitung ulang agar transaksi yang settle lewat tengah malam tetap tercatat.
+// "Hari ini" diambil dari database agar pergantian hari sama dengan batas hari di Aggregate.
+func (s *SalesAnalysisService) StartScheduler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if today, err := s.repo.Today(ctx); err != nil {
+				log.Printf("Gagal membaca tanggal database untuk agregasi penjualan: %v", err)
+			} else if _, err := s.Run(ctx, today.AddDate(0, 0, -1), today.AddDate(0, 0, 1)); err != nil {
+				log.Printf("Gagal menjalankan agregasi penjualan harian: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
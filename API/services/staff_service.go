@@ -2,10 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 	"pos-restaurant/models"
 	"pos-restaurant/repositories"
+	"regexp"
 )
 
+var ErrInvalidPin = errors.New("pin harus 4-10 digit angka")
+
+var pinPattern = regexp.MustCompile(`^[0-9]{4,10}$`)
+
 type StaffService struct {
 	repo *repositories.StaffRepository
 }
@@ -15,6 +21,12 @@ func NewStaffService(repo *repositories.StaffRepository) *StaffService {
 }
 
 func (s *StaffService) CreateStaff(ctx context.Context, staff *models.Staff) (int, error) {
+	hash, err := HashPin(staff.PinCode)
+	if err != nil {
+		return 0, err
+	}
+	staff.PinCode = hash
+
 	return s.repo.Create(ctx, staff)
 }
 
@@ -26,10 +38,35 @@ func (s *StaffService) GetStaffByID(ctx context.Context, id int) (*models.Staff,
 	return s.repo.GetByID(ctx, id)
 }
 
+// UpdateStaff hanya meng-hash dan mengganti PIN jika PIN baru diisi, PIN kosong mempertahankan PIN lama
 func (s *StaffService) UpdateStaff(ctx context.Context, staff *models.Staff) error {
+	if staff.PinCode != "" {
+		hash, err := HashPin(staff.PinCode)
+		if err != nil {
+			return err
+		}
+		staff.PinCode = hash
+	}
+
 	return s.repo.Update(ctx, staff)
 }
 
+// BootstrapManager membuat manager pertama untuk instalasi baru supaya ada yang bisa login dan menambah staff lain.
+// Tidak melakukan apa-apa jika sudah ada manager aktif. Mengembalikan ID manager baru, atau 0 jika dilewati.
+func (s *StaffService) BootstrapManager(ctx context.Context, name, pin string) (int, error) {
+	if !pinPattern.MatchString(pin) {
+		return 0, ErrInvalidPin
+	}
+	if name == "" {
+		name = "Manager"
+	}
+	hash, err := HashPin(pin)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.CreateFirstManager(ctx, name, hash)
+}
+
 func (s *StaffService) SoftDeleteStaff(ctx context.Context, id int) error {
 	return s.repo.SoftDelete(ctx, id)
 }
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"time"
+)
+
+var ErrInvalidStockMovement = errors.New("movement stok tidak valid")
+
+type StockMovementService struct {
+	repo *repositories.StockMovementRepository
+}
+
+func NewStockMovementService(repo *repositories.StockMovementRepository) *StockMovementService {
+	return &StockMovementService{repo: repo}
+}
+
+// Record mencatat movement manual (receiving, waste, adjustment).
+// Qty untuk receiving dan waste selalu positif, tanda +/- ditentukan dari jenis movement.
+func (s *StockMovementService) Record(ctx context.Context, m *models.StockMovement) (int, error) {
+	switch m.MovementType {
+	case models.StockReceiving:
+		if m.QtyChange <= 0 {
+			return 0, fmt.Errorf("%w: qty receiving harus lebih dari 0", ErrInvalidStockMovement)
+		}
+	case models.StockWaste:
+		if m.QtyChange <= 0 {
+			return 0, fmt.Errorf("%w: qty waste harus lebih dari 0", ErrInvalidStockMovement)
+		}
+		m.QtyChange = -m.QtyChange
+	case models.StockAdjustment:
+		if m.QtyChange == 0 {
+			return 0, fmt.Errorf("%w: qty adjustment tidak boleh 0", ErrInvalidStockMovement)
+		}
+	default:
+		return 0, fmt.Errorf("%w: movement_type %q tidak bisa dicatat manual", ErrInvalidStockMovement, m.MovementType)
+	}
+
+	return s.repo.Create(ctx, m)
+}
+
+func (s *StockMovementService) ListByIngredient(ctx context.Context, ingredientID int, from, to time.Time) ([]*models.StockMovement, error) {
+	return s.repo.ListByIngredient(ctx, ingredientID, from, to)
+}
+
+func (s *StockMovementService) Reconcile(ctx context.Context, ingredientID int) (*models.StockReconciliation, error) {
+	return s.repo.Reconcile(ctx, ingredientID)
+}
@@ -2,19 +2,26 @@ package services
 
 import (
 	"context"
+	"log"
+	"pos-restaurant/events"
 	"pos-restaurant/models"
 	"pos-restaurant/repositories"
 )
 
 type TableService struct {
-	repo *repositories.TableRepository
+	repo   *repositories.TableRepository
+	broker *events.Broker
 }
 
-func NewTableService(repo *repositories.TableRepository) *TableService {
-	return &TableService{repo: repo}
+func NewTableService(repo *repositories.TableRepository, broker *events.Broker) *TableService {
+	return &TableService{repo: repo, broker: broker}
 }
 
+// CreateTable menyimpan meja baru. Status selain out_of_order langsung dihitung dari order dan reservasi.
 func (s *TableService) CreateTable(ctx context.Context, table *models.Table) (int, error) {
+	if table.Status != models.TableOutOfOrder {
+		table.Status = models.TableAvailable
+	}
 	return s.repo.Create(ctx, table)
 }
 
@@ -26,10 +33,97 @@ func (s *TableService) GetTableByID(ctx context.Context, id int) (*models.Table,
 	return s.repo.GetByID(ctx, id)
 }
 
+// UpdateTable menyimpan perubahan meja. Klien hanya bisa menandai out_of_order atau melepasnya, status lain
+// yang dikirim diabaikan dan dihitung ulang dari order dan reservasi. Event dikirim sekali dengan status akhir.
 func (s *TableService) UpdateTable(ctx context.Context, table *models.Table) error {
-	return s.repo.Update(ctx, table)
+	prev, err := s.repo.GetByID(ctx, table.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, table); err != nil {
+		return err
+	}
+
+	status := prev.Status
+	switch {
+	case table.Status == models.TableOutOfOrder:
+		status = models.TableOutOfOrder
+	case table.Status != "" && prev.Status == models.TableOutOfOrder:
+		status = models.TableAvailable
+	}
+	if status != models.TableOutOfOrder {
+		changes, err := s.repo.RefreshStatus(ctx, []int{table.ID})
+		if err != nil {
+			log.Printf("Gagal menghitung ulang status meja %d: %v", table.ID, err)
+		}
+		for _, c := range changes {
+			status = c.To
+		}
+	}
+
+	table.Status = status
+	if status != prev.Status {
+		s.broker.Publish(events.TableStatusChanged, table.OutletID, map[string]any{
+			"table_id": table.ID, "table_number": table.TableNumber, "from": prev.Status, "to": status,
+		})
+	}
+	return nil
 }
 
 func (s *TableService) SoftDeleteTable(ctx context.Context, id int) error {
 	return s.repo.SoftDelete(ctx, id)
 }
+
+// SaveLayout menyimpan posisi meja di denah outlet
+func (s *TableService) SaveLayout(ctx context.Context, outletID int, layout []models.TableLayout) error {
+	return s.repo.SaveLayout(ctx, outletID, layout)
+}
+
+// Floor menyusun tampilan denah outlet beserta order yang sedang berjalan di setiap meja
+func (s *TableService) Floor(ctx context.Context, outletID int, section string) ([]*models.FloorTable, error) {
+	return s.repo.Floor(ctx, outletID, section)
+}
+
+// Refresh menghitung ulang status meja setelah order, bill, transfer atau reservasi berubah dan mengirim
+// event untuk meja yang statusnya berubah. Dipanggil setelah perubahan tersimpan, gagal hanya dicatat di log.
+func (s *TableService) Refresh(ctx context.Context, tableIDs ...int) {
+	ids := tableIDs[:0:0]
+	for _, id := range tableIDs {
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	changes, err := s.repo.RefreshStatus(ctx, ids)
+	if err != nil {
+		log.Printf("Gagal menghitung ulang status meja %v: %v", ids, err)
+		return
+	}
+	for _, c := range changes {
+		s.broker.Publish(events.TableStatusChanged, c.OutletID, map[string]any{
+			"table_id": c.TableID, "table_number": c.TableNumber, "from": c.From, "to": c.To,
+		})
+	}
+}
+
+// RefreshOrder menghitung ulang status meja tempat order berada, termasuk meja yang digabung dengannya
+func (s *TableService) RefreshOrder(ctx context.Context, orderID int) {
+	tableIDs, err := s.orderTables(ctx, orderID)
+	if err != nil {
+		log.Printf("Gagal ambil meja order %d: %v", orderID, err)
+		return
+	}
+	s.Refresh(ctx, tableIDs...)
+}
+
+func (s *TableService) orderTables(ctx context.Context, orderID int) ([]int, error) {
+	return s.repo.OrderTableIDs(ctx, orderID)
+}
+
+// refreshFreed menghitung ulang meja order sumber yang dikosongkan merge, meja bisa langsung reserved
+func (s *TableService) refreshFreed(ctx context.Context, result *models.MergeResult) {
+	ids := make([]int, 0, len(result.FreedTables))
+	for _, t := range result.FreedTables {
+		ids = append(ids, t.TableID)
+	}
+	s.Refresh(ctx, ids...)
+}
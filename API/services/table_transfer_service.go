@@ -2,20 +2,38 @@ package services
 
 import (
 	"context"
+	"log"
+	"pos-restaurant/events"
 	"pos-restaurant/models"
 	"pos-restaurant/repositories"
 )
 
 type TableTransferService struct {
-	repo *repositories.TableTransferRepository
+	repo   *repositories.TableTransferRepository
+	tables *TableService
+	broker *events.Broker
 }
 
-func NewTableTransferService(repo *repositories.TableTransferRepository) *TableTransferService {
-	return &TableTransferService{repo}
+func NewTableTransferService(repo *repositories.TableTransferRepository, tables *TableService, broker *events.Broker) *TableTransferService {
+	return &TableTransferService{repo, tables, broker}
 }
 
 func (s *TableTransferService) Create(ctx context.Context, t *models.TableTransfer) (int, error) {
-	return s.repo.Create(ctx, t)
+	id, err := s.repo.Create(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+	s.tables.Refresh(ctx, t.FromTableID, t.ToTableID)
+
+	outletID, err := s.repo.GetOrderOutletID(ctx, t.OrderID)
+	if err != nil {
+		log.Printf("Gagal ambil outlet order %d untuk event transfer meja: %v", t.OrderID, err)
+		return id, nil
+	}
+	s.broker.Publish(events.TableTransferred, outletID, map[string]any{
+		"transfer_id": id, "order_id": t.OrderID, "from_table_id": t.FromTableID, "to_table_id": t.ToTableID,
+	})
+	return id, nil
 }
 
 func (s *TableTransferService) List(ctx context.Context) ([]models.TableTransfer, error) {
@@ -27,7 +45,11 @@ func (s *TableTransferService) GetByID(ctx context.Context, id int) (*models.Tab
 }
 
 func (s *TableTransferService) Update(ctx context.Context, t *models.TableTransfer) error {
-	return s.repo.Update(ctx, t)
+	if err := s.repo.Update(ctx, t); err != nil {
+		return err
+	}
+	s.tables.Refresh(ctx, t.FromTableID, t.ToTableID)
+	return nil
 }
 
 func (s *TableTransferService) Delete(ctx context.Context, id int) error {
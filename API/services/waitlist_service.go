@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"pos-restaurant/events"
+	"pos-restaurant/models"
+	"pos-restaurant/repositories"
+	"sort"
+	"time"
+)
+
+type WaitlistService struct {
+	repo         *repositories.WaitlistRepository
+	reservations *ReservationService
+	tables       *TableService
+	broker       *events.Broker
+}
+
+func NewWaitlistService(repo *repositories.WaitlistRepository, reservations *ReservationService, tables *TableService, broker *events.Broker) *WaitlistService {
+	return &WaitlistService{repo: repo, reservations: reservations, tables: tables, broker: broker}
+}
+
+// Quote memperkirakan lama tunggu rombongan pax yang baru datang di belakang antrian saat ini
+func (s *WaitlistService) Quote(ctx context.Context, outletID, pax int) (*models.WaitQuote, error) {
+	waiting, err := s.repo.List(ctx, outletID, models.WaitlistWaiting)
+	if err != nil {
+		return nil, err
+	}
+	turn, turns, err := s.turnState(ctx, outletID)
+	if err != nil {
+		return nil, err
+	}
+	ahead := make([]int, 0, len(waiting))
+	for _, e := range waiting {
+		ahead = append(ahead, e.Pax)
+	}
+	minutes, competing := estimateWait(turns, turn, pax, ahead)
+	return &models.WaitQuote{
+		OutletID: outletID, Pax: pax, PartiesAhead: competing, QuotedMinutes: minutes, TurnMinutes: turn,
+	}, nil
+}
+
+// Create mendaftarkan rombongan walk-in ke antrian beserta perkiraan lama tunggu yang disampaikan ke tamu
+func (s *WaitlistService) Create(ctx context.Context, e *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	quote, err := s.Quote(ctx, e.OutletID, e.Pax)
+	if err != nil {
+		return nil, err
+	}
+	e.QuotedMinutes = quote.QuotedMinutes
+	if _, err := s.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+	e.EstimatedMinutes = quote.QuotedMinutes
+
+	s.broker.Publish(events.WaitlistUpdated, e.OutletID, map[string]any{
+		"waitlist_id": e.ID, "status": e.Status, "pax": e.Pax, "quoted_minutes": e.QuotedMinutes,
+	})
+	return e, nil
+}
+
+func (s *WaitlistService) GetByID(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List mengambil antrian outlet. Antrian yang masih waiting dilengkapi posisi dan perkiraan sisa tunggu
+// berdasarkan kondisi meja saat ini.
+func (s *WaitlistService) List(ctx context.Context, outletID int, status string) ([]*models.WaitlistEntry, error) {
+	entries, err := s.repo.List(ctx, outletID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ahead  []int
+		turn   int
+		turns  []models.TableTurn
+		loaded bool
+	)
+	for _, e := range entries {
+		if e.Status != models.WaitlistWaiting {
+			continue
+		}
+		if !loaded {
+			if turn, turns, err = s.turnState(ctx, outletID); err != nil {
+				return nil, err
+			}
+			loaded = true
+		}
+		e.Position = len(ahead) + 1
+		e.EstimatedMinutes, _ = estimateWait(turns, turn, e.Pax, ahead)
+		ahead = append(ahead, e.Pax)
+	}
+	return entries, nil
+}
+
+// Seat memanggil antrian ke meja. Tanpa meja, meja kosong terkecil yang cukup (atau gabungan meja
+// bersebelahan) dipilih otomatis. Kunjungan pelanggan dan order dine-in langsung dibuka.
+func (s *WaitlistService) Seat(ctx context.Context, id int, tableIDs []int, waiterID int) (*models.SeatResult, error) {
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != models.WaitlistWaiting {
+		return nil, fmt.Errorf("%w: antrian %d %s", repositories.ErrWaitlistNotWaiting, id, e.Status)
+	}
+	visitType, duration, err := s.reservations.DiningPeriodNow(ctx, e.OutletID)
+	if err != nil {
+		return nil, err
+	}
+
+	tableIDs = uniqueTableIDs(tableIDs)
+	if len(tableIDs) == 0 {
+		suggestion, err := s.reservations.SuggestNow(ctx, e.OutletID, e.Pax, duration)
+		if err != nil {
+			return nil, err
+		}
+		tableIDs = suggestion.TableIDs
+	}
+
+	result, err := s.repo.Seat(ctx, id, &models.Seating{TableIDs: tableIDs, WaiterID: waiterID, VisitType: visitType})
+	if err != nil {
+		return nil, err
+	}
+
+	s.tables.Refresh(ctx, result.TableIDs...)
+	s.broker.Publish(events.OrderCreated, e.OutletID, map[string]any{
+		"order_id": result.OrderID, "order_number": result.OrderNumber, "table_id": result.TableIDs[0],
+	})
+	s.broker.Publish(events.WaitlistUpdated, e.OutletID, map[string]any{
+		"waitlist_id": id, "status": models.WaitlistSeated, "table_ids": result.TableIDs, "order_id": result.OrderID,
+	})
+	return result, nil
+}
+
+// Remove mengeluarkan antrian: left jika tamu pergi, canceled jika dibatalkan
+func (s *WaitlistService) Remove(ctx context.Context, id int, status string) error {
+	if err := s.repo.Remove(ctx, id, status); err != nil {
+		return err
+	}
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("Gagal ambil antrian %d untuk event: %v", id, err)
+		return nil
+	}
+	s.broker.Publish(events.WaitlistUpdated, e.OutletID, map[string]any{"waitlist_id": id, "status": status})
+	return nil
+}
+
+// turnState mengambil rata-rata lama meja dipakai dan kondisi meja outlet. Outlet tanpa riwayat
+// memakai lama makan periode saat ini.
+func (s *WaitlistService) turnState(ctx context.Context, outletID int) (int, []models.TableTurn, error) {
+	turn, err := s.repo.TurnMinutes(ctx, outletID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if turn <= 0 {
+		_, duration, err := s.reservations.DiningPeriodNow(ctx, outletID)
+		if err != nil {
+			return 0, nil, err
+		}
+		turn = int(duration / time.Minute)
+	}
+	turns, err := s.repo.TableTurns(ctx, outletID, turn)
+	if err != nil {
+		return 0, nil, err
+	}
+	return turn, turns, nil
+}
+
+// estimateWait memperkirakan menit sampai ada meja untuk rombongan pax. Meja yang muat dianggap kosong saat
+// order aktifnya mencapai turn menit atau reservasinya selesai. Rombongan di depan yang tidak muat di meja
+// yang lebih kecil memakai meja yang sama lebih dulu, masing-masing selama satu turn. Rombongan yang lebih
+// besar dari semua meja dihitung kasar dari seluruh meja (perlu digabung). Hasil dibulatkan ke atas per 5 menit,
+// beserta jumlah rombongan di depan yang ikut dihitung.
+func estimateWait(turns []models.TableTurn, turn, pax int, ahead []int) (int, int) {
+	var candidates []models.TableTurn
+	smallMax := 0
+	for _, t := range turns {
+		if t.Capacity >= pax {
+			candidates = append(candidates, t)
+		} else if t.Capacity > smallMax {
+			smallMax = t.Capacity
+		}
+	}
+	if len(candidates) == 0 {
+		candidates, smallMax = turns, 0
+	}
+	if len(candidates) == 0 {
+		return roundUpMinutes(turn), len(ahead)
+	}
+
+	freeAt := make([]int, len(candidates))
+	for i, t := range candidates {
+		if t.OccupiedMinutes.Valid {
+			freeAt[i] = max(freeAt[i], turn-int(t.OccupiedMinutes.Int64))
+		}
+		if t.BookedMinutes.Valid {
+			freeAt[i] = max(freeAt[i], int(t.BookedMinutes.Int64))
+		}
+	}
+
+	competing := 0
+	for _, p := range ahead {
+		if p <= smallMax {
+			continue // bisa duduk di meja yang lebih kecil
+		}
+		competing++
+		sort.Ints(freeAt)
+		freeAt[0] += turn
+	}
+	sort.Ints(freeAt)
+	return roundUpMinutes(freeAt[0]), competing
+}
+
+func roundUpMinutes(m int) int {
+	if m <= 0 {
+		return 0
+	}
+	return (m + 4) / 5 * 5
+}
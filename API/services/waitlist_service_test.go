@@ -0,0 +1,56 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+
+	"pos-restaurant/models"
+)
+
+func minutes(m int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: m, Valid: true}
+}
+
+func TestEstimateWait(t *testing.T) {
+	// Meja 2 kursi kosong dalam 10 menit, meja 4 kursi dalam 40 menit (turn 60 menit)
+	busy := []models.TableTurn{
+		{TableID: 1, Capacity: 2, OccupiedMinutes: minutes(50)},
+		{TableID: 2, Capacity: 4, OccupiedMinutes: minutes(20)},
+	}
+	tests := []struct {
+		name          string
+		turns         []models.TableTurn
+		pax           int
+		ahead         []int
+		wantMinutes   int
+		wantCompeting int
+	}{
+		{"meja kosong", []models.TableTurn{{TableID: 1, Capacity: 4}}, 2, nil, 0, 0},
+		{"hanya meja yang muat", busy, 4, nil, 40, 0},
+		{"meja kecil yang paling cepat kosong", busy, 2, nil, 10, 0},
+		{"rombongan di depan memakai meja lebih dulu", busy, 2, []int{2}, 40, 1},
+		{"rombongan di depan yang muat meja kecil tidak dihitung", busy, 4, []int{2, 4}, 100, 1},
+		{"reservasi menahan meja, dibulatkan per 5 menit", []models.TableTurn{
+			{TableID: 1, Capacity: 4, BookedMinutes: minutes(33)},
+		}, 4, nil, 35, 0},
+		{"order melewati turn dianggap segera kosong", []models.TableTurn{
+			{TableID: 1, Capacity: 4, OccupiedMinutes: minutes(75)},
+		}, 4, nil, 0, 0},
+		{"rombongan lebih besar dari semua meja", busy, 8, nil, 10, 0},
+		{"outlet tanpa meja", nil, 2, []int{2, 3}, 60, 2},
+	}
+	for _, tt := range tests {
+		gotMinutes, gotCompeting := estimateWait(tt.turns, 60, tt.pax, tt.ahead)
+		if gotMinutes != tt.wantMinutes || gotCompeting != tt.wantCompeting {
+			t.Errorf("%s: estimateWait = %d, %d, want %d, %d", tt.name, gotMinutes, gotCompeting, tt.wantMinutes, tt.wantCompeting)
+		}
+	}
+}
+
+func TestRoundUpMinutes(t *testing.T) {
+	for in, want := range map[int]int{-5: 0, 0: 0, 1: 5, 5: 5, 6: 10, 59: 60} {
+		if got := roundUpMinutes(in); got != want {
+			t.Errorf("roundUpMinutes(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
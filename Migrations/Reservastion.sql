@@ -16,22 +16,46 @@ CREATE TABLE customers (
 
 -- Restaurant
 CREATE TYPE status_reservation AS ENUM ('confirmed', 'waiting', 'canceled', 'no_show', 'seated');
+CREATE TYPE visit_type AS ENUM ('breakfast', 'lunch', 'dinner', 'event');
 CREATE TABLE reservations (
     id SERIAL PRIMARY KEY,
     customer_id INT REFERENCES customers(cust_id),
 
     reservation_time TIMESTAMP NOT NULL,
     pax INT NOT NULL,
-    table_id INT REFERENCES tables(id),
+    table_id INT REFERENCES tables(id), -- Meja utama, semua meja (termasuk yang digabung) ada di reservation_tables
+    visit_type visit_type, -- Periode makan, menentukan lama meja dipakai
+    duration_minutes INT NOT NULL DEFAULT 90 CHECK (duration_minutes > 0), -- Meja terpakai sejak reservation_time selama ini
 
     status status_reservation NOT NULL,
     special_request TEXT,
+    seated_at TIMESTAMP, -- Diisi saat status menjadi seated
     
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE TYPE visit_type AS ENUM ('breakfast', 'lunch', 'dinner', 'event');
+-- Meja yang dipakai reservasi, lebih dari satu jika beberapa meja digabung
+CREATE TABLE reservation_tables (
+    reservation_id INT NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    table_id INT NOT NULL REFERENCES tables(id),
+    PRIMARY KEY (reservation_id, table_id)
+);
+CREATE INDEX idx_reservation_tables_table ON reservation_tables(table_id);
+-- Dipakai worker no-show dan penandaan meja reserved
+CREATE INDEX idx_reservations_active ON reservations(reservation_time) WHERE status IN ('confirmed', 'seated');
+
+-- Jam reservasi dan lama makan per outlet dan periode kunjungan
+CREATE TABLE outlet_dining_periods (
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    visit_type visit_type NOT NULL,
+    first_seating TIME NOT NULL, -- Slot reservasi pertama
+    last_seating TIME NOT NULL, -- Slot reservasi terakhir
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    PRIMARY KEY (outlet_id, visit_type),
+    CHECK (last_seating >= first_seating)
+);
+
 CREATE TABLE customer_visits (
     id SERIAL PRIMARY KEY,
     customer_id INT REFERENCES customers(cust_id),
@@ -60,14 +84,51 @@ CREATE TABLE orders (
     hotel_room VARCHAR(20) NULL, -- Untuk charge ke kamar
     waiter_id INT REFERENCES staff(id),
     outlet_id INT REFERENCES outlets(id),
-    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'settled', 'void', 'transferred')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'settled', 'void', 'transferred', 'merged')),
     order_type VARCHAR(20) NOT NULL CHECK (order_type IN ('dine_in', 'takeaway', 'delivery', 'room_service')),
 
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE TYPE status_bill AS ENUM ('open', 'paid', 'partial', 'split', 'void');
+-- Order dine-in yang dibuka saat tamu reservasi didudukkan (kolom ditambahkan setelah tabel orders dibuat)
+ALTER TABLE reservations ADD COLUMN order_id INT REFERENCES orders(id);
+
+CREATE TABLE order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NULL, -- NULL saat order pertama kali dibuat
+    to_status VARCHAR(20) NOT NULL,
+    changed_by INT REFERENCES staff(id),
+    reason TEXT,
+    changed_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Antrian walk-in per outlet. Saat dipanggil (seated) tamu mendapat meja, customer_visits dan order dine-in
+CREATE TABLE waitlist_entries (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    customer_id INT REFERENCES customers(cust_id), -- NULL = tamu baru, dibuatkan customer saat seated
+    guest_name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    pax INT NOT NULL CHECK (pax > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'seated', 'left', 'canceled')),
+    quoted_minutes INT NOT NULL DEFAULT 0, -- Perkiraan tunggu yang disampaikan ke tamu saat mendaftar
+    notes TEXT,
+
+    table_ids INT[] NOT NULL DEFAULT '{}', -- Meja saat seated, lebih dari satu jika digabung
+    visit_id INT REFERENCES customer_visits(id),
+    order_id INT REFERENCES orders(id),
+    created_by INT REFERENCES staff(id),
+
+    created_at TIMESTAMP DEFAULT NOW(),
+    seated_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_waitlist_waiting ON waitlist_entries(outlet_id, created_at) WHERE status = 'waiting';
+CREATE INDEX idx_waitlist_order ON waitlist_entries(order_id);
+
+CREATE TYPE status_bill AS ENUM ('open', 'paid', 'partial', 'split', 'void', 'refunded');
 CREATE TABLE bills (
     id SERIAL PRIMARY KEY,
     bill_number VARCHAR(50) UNIQUE NOT NULL, --UUID
@@ -80,23 +141,88 @@ CREATE TABLE bills (
     tax_amount DECIMAL(12,2) NOT NULL,
     service_charge DECIMAL(12,2) NOT NULL,
     discount_amount DECIMAL(12,2) DEFAULT 0,
+    tax_included DECIMAL(12,2) NOT NULL DEFAULT 0, -- Bagian tax_amount yang sudah ada di harga menu
+    rounding_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
     total_amount DECIMAL(12,2) NOT NULL,
     paid_amount DECIMAL(12,2) DEFAULT 0,
-    balance_due DECIMAL(12,2) GENERATED ALWAYS AS (total_amount - paid_amount) STORED,
+    refunded_amount DECIMAL(12,2) NOT NULL DEFAULT 0, -- Pembayaran yang dikembalikan, mengurangi tagihan bersih
+    balance_due DECIMAL(12,2) GENERATED ALWAYS AS (total_amount - refunded_amount - paid_amount) STORED,
+    tip_amount DECIMAL(12,2) NOT NULL DEFAULT 0, -- Total tip, terpisah dari paid_amount
 
     created_at TIMESTAMP DEFAULT NOW(),
-    updated_at TIMESTAMP DEFAULT NOW()
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (paid_amount + refunded_amount <= total_amount) -- Kelebihan cash jadi kembalian, bukan paid_amount
+);
+
+-- Sesi laci kas (shift kasir): dibuka dengan modal awal, semua pembayaran & refund kasir selama sesi terhubung ke sini
+CREATE TABLE cash_drawer_sessions (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    cashier_id INT NOT NULL REFERENCES staff(id),
+    opening_float DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (opening_float >= 0), -- Modal uang kembalian di laci
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opened_at TIMESTAMP DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    closed_by INT REFERENCES staff(id), -- Kasir sendiri atau manager/supervisor
+    notes TEXT
+);
+-- Satu kasir hanya boleh punya satu sesi terbuka
+CREATE UNIQUE INDEX idx_cash_drawer_open ON cash_drawer_sessions (cashier_id) WHERE status = 'open';
+
+-- Hitung buta saat tutup sesi: kasir mengisi jumlah fisik tanpa melihat angka sistem
+CREATE TABLE cash_drawer_counts (
+    session_id INT NOT NULL REFERENCES cash_drawer_sessions(id) ON DELETE CASCADE,
+    payment_method VARCHAR(50) NOT NULL, -- cash, credit_card, debit_card, voucher
+    counted_amount DECIMAL(12,2) NOT NULL CHECK (counted_amount >= 0),
+    PRIMARY KEY (session_id, payment_method)
 );
 
 CREATE TABLE bill_payments (
     id SERIAL PRIMARY KEY,
     bill_id INT REFERENCES bills(id),
     payment_method VARCHAR(50) NOT NULL CHECK (payment_method IN ('cash', 'credit_card', 'debit_card', 'room_charge', 'voucher', 'split')),
-    amount DECIMAL(12,2) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0), -- Nominal yang mengurangi tagihan
+    tendered_amount DECIMAL(12,2) NOT NULL, -- Uang/nominal yang diterima dari tamu (amount + tip + kembalian)
+    tip_amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (tip_amount >= 0),
+    change_amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (change_amount >= 0), -- Kembalian, hanya untuk cash
     reference_number VARCHAR(100), -- untuk pembayaran room cth: ROOM-401
     room_charge_approved_by INT REFERENCES staff(id),
+    received_by INT REFERENCES staff(id),
+    pms_guest_id VARCHAR(50), -- room_charge: tamu di PMS saat posting
+    pms_posting_id VARCHAR(100), -- room_charge: nomor posting folio di PMS
+    drawer_session_id INT REFERENCES cash_drawer_sessions(id), -- Sesi laci kas kasir penerima
     payment_time TIMESTAMP DEFAULT NOW()
 );
+CREATE INDEX idx_bill_payments_drawer ON bill_payments (drawer_session_id);
+
+-- Refund atas satu pembayaran (sebagian atau penuh), dikembalikan lewat metode pembayaran asalnya
+CREATE TABLE bill_refunds (
+    id SERIAL PRIMARY KEY,
+    bill_id INT NOT NULL REFERENCES bills(id),
+    payment_id INT NOT NULL REFERENCES bill_payments(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    refund_method VARCHAR(50) NOT NULL, -- Sama dengan payment_method asal, room_charge = kredit ke folio kamar
+    reference_number VARCHAR(100),
+    reason TEXT NOT NULL,
+    approved_by INT NOT NULL REFERENCES staff(id), -- Manager yang menyetujui dengan PIN
+    refunded_by INT REFERENCES staff(id),
+    pms_posting_id VARCHAR(100), -- Nomor posting kredit di PMS untuk refund room_charge
+    drawer_session_id INT REFERENCES cash_drawer_sessions(id), -- Sesi laci kas tempat refund dibayarkan
+    is_reversal BOOLEAN NOT NULL DEFAULT FALSE, -- Pembatalan pembayaran bill yang belum lunas: sisa tagihan dibuka lagi, bukan refund penjualan
+    refunded_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_bill_refunds_drawer ON bill_refunds (drawer_session_id);
+
+-- Riwayat cetak struk, copy_no 0 = cetakan pertama, selebihnya ditandai cetak ulang
+CREATE TABLE bill_prints (
+    id SERIAL PRIMARY KEY,
+    bill_id INT NOT NULL REFERENCES bills(id),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('escpos', 'html', 'pdf')),
+    copy_no INT NOT NULL CHECK (copy_no >= 0),
+    printed_by INT REFERENCES staff(id),
+    printed_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (bill_id, copy_no)
+);
 
 CREATE TABLE table_transfers (
     id SERIAL PRIMARY KEY,
@@ -108,13 +234,51 @@ CREATE TABLE table_transfers (
     reason TEXT
 );
 
+-- Jejak audit penggabungan order antar meja, satu baris per order sumber
+CREATE TABLE order_merges (
+    id SERIAL PRIMARY KEY,
+    target_order_id INT NOT NULL REFERENCES orders(id),
+    source_order_id INT NOT NULL REFERENCES orders(id),
+    source_table_id INT NULL REFERENCES tables(id),
+    items_moved INT NOT NULL DEFAULT 0,
+    bill_id INT NULL REFERENCES bills(id), -- Bill gabungan jika merge dilakukan dari bill
+    merged_by INT REFERENCES staff(id),
+    reason TEXT,
+    merged_at TIMESTAMP DEFAULT NOW(),
+    CHECK (target_order_id <> source_order_id)
+);
+
+-- Paket yang dipesan, komponennya disimpan sebagai order_items dengan harga hasil alokasi
+CREATE TABLE order_combos (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    combo_id INT NOT NULL REFERENCES combos(id),
+    name VARCHAR(255) NOT NULL,         -- Snapshot nama paket
+    qty DECIMAL(6,2) NOT NULL CHECK (qty > 0),
+    unit_price DECIMAL(10,2) NOT NULL,  -- Harga paket + upcharge saat dipesan
+    voided_at TIMESTAMP DEFAULT NULL,
+    voided_by INT REFERENCES staff(id),
+    void_reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
 CREATE TABLE order_items (
     id SERIAL PRIMARY KEY,
     order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
     menu_item_id INT NOT NULL REFERENCES menu_items(id),
     qty DECIMAL(6,2) NOT NULL CHECK (qty > 0),
-    unit_price DECIMAL(10,2) NOT NULL,  -- Harga saat dipesan (snapshot)
+    unit_price DECIMAL(10,2) NOT NULL,  -- Harga saat dipesan (snapshot dari menu_items.price)
+    override_price DECIMAL(10,2) NULL CHECK (override_price >= 0), -- Harga khusus, dipakai bill jika diisi
+    override_reason TEXT,
+    override_approved_by INT REFERENCES staff(id), -- Manager/supervisor yang menyetujui
+    modifiers_price DECIMAL(10,2) NOT NULL DEFAULT 0, -- Total price_delta modifier per unit
+    order_combo_id INT NULL REFERENCES order_combos(id) ON DELETE CASCADE, -- Komponen paket, unit_price = alokasi harga paket
+    seat_no INT NULL CHECK (seat_no > 0), -- Nomor kursi tamu, NULL = dimakan bersama (dibagi rata saat split per kursi)
+    course VARCHAR(20) NULL CHECK (course IN ('appetizer', 'main', 'dessert')), -- NULL = tanpa course, langsung ke dapur
     notes TEXT,  -- Contoh: "Pedas level 3, no bawang"
+    voided_at TIMESTAMP DEFAULT NULL, -- Item dibatalkan (sendiri atau ikut void order), stok sudah dikembalikan
+    voided_by INT REFERENCES staff(id),
+    void_reason TEXT,
     created_at TIMESTAMP DEFAULT NOW()
 );
 
@@ -123,4 +287,83 @@ CREATE TABLE order_item_ingredient_excluded (
     order_item_id INT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
     ingredient_id INT NOT NULL REFERENCES ingredients(id),
     created_at TIMESTAMP DEFAULT NOW()
-);
\ No newline at end of file
+);
+-- Modifier yang dipilih per item (snapshot nama & harga saat dipesan)
+CREATE TABLE order_item_modifiers (
+    id SERIAL PRIMARY KEY,
+    order_item_id INT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    modifier_option_id INT NOT NULL REFERENCES modifier_options(id),
+    name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Status course per order: ditahan sampai waiter fire, served saat semua item aktif course sudah disajikan
+CREATE TABLE order_courses (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    course VARCHAR(20) NOT NULL CHECK (course IN ('appetizer', 'main', 'dessert')),
+    fired_at TIMESTAMP, -- NULL = masih ditahan
+    fired_by INT REFERENCES staff(id),
+    served_at TIMESTAMP, -- Di-reset jika item baru masuk ke course yang sudah served
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (order_id, course)
+);
+
+CREATE TYPE status_kitchen AS ENUM ('queued', 'cooking', 'ready', 'served');
+CREATE TABLE kitchen_tickets (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    station_id INT NULL REFERENCES kitchen_stations(id), -- NULL jika kategori belum punya station
+    course VARCHAR(20) NULL, -- Course item di tiket ini, tiket dipisah per station & course
+    fired_at TIMESTAMP DEFAULT NOW(), -- NULL = ditahan (hold), belum masuk antrian dapur sampai di-fire
+    fired_by INT REFERENCES staff(id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE kitchen_ticket_items (
+    id SERIAL PRIMARY KEY,
+    ticket_id INT NOT NULL REFERENCES kitchen_tickets(id) ON DELETE CASCADE,
+    order_item_id INT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    status status_kitchen NOT NULL DEFAULT 'queued',
+    started_at TIMESTAMP,
+    ready_at TIMESTAMP,
+    served_at TIMESTAMP
+);
+
+-- Antrian cetak tiket dapur, diproses spooler dengan retry. Payload dirender saat dicetak.
+CREATE TABLE kitchen_print_jobs (
+    id SERIAL PRIMARY KEY,
+    ticket_id INT NOT NULL REFERENCES kitchen_tickets(id) ON DELETE CASCADE,
+    printer VARCHAR(50) NOT NULL,
+    reprint BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'printing', 'printed', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    claimed_at TIMESTAMP,
+    printed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_kitchen_print_jobs_pending ON kitchen_print_jobs (next_attempt_at) WHERE status = 'pending';
+
+-- Ledger stok bahan (append-only)
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL REFERENCES ingredients(id),
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('opening_balance', 'sale', 'receiving', 'waste', 'adjustment', 'void_reversal')),
+    qty_change DECIMAL(10,2) NOT NULL, -- Negatif = stok keluar
+    order_item_id INT NULL REFERENCES order_items(id),
+    staff_id INT NULL REFERENCES staff(id),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_stock_movements_ingredient ON stock_movements (ingredient_id, created_at);
+
+-- Saldo awal ledger untuk bahan yang sudah punya qty sebelum ledger dipakai, agar qty dan ledger langsung
+-- seimbang. Aman dijalankan ulang: bahan yang sudah punya movement dilewati.
+INSERT INTO stock_movements (ingredient_id, movement_type, qty_change, notes)
+SELECT i.id, 'opening_balance', i.qty, 'Saldo awal saat ledger stok diaktifkan'
+FROM ingredients i
+WHERE i.qty <> 0
+    AND NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.ingredient_id = i.id);
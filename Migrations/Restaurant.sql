@@ -4,6 +4,12 @@ CREATE TABLE outlets (
     location VARCHAR(255),
     service_charge_percentage DECIMAL(5,2) DEFAULT 10,
     tax_percentage DECIMAL(5,2) DEFAULT 10,
+    prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE, -- Harga menu sudah termasuk pajak
+    tax_on_service_charge BOOLEAN NOT NULL DEFAULT TRUE, -- Pajak juga dikenakan atas service charge
+    service_charge_exempt_types TEXT[] NOT NULL DEFAULT '{takeaway,delivery}', -- order_type tanpa service charge
+    rounding_unit DECIMAL(10,2) NOT NULL DEFAULT 0, -- Pembulatan total, contoh 100 (0 = tanpa pembulatan)
+    rounding_mode VARCHAR(10) NOT NULL DEFAULT 'nearest' CHECK (rounding_mode IN ('nearest', 'up', 'down')),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR', -- ISO 4217, menentukan presisi pembulatan pajak & service
     is_active BOOLEAN DEFAULT TRUE,
 
     created_at TIMESTAMP DEFAULT NOW(),
@@ -18,19 +24,33 @@ CREATE TABLE tables (
     table_number VARCHAR(50) NOT NULL,
     capacity INT NOT NULL,
     location_type table_type,
-    status VARCHAR(20) DEFAULT 'available' CHECK (status IN ('available', 'occupied', 'reserved', 'out_of_order')),
+    status VARCHAR(20) DEFAULT 'available' CHECK (status IN ('available', 'occupied', 'reserved', 'out_of_order')), -- Otomatis dari order & reservasi, out_of_order manual
+
+    -- Denah lantai (floor plan)
+    section VARCHAR(50), -- Area meja, contoh: Main Hall, Teras, VIP
+    shape VARCHAR(20) NOT NULL DEFAULT 'square' CHECK (shape IN ('square', 'round', 'rectangle')),
+    pos_x INT NOT NULL DEFAULT 0, -- Posisi di kanvas denah
+    pos_y INT NOT NULL DEFAULT 0,
 
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW(),
     deleted_at TIMESTAMP DEFAULT NULL
 );
 
+-- Pasangan meja bersebelahan yang bisa digabung untuk rombongan besar, disimpan sekali dengan table_id < adjacent_table_id
+CREATE TABLE table_adjacency (
+    table_id INT NOT NULL REFERENCES tables(id),
+    adjacent_table_id INT NOT NULL REFERENCES tables(id),
+    PRIMARY KEY (table_id, adjacent_table_id),
+    CHECK (table_id < adjacent_table_id)
+);
+
 -- Contoh untuk disambungkan ke data dari HRD
 CREATE TABLE staff (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
     role VARCHAR(50) NOT NULL CHECK (role IN ('waiter', 'cashier', 'chef', 'manager', 'supervisor')),
-    pin_code VARCHAR(10) NOT NULL, -- Untuk login POS
+    pin_code VARCHAR(255) NOT NULL, -- Hash bcrypt dari PIN untuk login POS
     is_active BOOLEAN DEFAULT TRUE,
 
     created_at TIMESTAMP DEFAULT NOW(),
@@ -38,14 +58,53 @@ CREATE TABLE staff (
     deleted_at TIMESTAMP DEFAULT NULL
 );
 
+-- Database lama: kolom pin_code dulu VARCHAR(10) berisi PIN plaintext. Perlebar kolomnya lalu hash ulang PIN
+-- plaintext dengan bcrypt dari pgcrypto (kompatibel dengan golang.org/x/crypto/bcrypt). Aman dijalankan ulang:
+-- PIN yang sudah berupa hash bcrypt ($2a$/$2b$/$2y$) dilewati.
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+ALTER TABLE staff ALTER COLUMN pin_code TYPE VARCHAR(255);
+UPDATE staff SET pin_code = crypt(pin_code, gen_salt('bf', 10)), updated_at = NOW()
+WHERE pin_code !~ '^\$2[aby]\$';
+
+-- Jejak persetujuan PIN manager/supervisor (override harga, refund) yang gagal, untuk audit percobaan menebak PIN
+CREATE TABLE approval_failures (
+    id SERIAL PRIMARY KEY,
+    approver_id INT NOT NULL, -- Staff yang PIN-nya dicoba, tanpa FK karena ID yang dicoba bisa tidak ada
+    requested_by INT REFERENCES staff(id), -- Staff yang login dan meminta persetujuan
+    action VARCHAR(50) NOT NULL CHECK (action IN ('price_override', 'refund')),
+    reason VARCHAR(50) NOT NULL CHECK (reason IN ('unknown_staff', 'inactive', 'role', 'wrong_pin', 'locked')),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_approval_failures_approver ON approval_failures (approver_id, created_at);
+
+-- Kitchen Display System
+CREATE TABLE kitchen_stations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL, -- Kitchen, Bar, Pastry
+    printer VARCHAR(50), -- Nama printer di POS_PRINTERS untuk tiket kertas, NULL = hanya KDS
+    printer_width INT NOT NULL DEFAULT 42 CHECK (printer_width BETWEEN 32 AND 64), -- Karakter per baris (32 = 58mm, 42 = 80mm)
+    created_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
 -- Menu
 CREATE TABLE menu_categories (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
+    station_id INT REFERENCES kitchen_stations(id), -- Routing tiket dapur per kategori
     created_at TIMESTAMP DEFAULT NOW(),
     deleted_at TIMESTAMP DEFAULT NULL
 );
 
+-- Tarif pajak khusus per kategori di outlet tertentu, menggantikan outlets.tax_percentage
+CREATE TABLE category_tax_rates (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    category_id INT NOT NULL REFERENCES menu_categories(id),
+    tax_percentage DECIMAL(5,2) NOT NULL CHECK (tax_percentage >= 0),
+    UNIQUE (outlet_id, category_id)
+);
+
 CREATE TABLE ingredients (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL UNIQUE,
@@ -92,15 +151,74 @@ CREATE TABLE menu_ingredients (
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
--- CREATE TABLE sales_analysis_daily (
---     id SERIAL PRIMARY KEY,
---     outlet_id INT REFERENCES outlets(id),
---     analysis_date DATE NOT NULL,
---     total_sales DECIMAL(12,2) NOT NULL,
---     total_covers INT NOT NULL, -- Jumlah tamu
---     avg_spend_per_cover DECIMAL(10,2) NOT NULL,
---     discount_amount DECIMAL(12,2) NOT NULL,
---     void_amount DECIMAL(12,2) NOT NULL, -- Total transaksi batal
---     created_at TIMESTAMP DEFAULT NOW(),
---     UNIQUE(outlet_id, analysis_date)
--- );
+-- Modifier menu (ukuran, kematangan, extra topping)
+CREATE TABLE modifier_groups (
+    id SERIAL PRIMARY KEY,
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,          -- Contoh: "Ukuran", "Kematangan", "Extra Topping"
+    min_select INT NOT NULL DEFAULT 0,   -- 1 = wajib pilih
+    max_select INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CHECK (min_select >= 0 AND max_select >= min_select AND max_select > 0)
+);
+
+CREATE TABLE modifier_options (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0,      -- Tambahan harga (boleh negatif)
+    ingredient_id INT NULL REFERENCES ingredients(id), -- Bahan tambahan yang dipakai (extra)
+    ingredient_qty DECIMAL(10,2) NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+-- Paket / set menu (harga tetap untuk beberapa menu)
+CREATE TABLE combos (
+    id SERIAL PRIMARY KEY,
+    sku VARCHAR(50) UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(10,2) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+-- Slot paket, contoh: "Main", "Drink", "Dessert". Slot dengan satu pilihan = komponen tetap
+CREATE TABLE combo_slots (
+    id SERIAL PRIMARY KEY,
+    combo_id INT NOT NULL REFERENCES combos(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE combo_slot_options (
+    id SERIAL PRIMARY KEY,
+    slot_id INT NOT NULL REFERENCES combo_slots(id) ON DELETE CASCADE,
+    menu_item_id INT NOT NULL REFERENCES menu_items(id),
+    price_delta DECIMAL(10,2) NOT NULL DEFAULT 0, -- Upcharge, contoh: ganti ke jus +5000
+    is_default BOOLEAN DEFAULT FALSE,
+    UNIQUE (slot_id, menu_item_id)
+);
+
+-- Diisi oleh job agregasi harian (upsert per outlet & tanggal)
+CREATE TABLE sales_analysis_daily (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT REFERENCES outlets(id),
+    analysis_date DATE NOT NULL,
+    total_sales DECIMAL(12,2) NOT NULL, -- Subtotal bill terbayar (sebelum diskon, pajak, service charge)
+    total_covers INT NOT NULL, -- Jumlah tamu
+    avg_spend_per_cover DECIMAL(10,2) NOT NULL,
+    discount_amount DECIMAL(12,2) NOT NULL,
+    void_amount DECIMAL(12,2) NOT NULL, -- Nilai item yang di-void, dasar yang sama dengan total_sales
+    refund_amount DECIMAL(12,2) NOT NULL DEFAULT 0, -- Total refund pembayaran, terpisah dari void
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(outlet_id, analysis_date)
+);